@@ -0,0 +1,377 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// AddressPlanFanOut is a kind of locality Subnets of a template are created for
+type AddressPlanFanOut string
+
+const (
+	// RegionAddressPlanFanOut creates a Subnet for each region
+	RegionAddressPlanFanOut AddressPlanFanOut = "Region"
+	// AvailabilityZoneAddressPlanFanOut creates a Subnet for each availability zone of each region
+	AvailabilityZoneAddressPlanFanOut AddressPlanFanOut = "AvailabilityZone"
+)
+
+// AddressPlanApproval is a way changes of the plan are approved
+type AddressPlanApproval string
+
+const (
+	// ManualAddressPlanApproval applies the revision of the plan set in spec.approvedRevision only
+	ManualAddressPlanApproval AddressPlanApproval = "Manual"
+	// AutomaticAddressPlanApproval applies any revision of the plan
+	AutomaticAddressPlanApproval AddressPlanApproval = "Automatic"
+)
+
+// AddressPlanNetwork is a template of the Network of the plan
+type AddressPlanNetwork struct {
+	// Name is the name of the Network, the plan name by default
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
+	// Labels are labels of the Network
+	// +kubebuilder:validation:Optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Spec is the desired state of the Network
+	// +kubebuilder:validation:Optional
+	Spec NetworkSpec `json:"spec,omitempty"`
+}
+
+// AddressPlanSubnet is a template of Subnets of the plan.
+// A template renders a Subnet for each Subnet of the parent template, or for each region or availability zone, if fanned out.
+// Subnets are named <parent subnet name>-<template name>, or <plan name>-<template name> on top level,
+// suffixed with the region and availability zone names if fanned out.
+type AddressPlanSubnet struct {
+	// Name identifies the template in the plan
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+	// Parent is the name of the parent template, it should be declared before the template.
+	// Templates without parent render top level Subnets.
+	// +kubebuilder:validation:Optional
+	Parent string `json:"parent,omitempty"`
+	// CIDR represents the IP Address Range, it is required for top level Subnets
+	// +kubebuilder:validation:Optional
+	CIDR *CIDR `json:"cidr,omitempty"`
+	// PrefixBits is an amount of ones zero bits at the beginning of the netmask
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=128
+	PrefixBits *byte `json:"prefixBits,omitempty"`
+	// Capacity is a desired amount of addresses; will be ceiled to the closest power of 2.
+	// +kubebuilder:validation:Optional
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+	// FanOut renders a Subnet for each region or availability zone instead of a single Subnet
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Region;AvailabilityZone
+	FanOut AddressPlanFanOut `json:"fanOut,omitempty"`
+	// Regions represents the network service location, regions of the parent Subnet are inherited if not set
+	// +kubebuilder:validation:Optional
+	Regions []Region `json:"regions,omitempty"`
+	// Labels are labels of the Subnets
+	// +kubebuilder:validation:Optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// AddressPlanSpec defines the desired state of AddressPlan
+type AddressPlanSpec struct {
+	// Network is the Network Subnets of the plan belong to
+	// +kubebuilder:validation:Optional
+	Network AddressPlanNetwork `json:"network,omitempty"`
+	// Subnets are templates of Subnets of the plan
+	// +kubebuilder:validation:Optional
+	Subnets []AddressPlanSubnet `json:"subnets,omitempty"`
+	// Approval is a way changes of the plan are approved
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Manual;Automatic
+	// +kubebuilder:default=Manual
+	Approval AddressPlanApproval `json:"approval,omitempty"`
+	// ApprovedRevision is the revision of the plan, which may be applied with Manual approval
+	// +kubebuilder:validation:Optional
+	ApprovedRevision string `json:"approvedRevision,omitempty"`
+}
+
+// AddressPlanChangeAction is an action taken on an object to bring it in sync with the plan
+type AddressPlanChangeAction string
+
+const (
+	CreateAddressPlanChangeAction   AddressPlanChangeAction = "Create"
+	UpdateAddressPlanChangeAction   AddressPlanChangeAction = "Update"
+	RecreateAddressPlanChangeAction AddressPlanChangeAction = "Recreate"
+	DeleteAddressPlanChangeAction   AddressPlanChangeAction = "Delete"
+)
+
+// AddressPlanChange is a change of an object needed to bring it in sync with the plan
+type AddressPlanChange struct {
+	// Action is an action taken on the object
+	Action AddressPlanChangeAction `json:"action"`
+	// Kind is the kind of the object, Network or Subnet
+	Kind string `json:"kind"`
+	// Name is the name of the object
+	Name string `json:"name"`
+	// Details describes the change
+	Details string `json:"details,omitempty"`
+	// Blocked is set if the change can not be applied, since the object is in use
+	Blocked bool `json:"blocked,omitempty"`
+}
+
+// AddressPlanState is a processing state of AddressPlan resource
+type AddressPlanState string
+
+const (
+	// PlannedAddressPlanState means changes of the plan are awaiting approval
+	PlannedAddressPlanState AddressPlanState = "Planned"
+	// ApplyingAddressPlanState means changes of the plan are being applied
+	ApplyingAddressPlanState AddressPlanState = "Applying"
+	// SyncedAddressPlanState means objects are in sync with the plan
+	SyncedAddressPlanState AddressPlanState = "Synced"
+	// BlockedAddressPlanState means the remaining changes are blocked by objects in use
+	BlockedAddressPlanState AddressPlanState = "Blocked"
+	// FailedAddressPlanState means the plan is invalid or can not be applied
+	FailedAddressPlanState AddressPlanState = "Failed"
+)
+
+// AddressPlanStatus defines the observed state of AddressPlan
+type AddressPlanStatus struct {
+	// State is a processing state of the plan
+	State AddressPlanState `json:"state,omitempty"`
+	// Message contains an error string or describes the current step of applying the plan
+	Message string `json:"message,omitempty"`
+	// Revision identifies the objects described by the plan
+	Revision string `json:"revision,omitempty"`
+	// AppliedRevision is the last revision objects have been synced with
+	AppliedRevision string `json:"appliedRevision,omitempty"`
+	// ObservedGeneration is the generation of the plan the status has been computed for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Changes are changes needed to bring objects in sync with the plan
+	Changes []AddressPlanChange `json:"changes,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=addressplans,singular=addressplan
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`,description="Processing state"
+// +kubebuilder:printcolumn:name="Revision",type=string,JSONPath=`.status.revision`,description="Revision of the plan"
+// +kubebuilder:printcolumn:name="Approved Revision",type=string,JSONPath=`.spec.approvedRevision`,description="Approved revision of the plan"
+// +kubebuilder:printcolumn:name="Applied Revision",type=string,JSONPath=`.status.appliedRevision`,description="Last applied revision of the plan"
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.message`,description="Message"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AddressPlan is the Schema for the addressplans API
+type AddressPlan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AddressPlanSpec   `json:"spec,omitempty"`
+	Status AddressPlanStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AddressPlanList contains a list of AddressPlan
+type AddressPlanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AddressPlan `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(func(s *runtime.Scheme) error {
+		s.AddKnownTypes(SchemeGroupVersion, &AddressPlan{}, &AddressPlanList{})
+		return nil
+	})
+}
+
+// Approves checks whether the revision of the plan may be applied.
+func (in *AddressPlan) Approves(revision string) bool {
+	return in.Spec.Approval == AutomaticAddressPlanApproval || in.Spec.ApprovedRevision == revision
+}
+
+// NetworkName returns the name of the Network of the plan.
+func (in *AddressPlan) NetworkName() string {
+	if in.Spec.Network.Name != "" {
+		return in.Spec.Network.Name
+	}
+	return in.Name
+}
+
+// Render builds the Network and Subnets described by the plan, labeled with AddressPlanLabel.
+// Subnets are ordered as they should be created, i.e. in the order of templates, parents first.
+func (in *AddressPlan) Render() (*Network, []Subnet, error) {
+	network := &Network{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      in.NetworkName(),
+			Namespace: in.Namespace,
+			Labels:    in.labels(in.Spec.Network.Labels),
+		},
+		Spec: *in.Spec.Network.Spec.DeepCopy(),
+	}
+
+	var subnets []Subnet
+	rendered := make(map[string][]Subnet, len(in.Spec.Subnets))
+	// Paths of templates rendering subnets, mapped by subnet name
+	names := make(map[string]string)
+	for i := range in.Spec.Subnets {
+		template := &in.Spec.Subnets[i]
+		path := fmt.Sprintf("spec.subnets[%d]", i)
+		if _, ok := rendered[template.Name]; ok {
+			return nil, nil, errors.Errorf("%s: template %s is declared more than once", path, template.Name)
+		}
+
+		requests := 0
+		for _, set := range []bool{template.CIDR != nil, template.PrefixBits != nil, template.Capacity != nil} {
+			if set {
+				requests++
+			}
+		}
+		if requests != 1 {
+			return nil, nil, errors.Errorf("%s: exactly one of cidr, prefixBits and capacity should be set", path)
+		}
+
+		// Top level subnets are rendered once, without a parent
+		parents := []*Subnet{nil}
+		if template.Parent == "" {
+			if template.CIDR == nil {
+				return nil, nil, errors.Errorf("%s: cidr should be set for top level subnets", path)
+			}
+		} else {
+			parentSubnets, ok := rendered[template.Parent]
+			if !ok {
+				return nil, nil, errors.Errorf("%s: parent template %s should be declared before the template", path, template.Parent)
+			}
+			parents = make([]*Subnet, len(parentSubnets))
+			for j := range parentSubnets {
+				parents[j] = &parentSubnets[j]
+			}
+		}
+
+		var templateSubnets []Subnet
+		for _, parent := range parents {
+			prefix := in.Name
+			regions := template.Regions
+			if parent != nil {
+				prefix = parent.Name
+				if len(regions) == 0 {
+					regions = parent.Spec.Regions
+				}
+			}
+
+			localities, err := fanOutLocalities(template.FanOut, regions)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, path)
+			}
+			for _, locality := range localities {
+				subnet := Subnet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      prefix + "-" + template.Name + locality.suffix,
+						Namespace: in.Namespace,
+						Labels:    in.labels(template.Labels),
+					},
+					Spec: SubnetSpec{
+						CIDR:    template.CIDR.DeepCopy(),
+						Network: v1.LocalObjectReference{Name: network.Name},
+						Regions: locality.regions,
+					},
+				}
+				if template.PrefixBits != nil {
+					prefixBits := *template.PrefixBits
+					subnet.Spec.PrefixBits = &prefixBits
+				}
+				if template.Capacity != nil {
+					capacity := template.Capacity.DeepCopy()
+					subnet.Spec.Capacity = &capacity
+				}
+				if parent != nil {
+					subnet.Spec.ParentSubnet = SubnetReference{Name: parent.Name}
+				}
+				for _, msg := range validation.IsDNS1123Subdomain(subnet.Name) {
+					return nil, nil, errors.Errorf("%s: invalid subnet name %s: %s", path, subnet.Name, msg)
+				}
+				if other, ok := names[subnet.Name]; ok {
+					return nil, nil, errors.Errorf("%s: subnet name %s is already rendered by %s", path, subnet.Name, other)
+				}
+				names[subnet.Name] = path
+				templateSubnets = append(templateSubnets, subnet)
+			}
+		}
+		if template.CIDR != nil && len(templateSubnets) > 1 {
+			return nil, nil, errors.Errorf("%s: cidr may be set only for templates rendering a single subnet", path)
+		}
+
+		rendered[template.Name] = templateSubnets
+		subnets = append(subnets, templateSubnets...)
+	}
+
+	return network, subnets, nil
+}
+
+func (in *AddressPlan) labels(templateLabels map[string]string) map[string]string {
+	labels := make(map[string]string, len(templateLabels)+1)
+	for key, value := range templateLabels {
+		labels[key] = value
+	}
+	labels[AddressPlanLabel] = in.Name
+	return labels
+}
+
+type addressPlanLocality struct {
+	suffix  string
+	regions []Region
+}
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// fanOutLocalities returns localities Subnets of a template are rendered for.
+func fanOutLocalities(fanOut AddressPlanFanOut, regions []Region) ([]addressPlanLocality, error) {
+	nameSuffix := func(names ...string) string {
+		var suffix string
+		for _, name := range names {
+			suffix += "-" + invalidNameCharacters.ReplaceAllString(strings.ToLower(name), "-")
+		}
+		return suffix
+	}
+
+	switch fanOut {
+	case "":
+		return []addressPlanLocality{{regions: regions}}, nil
+	case RegionAddressPlanFanOut, AvailabilityZoneAddressPlanFanOut:
+		if len(regions) == 0 {
+			return nil, errors.New("regions should be set or inherited from the parent to fan out subnets")
+		}
+	default:
+		return nil, errors.Errorf("unknown fan out %s", fanOut)
+	}
+
+	var localities []addressPlanLocality
+	for _, region := range regions {
+		if fanOut == RegionAddressPlanFanOut {
+			localities = append(localities, addressPlanLocality{
+				suffix:  nameSuffix(region.Name),
+				regions: []Region{*region.DeepCopy()},
+			})
+			continue
+		}
+		for _, zone := range region.AvailabilityZones {
+			localities = append(localities, addressPlanLocality{
+				suffix:  nameSuffix(region.Name, zone),
+				regions: []Region{{Name: region.Name, AvailabilityZones: []string{zone}}},
+			})
+		}
+	}
+	return localities, nil
+}
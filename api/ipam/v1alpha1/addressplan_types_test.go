@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("Address plan", func() {
+	newPlan := func(subnets ...AddressPlanSubnet) *AddressPlan {
+		return &AddressPlan{
+			ObjectMeta: metav1.ObjectMeta{Name: "eu", Namespace: "default"},
+			Spec:       AddressPlanSpec{Subnets: subnets},
+		}
+	}
+
+	It("Should render subnets fanned out to regions and availability zones", func() {
+		plan := newPlan(
+			AddressPlanSubnet{
+				Name: "dc",
+				CIDR: CidrMustParse("10.0.0.0/16"),
+				Regions: []Region{
+					{Name: "euw", AvailabilityZones: []string{"a", "b"}},
+					{Name: "eun", AvailabilityZones: []string{"a"}},
+				},
+			},
+			AddressPlanSubnet{Name: "region", Parent: "dc", PrefixBits: ptr.To[byte](18), FanOut: RegionAddressPlanFanOut},
+			AddressPlanSubnet{
+				Name:       "rack",
+				Parent:     "region",
+				PrefixBits: ptr.To[byte](24),
+				FanOut:     AvailabilityZoneAddressPlanFanOut,
+				Labels:     map[string]string{"role": "rack"},
+			},
+		)
+		plan.Spec.Network.Name = "eu-network"
+
+		network, subnets, err := plan.Render()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(network.Name).To(Equal("eu-network"))
+		Expect(network.Labels).To(Equal(map[string]string{AddressPlanLabel: "eu"}))
+
+		names := make([]string, len(subnets))
+		for i := range subnets {
+			names[i] = subnets[i].Name
+			Expect(subnets[i].Spec.Network.Name).To(Equal("eu-network"))
+		}
+		Expect(names).To(Equal([]string{
+			"eu-dc",
+			"eu-dc-region-euw",
+			"eu-dc-region-eun",
+			"eu-dc-region-euw-rack-euw-a",
+			"eu-dc-region-euw-rack-euw-b",
+			"eu-dc-region-eun-rack-eun-a",
+		}))
+
+		Expect(subnets[0].Spec.CIDR).To(Equal(CidrMustParse("10.0.0.0/16")))
+		Expect(subnets[0].Spec.ParentSubnet.Name).To(BeEmpty())
+		Expect(subnets[2].Spec.ParentSubnet.Name).To(Equal("eu-dc"))
+		Expect(subnets[2].Spec.Regions).To(Equal([]Region{{Name: "eun", AvailabilityZones: []string{"a"}}}))
+		Expect(subnets[4].Spec.ParentSubnet.Name).To(Equal("eu-dc-region-euw"))
+		Expect(subnets[4].Spec.PrefixBits).To(Equal(ptr.To[byte](24)))
+		Expect(subnets[4].Spec.Regions).To(Equal([]Region{{Name: "euw", AvailabilityZones: []string{"b"}}}))
+		Expect(subnets[4].Labels).To(Equal(map[string]string{AddressPlanLabel: "eu", "role": "rack"}))
+	})
+
+	It("Should refuse invalid templates", func() {
+		capacity := resource.MustParse("256")
+		plans := []*AddressPlan{
+			newPlan(AddressPlanSubnet{Name: "dc", PrefixBits: ptr.To[byte](16)}),
+			newPlan(AddressPlanSubnet{Name: "dc", CIDR: CidrMustParse("10.0.0.0/16"), Capacity: &capacity}),
+			newPlan(
+				AddressPlanSubnet{Name: "rack", Parent: "dc", PrefixBits: ptr.To[byte](24)},
+				AddressPlanSubnet{Name: "dc", CIDR: CidrMustParse("10.0.0.0/16")},
+			),
+			newPlan(
+				AddressPlanSubnet{Name: "dc", CIDR: CidrMustParse("10.0.0.0/16")},
+				AddressPlanSubnet{Name: "dc", CIDR: CidrMustParse("10.1.0.0/16")},
+			),
+			newPlan(
+				AddressPlanSubnet{Name: "dc", CIDR: CidrMustParse("10.0.0.0/16")},
+				AddressPlanSubnet{Name: "rack", Parent: "dc", PrefixBits: ptr.To[byte](24), FanOut: RegionAddressPlanFanOut},
+			),
+			newPlan(
+				AddressPlanSubnet{Name: "dc", CIDR: CidrMustParse("10.0.0.0/16"), Regions: []Region{
+					{Name: "euw", AvailabilityZones: []string{"a", "b"}},
+				}},
+				AddressPlanSubnet{Name: "rack", Parent: "dc", CIDR: CidrMustParse("10.0.0.0/24"), FanOut: AvailabilityZoneAddressPlanFanOut},
+			),
+			// eu-dc-rack is rendered by both templates
+			newPlan(
+				AddressPlanSubnet{Name: "dc", CIDR: CidrMustParse("10.0.0.0/16")},
+				AddressPlanSubnet{Name: "rack", Parent: "dc", PrefixBits: ptr.To[byte](24)},
+				AddressPlanSubnet{Name: "dc-rack", CIDR: CidrMustParse("10.1.0.0/16")},
+			),
+		}
+
+		for _, plan := range plans {
+			_, _, err := plan.Render()
+			Expect(err).To(HaveOccurred(), "subnets %+v", plan.Spec.Subnets)
+		}
+	})
+})
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"strings"
+)
+
+const (
+	// ReconcilePausedAnnotation stops controllers from processing a resource while it is set to "true".
+	// Deletion of the resource is still handled.
+	ReconcilePausedAnnotation = "ipam.metal.ironcore.dev/reconcile-paused"
+)
+
+// IsReconcilePaused checks whether reconciliation has been paused for the resource annotations
+func IsReconcilePaused(annotations map[string]string) bool {
+	return annotations[ReconcilePausedAnnotation] == "true"
+}
+
+const (
+	// SubnetAnnotation requests an IP for the annotated resource from the Subnet.
+	// The value is a Subnet name, or namespace/name for a Subnet of another namespace.
+	SubnetAnnotation = "ipam.metal.ironcore.dev/subnet"
+	// PoolAnnotation requests an IP for the annotated resource from any Subnet of the pool.
+	// Subnets are assigned to the pool by PoolLabel.
+	PoolAnnotation = "ipam.metal.ironcore.dev/pool"
+	// PoolLabel assigns a Subnet to a pool, IPs requested by PoolAnnotation are allocated from it.
+	PoolLabel = "ipam.metal.ironcore.dev/pool"
+	// IPAnnotation contains the IP address reserved for the annotated resource.
+	IPAnnotation = "ipam.metal.ironcore.dev/ip"
+	// InterfaceAnnotation contains the name of the consumer network interface the annotated IP is configured on.
+	InterfaceAnnotation = "ipam.metal.ironcore.dev/interface"
+	// MACAddressAnnotation contains the hardware address of the annotated IP, or of the annotated consumer,
+	// static DHCP host reservations are exported for IPs with a hardware address.
+	MACAddressAnnotation = "ipam.metal.ironcore.dev/mac-address"
+)
+
+// SubnetReferenceFromAnnotation parses SubnetAnnotation value into a Subnet reference.
+func SubnetReferenceFromAnnotation(value string) SubnetReference {
+	namespace, name, found := strings.Cut(value, "/")
+	if !found {
+		return SubnetReference{Name: value}
+	}
+	return SubnetReference{Namespace: namespace, Name: name}
+}
+
+// LoadBalancerIPsAnnotation requests particular addresses for a LoadBalancer Service,
+// the value is a comma separated list with at most one address per IP family.
+const LoadBalancerIPsAnnotation = "ipam.metal.ironcore.dev/load-balancer-ips"
+
+const (
+	// NodeLabel marks a Subnet allocated as pod CIDR of the Node, the value is the Node name,
+	// truncated and suffixed with its hash if it exceeds the label value length limit.
+	NodeLabel = "ipam.metal.ironcore.dev/node"
+	// NodeSelectorAnnotation restricts a pod CIDR pool Subnet to Nodes matching the label selector,
+	// e.g. "topology.kubernetes.io/zone=zone-a". Subnets without it serve any Node.
+	NodeSelectorAnnotation = "ipam.metal.ironcore.dev/node-selector"
+)
+
+const (
+	// DHCPExportLabel marks a ConfigMap the DHCP server configuration of Subnets of its namespace is written to,
+	// the value is the configuration format, "kea" or "dnsmasq".
+	DHCPExportLabel = "ipam.metal.ironcore.dev/dhcp-export"
+	// DHCPSubnetSelectorAnnotation restricts Subnets exported to the ConfigMap to ones matching the label selector.
+	DHCPSubnetSelectorAnnotation = "ipam.metal.ironcore.dev/dhcp-subnet-selector"
+)
+
+const (
+	// DNSZoneFilesLabel marks a ConfigMap of zone files of a Subnet, the value is the Subnet name.
+	DNSZoneFilesLabel = "ipam.metal.ironcore.dev/dns-zone-files"
+	// DNSZoneSerialAnnotation holds the serial of reverse zones in ConfigMaps of zone files of a Subnet,
+	// it is increased once the zone files change.
+	DNSZoneSerialAnnotation = "ipam.metal.ironcore.dev/dns-zone-serial"
+)
+
+const (
+	// SplitParentLabel marks a child Subnet created by the split of a Subnet, the value is the split Subnet name.
+	SplitParentLabel = "ipam.metal.ironcore.dev/split-parent"
+	// SplitIndexLabel contains the index of a child Subnet in the split, children are allocated in address order.
+	SplitIndexLabel = "ipam.metal.ironcore.dev/split-index"
+)
+
+const (
+	// AddressPlanLabel marks a Network or Subnet managed by an AddressPlan, the value is the plan name.
+	AddressPlanLabel = "ipam.metal.ironcore.dev/address-plan"
+	// AddressPlanHashAnnotation contains the hash of labels and spec of a Network or Subnet rendered by an AddressPlan,
+	// so changes of the plan are detected regardless of defaults set by the API server.
+	AddressPlanHashAnnotation = "ipam.metal.ironcore.dev/address-plan-hash"
+)
@@ -359,3 +359,36 @@ var _ = Describe("CIDR operations", func() {
 		})
 	})
 })
+
+var _ = Describe("CIDR summarization", func() {
+	summarize := func(maxAggregates int, cidrs ...string) []string {
+		parsed := make([]CIDR, 0, len(cidrs))
+		for _, cidr := range cidrs {
+			parsed = append(parsed, *CidrMustParse(cidr))
+		}
+		var aggregates []string
+		for _, aggregate := range SummarizeCIDRs(parsed, maxAggregates) {
+			aggregates = append(aggregates, aggregate.String())
+		}
+		return aggregates
+	}
+
+	It("Should merge contained and adjacent CIDRs", func() {
+		Expect(summarize(0, "10.0.1.0/24", "10.0.0.0/24", "10.0.0.128/25", "10.0.2.0/23", "10.1.0.0/24")).
+			To(Equal([]string{"10.0.0.0/22", "10.1.0.0/24"}))
+		Expect(summarize(0, "10.0.1.0/24", "10.0.2.0/24")).
+			To(Equal([]string{"10.0.1.0/24", "10.0.2.0/24"}))
+		Expect(summarize(0, "fd00::/64", "10.0.0.0/25", "fd00:0:0:1::/64", "10.0.0.128/25")).
+			To(Equal([]string{"10.0.0.0/24", "fd00::/63"}))
+		Expect(summarize(0)).To(BeEmpty())
+	})
+
+	It("Should limit the number of aggregates by over-coverage", func() {
+		Expect(summarize(2, "10.0.0.0/24", "10.0.2.0/24", "10.0.8.0/24")).
+			To(Equal([]string{"10.0.0.0/22", "10.0.8.0/24"}))
+		Expect(summarize(1, "10.0.0.0/24", "10.0.2.0/24", "10.0.8.0/24")).
+			To(Equal([]string{"10.0.0.0/20"}))
+		Expect(summarize(1, "10.0.0.0/24", "fd00::/64", "fd00:0:0:2::/64")).
+			To(Equal([]string{"10.0.0.0/24", "fd00::/62"}))
+	})
+})
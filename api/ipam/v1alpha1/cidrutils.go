@@ -4,7 +4,9 @@
 package v1alpha1
 
 import (
+	"math/big"
 	"net/netip"
+	"slices"
 )
 
 func IPMustParse(ipString string) *IPAddr {
@@ -86,3 +88,99 @@ func CIDRFromString(cidrString string) (*CIDR, error) {
 		Net: cidr,
 	}, nil
 }
+
+// SummarizeCIDRs merges contained and adjacent CIDRs into the minimal list of aggregates covering them,
+// sorted by family and address. If maxAggregates is positive, aggregates of every address family are merged further
+// into common supernets, until there are at most maxAggregates of them; merges covering the least addresses not
+// covered by the CIDRs are picked first.
+func SummarizeCIDRs(cidrs []CIDR, maxAggregates int) []CIDR {
+	var v4, v6 []netip.Prefix
+	for _, cidr := range cidrs {
+		if cidr.IsIPv4() {
+			v4 = append(v4, cidr.Net.Masked())
+		} else {
+			v6 = append(v6, cidr.Net.Masked())
+		}
+	}
+
+	aggregates := make([]CIDR, 0, len(cidrs))
+	for _, prefixes := range [][]netip.Prefix{v4, v6} {
+		prefixes = summarizePrefixes(prefixes)
+		for maxAggregates > 0 && len(prefixes) > maxAggregates {
+			prefixes = summarizePrefixes(mergeCheapest(prefixes))
+		}
+		for _, prefix := range prefixes {
+			aggregates = append(aggregates, CIDR{Net: prefix})
+		}
+	}
+	return aggregates
+}
+
+// summarizePrefixes drops contained prefixes and merges sibling prefixes of the same family into their parents.
+func summarizePrefixes(prefixes []netip.Prefix) []netip.Prefix {
+	slices.SortFunc(prefixes, func(a, b netip.Prefix) int {
+		if c := a.Addr().Compare(b.Addr()); c != 0 {
+			return c
+		}
+		return a.Bits() - b.Bits()
+	})
+
+	summarized := make([]netip.Prefix, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		// prefixes are sorted by address, so a prefix may only be contained in the last one
+		if len(summarized) > 0 && summarized[len(summarized)-1].Overlaps(prefix) {
+			continue
+		}
+		summarized = append(summarized, prefix)
+		for len(summarized) > 1 {
+			last, previous := summarized[len(summarized)-1], summarized[len(summarized)-2]
+			if last.Bits() != previous.Bits() || last.Bits() == 0 {
+				break
+			}
+			parent, _ := previous.Addr().Prefix(previous.Bits() - 1)
+			if !parent.Contains(last.Addr()) {
+				break
+			}
+			summarized = append(summarized[:len(summarized)-2], parent)
+		}
+	}
+	return summarized
+}
+
+// mergeCheapest replaces the pair of neighbouring prefixes, which common supernet covers the least addresses
+// out of the prefixes, with the supernet. Prefixes should be sorted and not overlap.
+func mergeCheapest(prefixes []netip.Prefix) []netip.Prefix {
+	var cheapest netip.Prefix
+	var cheapestCost *big.Int
+	for i := 0; i+1 < len(prefixes); i++ {
+		supernet := commonSupernet(prefixes[i], prefixes[i+1])
+		cost := prefixCapacity(supernet)
+		for _, prefix := range prefixes {
+			if supernet.Contains(prefix.Addr()) {
+				cost.Sub(cost, prefixCapacity(prefix))
+			}
+		}
+		if cheapestCost == nil || cost.Cmp(cheapestCost) < 0 {
+			cheapest, cheapestCost = supernet, cost
+		}
+	}
+	// contained prefixes are dropped by summarization
+	return append(prefixes, cheapest)
+}
+
+// commonSupernet returns the longest prefix containing both prefixes.
+func commonSupernet(a, b netip.Prefix) netip.Prefix {
+	bits := min(a.Bits(), b.Bits())
+	for ; bits > 0; bits-- {
+		supernet, _ := a.Addr().Prefix(bits)
+		if supernet.Contains(b.Addr()) {
+			return supernet
+		}
+	}
+	supernet, _ := a.Addr().Prefix(0)
+	return supernet
+}
+
+func prefixCapacity(prefix netip.Prefix) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(prefix.Addr().BitLen()-prefix.Bits()))
+}
@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"fmt"
+	"math"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// CapacityLowCondition is set on Networks and Subnets with utilization thresholds.
+	// It is true while the reserved part of capacity is not below one of the thresholds.
+	CapacityLowCondition = "CapacityLow"
+
+	UtilizationNormalReason   = "UtilizationNormal"
+	UtilizationWarningReason  = "UtilizationWarning"
+	UtilizationCriticalReason = "UtilizationCritical"
+)
+
+// UtilizationPercentage returns a percentage of reserved capacity rounded down.
+func UtilizationPercentage(capacity, capacityLeft resource.Quantity) int32 {
+	total := capacity.AsApproximateFloat64()
+	if total <= 0 {
+		return 0
+	}
+	return int32(math.Floor((total - capacityLeft.AsApproximateFloat64()) / total * 100))
+}
+
+// UtilizationCondition evaluates utilization against the thresholds,
+// and returns the CapacityLow condition or nil, if no thresholds are set.
+func UtilizationCondition(utilization int32, warning, critical *int32, generation int64) *metav1.Condition {
+	if warning == nil && critical == nil {
+		return nil
+	}
+
+	condition := &metav1.Condition{
+		Type:               CapacityLowCondition,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             UtilizationNormalReason,
+		Message:            fmt.Sprintf("%d%% of capacity is reserved", utilization),
+	}
+	switch {
+	case critical != nil && utilization >= *critical:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = UtilizationCriticalReason
+		condition.Message = fmt.Sprintf("%d%% of capacity is reserved, critical threshold is %d%%", utilization, *critical)
+	case warning != nil && utilization >= *warning:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = UtilizationWarningReason
+		condition.Message = fmt.Sprintf("%d%% of capacity is reserved, warning threshold is %d%%", utilization, *warning)
+	}
+	return condition
+}
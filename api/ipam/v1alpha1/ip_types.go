@@ -4,7 +4,6 @@
 package v1alpha1
 
 import (
-	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -20,9 +19,9 @@ type IPState string
 
 // IPSpec defines the desired state of IP
 type IPSpec struct {
-	// SubnetName is referring to parent subnet that holds requested IP
+	// Subnet is referring to parent subnet that holds requested IP
 	// +kubebuilder:validation:Required
-	Subnet v1.LocalObjectReference `json:"subnet"`
+	Subnet SubnetReference `json:"subnet"`
 	// Consumer refers to resource IP has been booked for
 	// +kubebuilder:validation:Optional
 	Consumer *ResourceReference `json:"consumer,omitempty"`
@@ -39,6 +38,9 @@ type IPStatus struct {
 	Reserved *IPAddr `json:"reserved,omitempty"`
 	// Message contains error details if the one has occurred
 	Message string `json:"message,omitempty"`
+	// NetworkConfig is the network configuration of the reserved address, taken from the subnet
+	// +optional
+	NetworkConfig *NetworkConfig `json:"networkConfig,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,235 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"gopkg.in/inf.v0"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+// IPAMQuotaResources is a set of amounts of IPAM resources
+type IPAMQuotaResources struct {
+	// IPs is a number of IPs
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	IPs *int64 `json:"ips,omitempty"`
+	// SubnetCapacity is a total address capacity of child Subnets, i.e. Subnets with a parent Subnet
+	// +kubebuilder:validation:Optional
+	SubnetCapacity *resource.Quantity `json:"subnetCapacity,omitempty"`
+	// Networks is a number of Networks
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	Networks *int64 `json:"networks,omitempty"`
+}
+
+// IPAMQuotaSpec defines the desired state of IPAMQuota
+type IPAMQuotaSpec struct {
+	// Hard is a set of limits enforced on creation of IPAM resources
+	// +kubebuilder:validation:Required
+	Hard IPAMQuotaResources `json:"hard"`
+	// Selector limits the quota to resources with matching labels.
+	// All resources of the namespace are accounted if not set.
+	// +kubebuilder:validation:Optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// IPAMQuotaStatus defines the observed state of IPAMQuota
+type IPAMQuotaStatus struct {
+	// Hard is a set of enforced limits
+	Hard IPAMQuotaResources `json:"hard,omitempty"`
+	// Used is current usage of limited resources
+	Used IPAMQuotaResources `json:"used,omitempty"`
+	// Message contains an error string if usage can not be calculated
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=ipamquotas,singular=ipamquota
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Used IPs",type=string,JSONPath=`.status.used.ips`,description="Number of IPs"
+// +kubebuilder:printcolumn:name="Hard IPs",type=string,JSONPath=`.status.hard.ips`,description="Limit of IPs"
+// +kubebuilder:printcolumn:name="Used Subnet Capacity",type=string,JSONPath=`.status.used.subnetCapacity`,description="Address capacity of child Subnets"
+// +kubebuilder:printcolumn:name="Hard Subnet Capacity",type=string,JSONPath=`.status.hard.subnetCapacity`,description="Limit of address capacity of child Subnets"
+// +kubebuilder:printcolumn:name="Used Networks",type=string,JSONPath=`.status.used.networks`,description="Number of Networks"
+// +kubebuilder:printcolumn:name="Hard Networks",type=string,JSONPath=`.status.hard.networks`,description="Limit of Networks"
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.message`,description="Message"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IPAMQuota is the Schema for the ipamquotas API
+type IPAMQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IPAMQuotaSpec   `json:"spec,omitempty"`
+	Status IPAMQuotaStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IPAMQuotaList contains a list of IPAMQuota
+type IPAMQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IPAMQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(func(s *runtime.Scheme) error {
+		s.AddKnownTypes(SchemeGroupVersion, &IPAMQuota{}, &IPAMQuotaList{})
+		return nil
+	})
+}
+
+// Selects checks whether the resource is accounted by the quota.
+func (in *IPAMQuota) Selects(obj metav1.Object) (bool, error) {
+	if in.Spec.Selector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(in.Spec.Selector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(obj.GetLabels())), nil
+}
+
+// Usage calculates usage of the limited resources.
+// Subnets should contain all Subnets of the namespace, so capacity requested by prefix bits may be resolved with parents.
+// Capacity requested by prefix bits from a parent in another namespace is not accounted until the CIDR is reserved.
+// Failed resources are not accounted, since they do not hold any reservation.
+func (in *IPAMQuota) Usage(networks []Network, subnets []Subnet, ips []IP) (IPAMQuotaResources, error) {
+	used := IPAMQuotaResources{}
+
+	if in.Spec.Hard.Networks != nil {
+		count := int64(0)
+		for i := range networks {
+			if networks[i].Status.State == CFailedNetworkState {
+				continue
+			}
+			ok, err := in.Selects(&networks[i])
+			if err != nil {
+				return used, err
+			}
+			if ok {
+				count++
+			}
+		}
+		used.Networks = &count
+	}
+
+	if in.Spec.Hard.SubnetCapacity != nil {
+		parents := make(map[types.NamespacedName]*Subnet, len(subnets))
+		for i := range subnets {
+			parents[types.NamespacedName{Namespace: subnets[i].Namespace, Name: subnets[i].Name}] = &subnets[i]
+		}
+		capacity := resource.Quantity{}
+		for i := range subnets {
+			subnet := &subnets[i]
+			if subnet.Spec.ParentSubnet.Name == "" || subnet.Status.State == FailedSubnetState {
+				continue
+			}
+			ok, err := in.Selects(subnet)
+			if err != nil {
+				return used, err
+			}
+			if ok {
+				capacity.Add(subnet.RequestedCapacity(parents[subnet.Spec.ParentSubnet.NamespacedName(subnet.Namespace)]))
+			}
+		}
+		used.SubnetCapacity = &capacity
+	}
+
+	if in.Spec.Hard.IPs != nil {
+		count := int64(0)
+		for i := range ips {
+			if ips[i].Status.State == FailedIPState {
+				continue
+			}
+			ok, err := in.Selects(&ips[i])
+			if err != nil {
+				return used, err
+			}
+			if ok {
+				count++
+			}
+		}
+		used.IPs = &count
+	}
+
+	return used, nil
+}
+
+// Exceeds returns descriptions of limits, that would be exceeded if requested resources are added to used ones.
+func (in *IPAMQuota) Exceeds(used, requested IPAMQuotaResources) []string {
+	exceeded := make([]string, 0)
+	hard := in.Spec.Hard
+	if hard.IPs != nil && requested.IPs != nil {
+		if total := ptr.Deref(used.IPs, 0) + *requested.IPs; total > *hard.IPs {
+			exceeded = append(exceeded, fmt.Sprintf("ips: requested %d, used %d, limited %d", *requested.IPs, ptr.Deref(used.IPs, 0), *hard.IPs))
+		}
+	}
+	if hard.SubnetCapacity != nil && requested.SubnetCapacity != nil {
+		usedCapacity := ptr.Deref(used.SubnetCapacity, resource.Quantity{})
+		total := requested.SubnetCapacity.DeepCopy()
+		total.Add(usedCapacity)
+		if total.Cmp(*hard.SubnetCapacity) > 0 {
+			exceeded = append(exceeded, fmt.Sprintf("subnetCapacity: requested %s, used %s, limited %s",
+				requested.SubnetCapacity.String(), usedCapacity.String(), hard.SubnetCapacity.String()))
+		}
+	}
+	if hard.Networks != nil && requested.Networks != nil {
+		if total := ptr.Deref(used.Networks, 0) + *requested.Networks; total > *hard.Networks {
+			exceeded = append(exceeded, fmt.Sprintf("networks: requested %d, used %d, limited %d", *requested.Networks, ptr.Deref(used.Networks, 0), *hard.Networks))
+		}
+	}
+	return exceeded
+}
+
+// ExceededMessage formats descriptions of exceeded limits of the quota.
+func (in *IPAMQuota) ExceededMessage(exceeded []string) string {
+	return fmt.Sprintf("exceeded quota %s: %s", in.Name, strings.Join(exceeded, ", "))
+}
+
+// RequestedCapacity returns the address capacity of the subnet, or capacity it requests if it is not booked yet.
+// Parent is required to resolve capacity of subnets requested by prefix bits; it is zero if the parent is unknown.
+func (in *Subnet) RequestedCapacity(parent *Subnet) resource.Quantity {
+	if in.Status.Reserved != nil {
+		return in.Status.Capacity.DeepCopy()
+	}
+
+	var capacity *big.Int
+	switch {
+	case in.Spec.CIDR != nil:
+		capacity = in.Spec.CIDR.AddressCapacity()
+	case in.Spec.PrefixBits != nil:
+		if parent == nil || parent.Status.Reserved == nil {
+			return resource.Quantity{}
+		}
+		bits := parent.Status.Reserved.Net.Addr().BitLen() - int(*in.Spec.PrefixBits)
+		if bits < 0 {
+			return resource.Quantity{}
+		}
+		capacity = new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	case in.Spec.Capacity != nil:
+		requestedCapacity := in.Spec.Capacity.DeepCopy()
+		requested := new(inf.Dec).Round(requestedCapacity.AsDec(), 0, inf.RoundCeil).UnscaledBig()
+		if requested.Sign() <= 0 {
+			return resource.Quantity{}
+		}
+		// capacity is ceiled to the closest power of 2
+		capacity = new(big.Int).Lsh(big.NewInt(1), uint(new(big.Int).Sub(requested, Increment).BitLen()))
+	default:
+		return resource.Quantity{}
+	}
+	return resource.MustParse(capacity.String())
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ReferenceGrantFrom describes resources allowed to refer Subnets of the grant namespace
+type ReferenceGrantFrom struct {
+	// Kind is a kind of the referring resource
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=Subnet;IP
+	Kind string `json:"kind"`
+	// Namespace is a namespace of the referring resources
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+}
+
+// ReferenceGrantTo describes Subnets of the grant namespace that may be referred
+type ReferenceGrantTo struct {
+	// Name is a name of the Subnet, all Subnets of the namespace may be referred if not set
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
+}
+
+// IPAMReferenceGrantSpec defines the desired state of IPAMReferenceGrant
+type IPAMReferenceGrantSpec struct {
+	// From is a list of resources allowed to refer Subnets of the namespace
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	From []ReferenceGrantFrom `json:"from"`
+	// To is a list of Subnets that may be referred
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	To []ReferenceGrantTo `json:"to"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=ipamreferencegrants,singular=ipamreferencegrant
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IPAMReferenceGrant permits IPs and Subnets from other namespaces
+// to allocate addresses from Subnets of the grant namespace
+type IPAMReferenceGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IPAMReferenceGrantSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IPAMReferenceGrantList contains a list of IPAMReferenceGrant
+type IPAMReferenceGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IPAMReferenceGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(func(s *runtime.Scheme) error {
+		s.AddKnownTypes(SchemeGroupVersion, &IPAMReferenceGrant{}, &IPAMReferenceGrantList{})
+		return nil
+	})
+}
+
+// Permits checks whether the grant allows resources of the kind from the namespace to refer the Subnet.
+func (in *IPAMReferenceGrant) Permits(kind, namespace, subnetName string) bool {
+	fromPermitted := false
+	for _, from := range in.Spec.From {
+		if from.Kind == kind && from.Namespace == namespace {
+			fromPermitted = true
+			break
+		}
+	}
+	if !fromPermitted {
+		return false
+	}
+	for _, to := range in.Spec.To {
+		if to.Name == "" || to.Name == subnetName {
+			return true
+		}
+	}
+	return false
+}
+
+// ReferencePermitted checks whether the resource of the kind from the namespace may refer the Subnet.
+// References within the namespace are always permitted, cross namespace references require
+// one of the grants, which should be the grants of the Subnet namespace.
+func ReferencePermitted(grants []IPAMReferenceGrant, kind, namespace string, ref SubnetReference) bool {
+	if !ref.IsCrossNamespace(namespace) {
+		return true
+	}
+	for i := range grants {
+		if grants[i].Namespace == ref.Namespace && grants[i].Permits(kind, namespace, ref.Name) {
+			return true
+		}
+	}
+	return false
+}
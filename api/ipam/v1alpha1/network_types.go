@@ -4,6 +4,8 @@
 package v1alpha1
 
 import (
+	"slices"
+
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -26,6 +28,79 @@ type NetworkSpec struct {
 	// Description contains a human readable description of network
 	// +kubebuilder:validation:Optional
 	Description string `json:"description,omitempty"`
+	// UtilizationWarning is a percentage of capacity of top level subnets reserved in any address family,
+	// at which the CapacityLow condition is raised
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	UtilizationWarning *int32 `json:"utilizationWarning,omitempty"`
+	// UtilizationCritical is a percentage of capacity of top level subnets reserved in any address family,
+	// at which the CapacityLow condition becomes critical
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	UtilizationCritical *int32 `json:"utilizationCritical,omitempty"`
+	// ReservedRanges are ranges blocked in the network without creating a Subnet,
+	// e.g. address space of legacy systems or upstream providers, top level subnets may not overlap them
+	// +kubebuilder:validation:Optional
+	ReservedRanges []NetworkReservedRange `json:"reservedRanges,omitempty"`
+	// AllowedRanges are aggregates top level subnets should belong to. Top level subnets of an address family
+	// are not restricted, if no ranges of the family are set
+	// +kubebuilder:validation:Optional
+	AllowedRanges []CIDR `json:"allowedRanges,omitempty"`
+	// SpecialPurposePolicy defines how top level subnets and IPs overlapping IANA special-purpose blocks,
+	// e.g. documentation or loopback ranges, are admitted. Overlaps are warned about if not set
+	// +kubebuilder:validation:Optional
+	SpecialPurposePolicy *SpecialPurposePolicy `json:"specialPurposePolicy,omitempty"`
+	// MaxAggregates limits the number of aggregates of top level subnets per address family in status,
+	// aggregates are merged into covering supernets, until the limit is met. Aggregates are minimal if not set
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxAggregates *int32 `json:"maxAggregates,omitempty"`
+}
+
+// SpecialPurposeAction is an action taken on admission of addresses overlapping special-purpose blocks.
+type SpecialPurposeAction string
+
+const (
+	IgnoreSpecialPurposeAction SpecialPurposeAction = "Ignore"
+	WarnSpecialPurposeAction   SpecialPurposeAction = "Warn"
+	RejectSpecialPurposeAction SpecialPurposeAction = "Reject"
+)
+
+// SpecialPurposePolicy defines admission of addresses overlapping special-purpose blocks.
+type SpecialPurposePolicy struct {
+	// Action is taken on admission of overlapping top level subnets and IPs
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Ignore;Warn;Reject
+	// +kubebuilder:default=Warn
+	Action SpecialPurposeAction `json:"action,omitempty"`
+	// Exceptions are ranges special-purpose addresses are intended in, e.g. 100.64.0.0/10 of a carrier-grade NAT,
+	// top level subnets and IPs within them are not checked
+	// +kubebuilder:validation:Optional
+	Exceptions []CIDR `json:"exceptions,omitempty"`
+}
+
+// NetworkReservedRange is a range blocked in the network.
+type NetworkReservedRange struct {
+	// CIDR is the blocked range
+	// +kubebuilder:validation:Required
+	CIDR CIDR `json:"cidr"`
+	// Reason is a human readable reason the range is blocked for
+	// +kubebuilder:validation:Optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// AllowedRangeStatus is utilization of an allowed range of the network.
+type AllowedRangeStatus struct {
+	// CIDR is the allowed range
+	CIDR CIDR `json:"cidr"`
+	// Capacity is an address capacity of the range
+	Capacity resource.Quantity `json:"capacity"`
+	// CapacityLeft is an address capacity of the range not booked by top level subnets or reserved ranges
+	CapacityLeft resource.Quantity `json:"capacityLeft"`
+	// Utilization is a percentage of the capacity booked
+	Utilization int32 `json:"utilization"`
 }
 
 const (
@@ -48,10 +123,23 @@ type NetworkStatus struct {
 	IPv4Capacity resource.Quantity `json:"ipv4Capacity,omitempty"`
 	// IPv6Capacity is a total address capacity of all IPv4 CIDRs in Ranges
 	IPv6Capacity resource.Quantity `json:"ipv6Capacity,omitempty"`
+	// ReservedRanges is a list of reserved ranges booked in IPv4Ranges and IPv6Ranges
+	ReservedRanges []NetworkReservedRange `json:"reservedRanges,omitempty"`
+	// IPv4Aggregates is a list of IPv4 aggregates covering top level subnets, e.g. for route announcements
+	IPv4Aggregates []CIDR `json:"ipv4Aggregates,omitempty"`
+	// IPv6Aggregates is a list of IPv6 aggregates covering top level subnets, e.g. for route announcements
+	IPv6Aggregates []CIDR `json:"ipv6Aggregates,omitempty"`
+	// AllowedRanges is utilization of allowed ranges by booked ranges
+	AllowedRanges []AllowedRangeStatus `json:"allowedRanges,omitempty"`
 	// State is a network creation request processing state
 	State NetworkState `json:"state,omitempty"`
 	// Message contains error details if the one has occurred
 	Message string `json:"message,omitempty"`
+	// Conditions represent the latest observations of the network state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // Network is the Schema for the networks API
@@ -222,6 +310,88 @@ func (in *Network) CanReserve(cidr *CIDR) bool {
 	return false
 }
 
+// Allows checks the CIDR belongs to an allowed range of its address family,
+// any CIDR is allowed if no ranges of the family are set.
+func (in *Network) Allows(cidr *CIDR) bool {
+	restricted := false
+	for _, allowedRange := range in.Spec.AllowedRanges {
+		if allowedRange.IsIPv4() != cidr.IsIPv4() {
+			continue
+		}
+		restricted = true
+		if allowedRange.Net.Bits() <= cidr.Net.Bits() && allowedRange.Net.Contains(cidr.Net.Addr()) {
+			return true
+		}
+	}
+	return !restricted
+}
+
+// AllowedRangeUtilization computes utilization of allowed ranges by booked ranges of the network.
+func (in *Network) AllowedRangeUtilization() []AllowedRangeStatus {
+	if len(in.Spec.AllowedRanges) == 0 {
+		return nil
+	}
+
+	statuses := make([]AllowedRangeStatus, 0, len(in.Spec.AllowedRanges))
+	for _, allowedRange := range in.Spec.AllowedRanges {
+		capacity := resource.MustParse(allowedRange.AddressCapacity().String())
+		capacityLeft := capacity.DeepCopy()
+		for _, booked := range in.getRangesForCidr(&allowedRange) {
+			if !booked.Net.Overlaps(allowedRange.Net) {
+				continue
+			}
+			// Overlapping prefixes nest, so the longer one is the overlap
+			overlap := booked
+			if allowedRange.Net.Bits() > booked.Net.Bits() {
+				overlap = allowedRange
+			}
+			capacityLeft.Sub(resource.MustParse(overlap.AddressCapacity().String()))
+		}
+		statuses = append(statuses, AllowedRangeStatus{
+			CIDR:         allowedRange,
+			Capacity:     capacity,
+			CapacityLeft: capacityLeft,
+			Utilization:  UtilizationPercentage(capacity, capacityLeft),
+		})
+	}
+	return statuses
+}
+
+// Aggregates summarizes ranges booked by top level subnets, ranges booked by reserved ranges are left out.
+func (in *Network) Aggregates() (ipv4 []CIDR, ipv6 []CIDR) {
+	var ranges []CIDR
+	for _, booked := range append(slices.Clone(in.Status.IPv4Ranges), in.Status.IPv6Ranges...) {
+		if !slices.ContainsFunc(in.Status.ReservedRanges, func(reservedRange NetworkReservedRange) bool {
+			return reservedRange.CIDR.Equal(&booked)
+		}) {
+			ranges = append(ranges, booked)
+		}
+	}
+
+	maxAggregates := 0
+	if in.Spec.MaxAggregates != nil {
+		maxAggregates = int(*in.Spec.MaxAggregates)
+	}
+	for _, aggregate := range SummarizeCIDRs(ranges, maxAggregates) {
+		if aggregate.IsIPv4() {
+			ipv4 = append(ipv4, aggregate)
+		} else {
+			ipv6 = append(ipv6, aggregate)
+		}
+	}
+	return ipv4, ipv6
+}
+
+// BookedReservedRange returns the booked reserved range overlapping the CIDR, if any.
+func (in *Network) BookedReservedRange(cidr *CIDR) *NetworkReservedRange {
+	for i := range in.Status.ReservedRanges {
+		if in.Status.ReservedRanges[i].CIDR.Net.Overlaps(cidr.Net) {
+			return &in.Status.ReservedRanges[i]
+		}
+	}
+	return nil
+}
+
 func (in *Network) getRangesForCidr(cidr *CIDR) []CIDR {
 	if cidr.IsIPv4() {
 		return in.Status.IPv4Ranges
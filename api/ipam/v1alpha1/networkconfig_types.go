@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"slices"
+)
+
+// SubnetRoute is a static route configured for addresses of a Subnet
+type SubnetRoute struct {
+	// Destination is the destination CIDR of the route
+	// +kubebuilder:validation:Required
+	Destination *CIDR `json:"destination"`
+	// Gateway is the next hop of the route, the Subnet gateway is used if not set
+	// +kubebuilder:validation:Optional
+	Gateway *IPAddr `json:"gateway,omitempty"`
+	// Metric is the metric of the route
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	Metric *int32 `json:"metric,omitempty"`
+}
+
+// NetworkConfig is a resolved network configuration of addresses of a Subnet,
+// fields which are not set by the Subnet are inherited from its parent.
+type NetworkConfig struct {
+	// PrefixLength is the length of the on-link prefix, i.e. of the Subnet the gateway belongs to,
+	// or of the Subnet itself if no gateway is configured
+	PrefixLength byte `json:"prefixLength,omitempty"`
+	// Gateway is the default gateway
+	Gateway *IPAddr `json:"gateway,omitempty"`
+	// DNSServers are addresses of DNS resolvers
+	DNSServers []IPAddr `json:"dnsServers,omitempty"`
+	// SearchDomains are DNS search domains
+	SearchDomains []string `json:"searchDomains,omitempty"`
+	// Routes are static routes
+	Routes []SubnetRoute `json:"routes,omitempty"`
+	// MTU is the maximum transmission unit of the link
+	MTU *int32 `json:"mtu,omitempty"`
+}
+
+// ResolveNetworkConfig resolves network configuration of the Subnet from its spec,
+// fields which are not set in spec are taken from the resolved configuration of the parent, if any.
+// Reserved CIDR of the Subnet should be known.
+func (in *Subnet) ResolveNetworkConfig(parent *NetworkConfig) *NetworkConfig {
+	if parent == nil {
+		parent = &NetworkConfig{}
+	}
+	config := parent.DeepCopy()
+
+	if in.Spec.Gateway != nil {
+		config.Gateway = in.Spec.Gateway.DeepCopy()
+		config.PrefixLength = in.Status.Reserved.MaskOnes()
+	} else if config.Gateway == nil && in.Status.Reserved != nil {
+		config.PrefixLength = in.Status.Reserved.MaskOnes()
+	}
+	if len(in.Spec.DNSServers) > 0 {
+		config.DNSServers = slices.Clone(in.Spec.DNSServers)
+	}
+	if len(in.Spec.SearchDomains) > 0 {
+		config.SearchDomains = slices.Clone(in.Spec.SearchDomains)
+	}
+	if len(in.Spec.Routes) > 0 {
+		config.Routes = make([]SubnetRoute, len(in.Spec.Routes))
+		for i := range in.Spec.Routes {
+			in.Spec.Routes[i].DeepCopyInto(&config.Routes[i])
+		}
+	}
+	if in.Spec.MTU != nil {
+		mtu := *in.Spec.MTU
+		config.MTU = &mtu
+	}
+
+	return config
+}
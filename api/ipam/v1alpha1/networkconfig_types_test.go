@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Subnet network configuration", func() {
+	It("Should inherit unset fields and the on-link prefix of the parent gateway", func() {
+		mtu := int32(9000)
+		parent := EmptySubnetFromCidr("10.0.0.0/16")
+		parent.Spec.Gateway = IPMustParse("10.0.0.1")
+		parent.Spec.DNSServers = []IPAddr{*IPMustParse("10.0.0.53")}
+		parent.Spec.MTU = &mtu
+		parentConfig := parent.ResolveNetworkConfig(nil)
+		Expect(parentConfig.PrefixLength).To(Equal(byte(16)))
+
+		child := EmptySubnetFromCidr("10.0.5.0/24")
+		child.Spec.SearchDomains = []string{"example.com"}
+		config := child.ResolveNetworkConfig(parentConfig)
+		Expect(config.PrefixLength).To(Equal(byte(16)))
+		Expect(config.Gateway.String()).To(Equal("10.0.0.1"))
+		Expect(config.DNSServers).To(HaveLen(1))
+		Expect(config.SearchDomains).To(Equal([]string{"example.com"}))
+		Expect(*config.MTU).To(Equal(int32(9000)))
+		Expect(parentConfig.SearchDomains).To(BeEmpty())
+	})
+
+	It("Should override the parent gateway with the own one", func() {
+		parent := EmptySubnetFromCidr("10.0.0.0/16")
+		parent.Spec.Gateway = IPMustParse("10.0.0.1")
+
+		child := EmptySubnetFromCidr("10.0.5.0/24")
+		child.Spec.Gateway = IPMustParse("10.0.5.1")
+		config := child.ResolveNetworkConfig(parent.ResolveNetworkConfig(nil))
+		Expect(config.PrefixLength).To(Equal(byte(24)))
+		Expect(config.Gateway.String()).To(Equal("10.0.5.1"))
+	})
+})
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"net/netip"
+	"slices"
+	"sync"
+)
+
+//go:embed specialpurpose_registry.csv
+var specialPurposeRegistry []byte
+
+// SpecialPurposeBlock is a block of the IANA special-purpose address registries.
+// +kubebuilder:object:generate=false
+type SpecialPurposeBlock struct {
+	// Prefix is the address block
+	Prefix netip.Prefix
+	// Name is the registry name of the block, e.g. Documentation (TEST-NET-1)
+	Name string
+	// RFC is the RFC defining the block
+	RFC string
+}
+
+func (in SpecialPurposeBlock) String() string {
+	return fmt.Sprintf("%s (%s, %s)", in.Prefix, in.Name, in.RFC)
+}
+
+var specialPurposeBlocks = sync.OnceValue(func() []SpecialPurposeBlock {
+	reader := csv.NewReader(bytes.NewReader(specialPurposeRegistry))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 3
+	records, err := reader.ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("invalid special-purpose registry: %s", err))
+	}
+
+	blocks := make([]SpecialPurposeBlock, 0, len(records)-1)
+	// the first record is the header
+	for _, record := range records[1:] {
+		prefix, err := netip.ParsePrefix(record[0])
+		if err != nil {
+			panic(fmt.Sprintf("invalid special-purpose registry block %s: %s", record[0], err))
+		}
+		blocks = append(blocks, SpecialPurposeBlock{Prefix: prefix, Name: record[1], RFC: record[2]})
+	}
+	return blocks
+})
+
+// SpecialPurposeBlocks returns blocks of the embedded IANA special-purpose address registries,
+// along with multicast address space.
+func SpecialPurposeBlocks() []SpecialPurposeBlock {
+	return slices.Clone(specialPurposeBlocks())
+}
+
+// SpecialPurposeOverlaps returns special-purpose blocks overlapping the CIDR.
+func SpecialPurposeOverlaps(cidr *CIDR) []SpecialPurposeBlock {
+	var overlaps []SpecialPurposeBlock
+	for _, block := range specialPurposeBlocks() {
+		if block.Prefix.Overlaps(cidr.Net) {
+			overlaps = append(overlaps, block)
+		}
+	}
+	return overlaps
+}
+
+// Check returns the action to take on the CIDR and special-purpose blocks it overlaps, if any.
+// CIDRs within exceptions are not checked, and nil policy warns on overlaps.
+func (in *SpecialPurposePolicy) Check(cidr *CIDR) (SpecialPurposeAction, []SpecialPurposeBlock) {
+	action := WarnSpecialPurposeAction
+	if in != nil {
+		if in.Action != "" {
+			action = in.Action
+		}
+		for _, exception := range in.Exceptions {
+			if exception.Net.Bits() <= cidr.Net.Bits() && exception.Net.Contains(cidr.Net.Addr()) {
+				return action, nil
+			}
+		}
+	}
+	if action == IgnoreSpecialPurposeAction {
+		return action, nil
+	}
+	return action, SpecialPurposeOverlaps(cidr)
+}
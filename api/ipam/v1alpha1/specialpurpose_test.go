@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Special-purpose registry", func() {
+	It("Should load the embedded registry", func() {
+		Expect(SpecialPurposeBlocks()).To(ContainElements(
+			HaveField("Name", "Documentation (TEST-NET-1)"),
+			HaveField("Name", "Shared Address Space"),
+			HaveField("Name", "Multicast"),
+		))
+		Expect(SpecialPurposeBlocks()).NotTo(ContainElement(HaveField("Name", "Private-Use")))
+	})
+
+	It("Should find blocks overlapping the CIDR", func() {
+		Expect(SpecialPurposeOverlaps(CidrMustParse("192.0.2.0/25"))).To(ConsistOf(HaveField("RFC", "RFC5737")))
+		Expect(SpecialPurposeOverlaps(CidrMustParse("64:ff9b::/96"))).To(ConsistOf(HaveField("RFC", "RFC6052")))
+		Expect(SpecialPurposeOverlaps(CidrMustParse("10.0.0.0/8"))).To(BeEmpty())
+	})
+
+	It("Should apply the policy", func() {
+		var policy *SpecialPurposePolicy
+		action, blocks := policy.Check(CidrMustParse("100.64.0.0/16"))
+		Expect(action).To(Equal(WarnSpecialPurposeAction))
+		Expect(blocks).To(HaveLen(1))
+
+		policy = &SpecialPurposePolicy{
+			Action:     RejectSpecialPurposeAction,
+			Exceptions: []CIDR{*CidrMustParse("100.64.0.0/10")},
+		}
+		action, blocks = policy.Check(CidrMustParse("100.64.0.0/16"))
+		Expect(action).To(Equal(RejectSpecialPurposeAction))
+		Expect(blocks).To(BeEmpty())
+		_, blocks = policy.Check(CidrMustParse("203.0.113.0/24"))
+		Expect(blocks).To(HaveLen(1))
+
+		policy.Action = IgnoreSpecialPurposeAction
+		_, blocks = policy.Check(CidrMustParse("203.0.113.0/24"))
+		Expect(blocks).To(BeEmpty())
+	})
+})
@@ -6,6 +6,7 @@ package v1alpha1
 import (
 	"math/big"
 	"net/netip"
+	"slices"
 
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
@@ -27,9 +28,9 @@ type SubnetSpec struct {
 	// Capacity is a desired amount of addresses; will be ceiled to the closest power of 2.
 	// +kubebuilder:validation:Optional
 	Capacity *resource.Quantity `json:"capacity,omitempty"`
-	// ParentSubnetName contains a reference (name) to the parent subent
+	// ParentSubnet contains a reference to the parent subnet
 	// +kubebuilder:validation:Optional
-	ParentSubnet v1.LocalObjectReference `json:"parentSubnet,omitempty"`
+	ParentSubnet SubnetReference `json:"parentSubnet,omitempty"`
 	// NetworkName contains a reference (name) to the network
 	// +kubebuilder:validation:Required
 	Network v1.LocalObjectReference `json:"network"`
@@ -39,6 +40,109 @@ type SubnetSpec struct {
 	// Consumer refers to resource Subnet has been booked for
 	// +kubebuilder:validation:Optional
 	Consumer *ResourceReference `json:"consumer,omitempty"`
+	// UtilizationWarning is a percentage of reserved capacity, at which the CapacityLow condition is raised
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	UtilizationWarning *int32 `json:"utilizationWarning,omitempty"`
+	// UtilizationCritical is a percentage of reserved capacity, at which the CapacityLow condition becomes critical
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	UtilizationCritical *int32 `json:"utilizationCritical,omitempty"`
+	// AccessPolicy restricts who may allocate IPs and child Subnets from the subnet
+	// +kubebuilder:validation:Optional
+	AccessPolicy *SubnetAccessPolicy `json:"accessPolicy,omitempty"`
+	// Gateway is the default gateway of the subnet; the address is reserved, so it is never allocated to IPs
+	// +kubebuilder:validation:Optional
+	Gateway *IPAddr `json:"gateway,omitempty"`
+	// DNSServers are addresses of DNS resolvers
+	// +kubebuilder:validation:Optional
+	DNSServers []IPAddr `json:"dnsServers,omitempty"`
+	// SearchDomains are DNS search domains
+	// +kubebuilder:validation:Optional
+	SearchDomains []string `json:"searchDomains,omitempty"`
+	// Routes are static routes
+	// +kubebuilder:validation:Optional
+	Routes []SubnetRoute `json:"routes,omitempty"`
+	// MTU is the maximum transmission unit of the link
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=68
+	// +kubebuilder:validation:Maximum=65535
+	MTU *int32 `json:"mtu,omitempty"`
+	// DNS configures DNS records of IPs reserved in the subnet
+	// +kubebuilder:validation:Optional
+	DNS *SubnetDNS `json:"dns,omitempty"`
+	// Split carves the subnet into equally sized child Subnets, which are created and owned by the subnet.
+	// Split may not be set along with the gateway.
+	// +kubebuilder:validation:Optional
+	Split *SubnetSplit `json:"split,omitempty"`
+}
+
+// SubnetAccessPolicy restricts allocations of IPs and child Subnets.
+// Each restriction applies only if it is set; a policy without restrictions allows any allocation.
+type SubnetAccessPolicy struct {
+	// Consumers lists consumer kinds allocations may be booked for.
+	// Allocations without a matching consumer are rejected.
+	// +kubebuilder:validation:Optional
+	Consumers []ConsumerKind `json:"consumers,omitempty"`
+	// Users lists names of users and service accounts allowed to allocate,
+	// e.g. system:serviceaccount:default:machine-controller
+	// +kubebuilder:validation:Optional
+	Users []string `json:"users,omitempty"`
+	// Groups lists groups of users allowed to allocate, e.g. system:serviceaccounts:default
+	// +kubebuilder:validation:Optional
+	Groups []string `json:"groups,omitempty"`
+	// AllowExplicitAddresses permits requests of particular addresses, i.e. IPs with spec.ip and child Subnets with spec.cidr.
+	// Allocations of the next free address are always permitted.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	AllowExplicitAddresses *bool `json:"allowExplicitAddresses,omitempty"`
+}
+
+// ConsumerKind matches consumer references of a particular type
+type ConsumerKind struct {
+	// APIVersion is consumer's API group and version; consumers of any version match if it is not set
+	// +kubebuilder:validation:Optional
+	APIVersion string `json:"apiVersion,omitempty"`
+	// Kind is consumer's kind
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+}
+
+// AllowsConsumer checks whether allocations may be booked for the consumer.
+func (in *SubnetAccessPolicy) AllowsConsumer(consumer *ResourceReference) bool {
+	if len(in.Consumers) == 0 {
+		return true
+	}
+	if consumer == nil {
+		return false
+	}
+	for _, kind := range in.Consumers {
+		if kind.Kind == consumer.Kind && (kind.APIVersion == "" || kind.APIVersion == consumer.APIVersion) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsUser checks whether the user or any of its groups may allocate.
+func (in *SubnetAccessPolicy) AllowsUser(username string, groups []string) bool {
+	if len(in.Users) == 0 && len(in.Groups) == 0 {
+		return true
+	}
+	if slices.Contains(in.Users, username) {
+		return true
+	}
+	return slices.ContainsFunc(groups, func(group string) bool {
+		return slices.Contains(in.Groups, group)
+	})
+}
+
+// AllowsExplicitAddresses checks whether particular addresses may be requested.
+func (in *SubnetAccessPolicy) AllowsExplicitAddresses() bool {
+	return in.AllowExplicitAddresses == nil || *in.AllowExplicitAddresses
 }
 
 const (
@@ -94,6 +198,14 @@ type SubnetStatus struct {
 	State SubnetState `json:"state,omitempty"`
 	// Message contains an error string for the failed State
 	Message string `json:"message,omitempty"`
+	// Conditions represent the latest observations of the subnet state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// NetworkConfig is the network configuration of the subnet addresses, resolved with inheritance from parent subnets
+	// +optional
+	NetworkConfig *NetworkConfig `json:"networkConfig,omitempty"`
 }
 
 // +kubebuilder:object:root=true
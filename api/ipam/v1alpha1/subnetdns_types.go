@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+// DNSHostnamePlaceholders are placeholders, which may be used in SubnetDNS.HostnameTemplate.
+var DNSHostnamePlaceholders = []string{
+	"{ip.name}",
+	"{consumer.kind}",
+	"{consumer.name}",
+	"{subnet.name}",
+	"{namespace}",
+	"{zone}",
+}
+
+// SubnetDNS configures DNS records of IPs reserved in a Subnet.
+// Forward A/AAAA records and reverse PTR records are derived for every finished IP.
+type SubnetDNS struct {
+	// Zone is the forward DNS zone hostnames of IPs belong to, e.g. example.net
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Zone string `json:"zone"`
+	// HostnameTemplate is a template of fully qualified hostnames of IPs, e.g. {consumer.name}.{subnet.name}.example.net.
+	// Placeholders are {ip.name}, {consumer.kind}, {consumer.name}, {subnet.name}, {namespace} and {zone}.
+	// IPs without a consumer get no records if the template refers the consumer.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="{ip.name}.{zone}"
+	HostnameTemplate string `json:"hostnameTemplate,omitempty"`
+	// TTL is a time to live of the records in seconds
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	TTL *int32 `json:"ttl,omitempty"`
+	// Nameservers are authoritative nameservers of reverse zones of the subnet, used in SOA and NS records of zone files
+	// +kubebuilder:validation:Optional
+	Nameservers []string `json:"nameservers,omitempty"`
+	// DisableReverse disables PTR records
+	// +kubebuilder:validation:Optional
+	DisableReverse bool `json:"disableReverse,omitempty"`
+}
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// SubnetReference refers to a Subnet at the same namespace, or at another namespace
+// if the reference is permitted by an IPAMReferenceGrant of that namespace
+// +structType=atomic
+type SubnetReference struct {
+	// Name is a name of the Subnet
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
+	// Namespace is a namespace of the Subnet, namespace of the referring resource is used if not set
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// NamespacedName returns the key of the referred Subnet,
+// namespace is the namespace of the referring resource.
+func (in SubnetReference) NamespacedName(namespace string) types.NamespacedName {
+	if in.Namespace != "" {
+		namespace = in.Namespace
+	}
+	return types.NamespacedName{Namespace: namespace, Name: in.Name}
+}
+
+// IsCrossNamespace checks whether the reference points out of the namespace of the referring resource.
+func (in SubnetReference) IsCrossNamespace(namespace string) bool {
+	return in.Namespace != "" && in.Namespace != namespace
+}
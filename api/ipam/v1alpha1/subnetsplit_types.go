@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SplitNamePlaceholders are placeholders, which may be used in SubnetSplit.NameTemplate.
+var SplitNamePlaceholders = []string{
+	"{subnet.name}",
+	"{index}",
+}
+
+// MaxSplitChildren is the maximum number of child Subnets of a split.
+const MaxSplitChildren = 1024
+
+const defaultSplitNameTemplate = "{subnet.name}-{index}"
+
+// SubnetSplit carves a Subnet into equally sized child Subnets, which are created and owned by the Subnet.
+// Children are allocated in address order and named by their index, starting with 0.
+type SubnetSplit struct {
+	// PrefixBits is the prefix length of child Subnets, the Subnet is split into all subnets of the length.
+	// Either prefixBits or count should be set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=128
+	PrefixBits *byte `json:"prefixBits,omitempty"`
+	// Count is the number of child Subnets, children get the longest prefix fitting the count into the Subnet.
+	// Either prefixBits or count should be set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1024
+	Count *int32 `json:"count,omitempty"`
+	// NameTemplate is a template of child Subnet names, e.g. {subnet.name}-rack-{index}.
+	// Placeholders are {subnet.name} and {index}; the template should refer {index}, so names are unique.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="{subnet.name}-{index}"
+	NameTemplate string `json:"nameTemplate,omitempty"`
+}
+
+// Children returns the prefix length and the number of child Subnets splitting the CIDR.
+func (in *SubnetSplit) Children(cidr *CIDR) (byte, int, error) {
+	ones := cidr.MaskOnes()
+	maskBits := cidr.MaskBits()
+
+	switch {
+	case in.PrefixBits != nil && in.Count != nil:
+		return 0, 0, errors.New("either prefix bits or count should be set for the split, not both")
+	case in.PrefixBits != nil:
+		prefixBits := *in.PrefixBits
+		if prefixBits < ones || prefixBits > maskBits {
+			return 0, 0, errors.Errorf("children of /%d prefix length do not fit into %s", prefixBits, cidr)
+		}
+		if prefixBits-ones > byte(bits.Len(MaxSplitChildren-1)) {
+			return 0, 0, errors.Errorf("split of %s into /%d children exceeds %d children", cidr, prefixBits, MaxSplitChildren)
+		}
+		return prefixBits, 1 << (prefixBits - ones), nil
+	case in.Count != nil:
+		count := int(*in.Count)
+		if count < 1 || count > MaxSplitChildren {
+			return 0, 0, errors.Errorf("split count should be between 1 and %d", MaxSplitChildren)
+		}
+		extraBits := byte(bits.Len(uint(count - 1)))
+		if ones+extraBits > maskBits {
+			return 0, 0, errors.Errorf("%d children do not fit into %s", count, cidr)
+		}
+		return ones + extraBits, count, nil
+	default:
+		return 0, 0, errors.New("either prefix bits or count should be set for the split")
+	}
+}
+
+// ChildName returns the name of the child Subnet with the index.
+func (in *SubnetSplit) ChildName(subnetName string, index int) string {
+	template := in.NameTemplate
+	if template == "" {
+		template = defaultSplitNameTemplate
+	}
+	return strings.NewReplacer("{subnet.name}", subnetName, "{index}", strconv.Itoa(index)).Replace(template)
+}
@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("Subnet split", func() {
+	It("Should compute prefix length and number of children", func() {
+		testCases := []struct {
+			split      SubnetSplit
+			cidr       string
+			prefixBits byte
+			count      int
+		}{
+			{split: SubnetSplit{PrefixBits: ptr.To[byte](24)}, cidr: "10.0.0.0/20", prefixBits: 24, count: 16},
+			{split: SubnetSplit{PrefixBits: ptr.To[byte](20)}, cidr: "10.0.0.0/20", prefixBits: 20, count: 1},
+			{split: SubnetSplit{Count: ptr.To[int32](16)}, cidr: "10.0.0.0/20", prefixBits: 24, count: 16},
+			{split: SubnetSplit{Count: ptr.To[int32](3)}, cidr: "10.0.0.0/20", prefixBits: 22, count: 3},
+			{split: SubnetSplit{Count: ptr.To[int32](1)}, cidr: "fd00::/48", prefixBits: 48, count: 1},
+			{split: SubnetSplit{PrefixBits: ptr.To[byte](64)}, cidr: "fd00::/56", prefixBits: 64, count: 256},
+		}
+
+		for _, testCase := range testCases {
+			prefixBits, count, err := testCase.split.Children(CidrMustParse(testCase.cidr))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(prefixBits).To(Equal(testCase.prefixBits))
+			Expect(count).To(Equal(testCase.count))
+		}
+	})
+
+	It("Should refuse splits not fitting into the CIDR", func() {
+		testCases := []struct {
+			split SubnetSplit
+			cidr  string
+		}{
+			{split: SubnetSplit{}, cidr: "10.0.0.0/20"},
+			{split: SubnetSplit{PrefixBits: ptr.To[byte](24), Count: ptr.To[int32](16)}, cidr: "10.0.0.0/20"},
+			{split: SubnetSplit{PrefixBits: ptr.To[byte](16)}, cidr: "10.0.0.0/20"},
+			{split: SubnetSplit{PrefixBits: ptr.To[byte](33)}, cidr: "10.0.0.0/20"},
+			{split: SubnetSplit{PrefixBits: ptr.To[byte](64)}, cidr: "fd00::/48"},
+			{split: SubnetSplit{Count: ptr.To[int32](3)}, cidr: "10.0.0.1/32"},
+			{split: SubnetSplit{Count: ptr.To[int32](MaxSplitChildren + 1)}, cidr: "fd00::/48"},
+		}
+
+		for _, testCase := range testCases {
+			_, _, err := testCase.split.Children(CidrMustParse(testCase.cidr))
+			Expect(err).To(HaveOccurred(), "split %+v of %s", testCase.split, testCase.cidr)
+		}
+	})
+
+	It("Should name children by the template", func() {
+		Expect((&SubnetSplit{}).ChildName("rack", 3)).To(Equal("rack-3"))
+		Expect((&SubnetSplit{NameTemplate: "{subnet.name}-pod-{index}"}).ChildName("rack", 12)).To(Equal("rack-pod-12"))
+	})
+})
@@ -0,0 +1,15 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"github.com/containernetworking/cni/pkg/skel"
+	"github.com/containernetworking/cni/pkg/version"
+
+	"github.com/ironcore-dev/ipam/internal/cni"
+)
+
+func main() {
+	skel.PluginMainFuncs(cni.NewPlugin().Funcs(), version.All, "IronCore IPAM CNI plugin")
+}
@@ -0,0 +1,216 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ipamv1alpha1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+	utils "github.com/ironcore-dev/ipam/cmdutils"
+)
+
+var (
+	allocateNamespace string
+	allocateName      string
+	allocateSubnet    string
+	allocateIP        string
+	allocateParent    string
+	allocateCIDR      string
+	allocatePrefix    uint8
+	allocateConsumer  string
+	allocateWait      bool
+	allocateTimeout   time.Duration
+)
+
+func NewIPCommand() *cobra.Command {
+	ip := &cobra.Command{
+		Use:   "ip",
+		Short: "Allocate and release IPs",
+		Args:  cobra.NoArgs,
+	}
+
+	allocate := &cobra.Command{
+		Use:   "allocate",
+		Short: "Allocate an IP from a Subnet",
+		Args:  cobra.NoArgs,
+		RunE:  runIPAllocate,
+	}
+	addAllocateFlags(allocate)
+	allocate.Flags().StringVar(&allocateSubnet, "subnet", "", "Subnet to allocate the IP from")
+	allocate.Flags().StringVar(&allocateIP, "ip", "",
+		"desired IP address. Defaults to the next free address of the Subnet if not specified")
+	_ = allocate.MarkFlagRequired("subnet")
+
+	release := &cobra.Command{
+		Use:   "release NAME",
+		Short: "Release an IP back to its Subnet",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRelease(cmd, &ipamv1alpha1.IP{ObjectMeta: metav1.ObjectMeta{Namespace: allocateNamespace, Name: args[0]}})
+		},
+	}
+	addReleaseFlags(release)
+
+	ip.AddCommand(allocate, release)
+	return ip
+}
+
+func NewSubnetCommand() *cobra.Command {
+	subnet := &cobra.Command{
+		Use:   "subnet",
+		Short: "Allocate and release Subnets",
+		Args:  cobra.NoArgs,
+	}
+
+	allocate := &cobra.Command{
+		Use:   "allocate",
+		Short: "Allocate a Subnet from a parent Subnet",
+		Args:  cobra.NoArgs,
+		RunE:  runSubnetAllocate,
+	}
+	addAllocateFlags(allocate)
+	allocate.Flags().StringVar(&allocateParent, "parent", "", "parent Subnet to allocate the Subnet from")
+	allocate.Flags().Uint8Var(&allocatePrefix, "prefix", 0, "prefix length of the Subnet to allocate, e.g. 26")
+	allocate.Flags().StringVar(&allocateCIDR, "cidr", "", "desired CIDR of the Subnet, instead of a prefix length")
+	allocate.MarkFlagsMutuallyExclusive("prefix", "cidr")
+	allocate.MarkFlagsOneRequired("prefix", "cidr")
+	_ = allocate.MarkFlagRequired("parent")
+
+	release := &cobra.Command{
+		Use:   "release NAME",
+		Short: "Release a Subnet back to its parent Subnet",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRelease(cmd, &ipamv1alpha1.Subnet{ObjectMeta: metav1.ObjectMeta{Namespace: allocateNamespace, Name: args[0]}})
+		},
+	}
+	addReleaseFlags(release)
+
+	subnet.AddCommand(allocate, release)
+	return subnet
+}
+
+func NewNextFreeCommand() *cobra.Command {
+	nextFree := &cobra.Command{
+		Use:   "next-free",
+		Short: "Show the CIDR, that would be allocated next in a Subnet, without reserving it",
+		Args:  cobra.NoArgs,
+		RunE:  runNextFree,
+	}
+	nextFree.Flags().StringVarP(&allocateNamespace, "namespace", "n", "default", "namespace of the Subnet")
+	nextFree.Flags().StringVar(&allocateSubnet, "subnet", "", "Subnet to look for a free CIDR in")
+	nextFree.Flags().Uint8Var(&allocatePrefix, "prefix", 0,
+		"prefix length of the free CIDR. Defaults to a single address if not specified")
+	_ = nextFree.MarkFlagRequired("subnet")
+	return nextFree
+}
+
+func addAllocateFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&allocateNamespace, "namespace", "n", "default", "namespace to allocate in")
+	cmd.Flags().StringVar(&allocateName, "name", "",
+		"name of the allocated resource. Generated from the parent name if not specified")
+	cmd.Flags().StringVar(&allocateConsumer, "consumer", "",
+		"consumer of the allocated resource, as kind/name or apiVersion/kind/name")
+	cmd.Flags().BoolVar(&allocateWait, "wait", false, "wait until the allocation is finished and print the result")
+	cmd.Flags().DurationVar(&allocateTimeout, "timeout", time.Minute, "time to wait for with --wait")
+}
+
+func addReleaseFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&allocateNamespace, "namespace", "n", "default", "namespace of the released resource")
+	cmd.Flags().BoolVar(&allocateWait, "wait", false, "wait until the resource is released")
+	cmd.Flags().DurationVar(&allocateTimeout, "timeout", time.Minute, "time to wait for with --wait")
+}
+
+// waitTimeout returns a time to wait for the allocation, zero if it should not be waited for.
+func waitTimeout() time.Duration {
+	if !allocateWait {
+		return 0
+	}
+	return allocateTimeout
+}
+
+func runIPAllocate(cmd *cobra.Command, args []string) error {
+	consumer, err := utils.ParseConsumer(allocateConsumer)
+	if err != nil {
+		return err
+	}
+	cl, err := makeClusterClient()
+	if err != nil {
+		return err
+	}
+
+	ip, err := utils.AllocateIP(cmd.Context(), cl, utils.AllocateIPOptions{
+		Namespace: allocateNamespace,
+		Name:      allocateName,
+		Subnet:    allocateSubnet,
+		IP:        allocateIP,
+		Consumer:  consumer,
+		Wait:      waitTimeout(),
+	})
+	if err != nil {
+		return err
+	}
+	if ip.Status.Reserved == nil {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "ip %s/%s created\n", ip.Namespace, ip.Name)
+		return nil
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "ip %s/%s allocated %s\n", ip.Namespace, ip.Name, ip.Status.Reserved)
+	return nil
+}
+
+func runSubnetAllocate(cmd *cobra.Command, args []string) error {
+	consumer, err := utils.ParseConsumer(allocateConsumer)
+	if err != nil {
+		return err
+	}
+	cl, err := makeClusterClient()
+	if err != nil {
+		return err
+	}
+
+	subnet, err := utils.AllocateSubnet(cmd.Context(), cl, utils.AllocateSubnetOptions{
+		Namespace:  allocateNamespace,
+		Name:       allocateName,
+		Parent:     allocateParent,
+		CIDR:       allocateCIDR,
+		PrefixBits: allocatePrefix,
+		Consumer:   consumer,
+		Wait:       waitTimeout(),
+	})
+	if err != nil {
+		return err
+	}
+	if subnet.Status.Reserved == nil {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "subnet %s/%s created\n", subnet.Namespace, subnet.Name)
+		return nil
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "subnet %s/%s allocated %s\n", subnet.Namespace, subnet.Name, subnet.Status.Reserved)
+	return nil
+}
+
+func runRelease(cmd *cobra.Command, obj client.Object) error {
+	cl, err := makeClusterClient()
+	if err != nil {
+		return err
+	}
+	return utils.Release(cmd.Context(), cl, obj, waitTimeout())
+}
+
+func runNextFree(cmd *cobra.Command, args []string) error {
+	cl, err := makeClusterClient()
+	if err != nil {
+		return err
+	}
+	cidr, err := utils.NextFree(cmd.Context(), cl, allocateNamespace, allocateSubnet, allocatePrefix)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), cidr)
+	return nil
+}
@@ -4,8 +4,12 @@
 package app
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
 
 	ipamv1alphav1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -17,6 +21,8 @@ const Name string = "ipamctl"
 
 var scheme = runtime.NewScheme()
 
+var kubeconfig string
+
 func init() {
 	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
@@ -29,6 +35,28 @@ func NewCommand() *cobra.Command {
 		Short: "CLI client for ipam",
 		Args:  cobra.NoArgs,
 	}
+	root.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "",
+		"Kubeconfig pointing to the cluster. Defaults to KUBECONFIG, in-cluster config or ~/.kube/config")
 	root.AddCommand(NewMoveCommand())
+	root.AddCommand(NewTreeCommand())
+	root.AddCommand(NewIPCommand())
+	root.AddCommand(NewSubnetCommand())
+	root.AddCommand(NewNextFreeCommand())
+	root.AddCommand(NewWhoisCommand())
+	root.AddCommand(NewRenderCommand())
+	root.AddCommand(NewExportCommand())
+	root.AddCommand(NewSummarizeCommand())
 	return root
 }
+
+// makeClusterClient creates a client for the cluster selected with --kubeconfig.
+func makeClusterClient() (client.Client, error) {
+	if kubeconfig != "" {
+		return makeClient(kubeconfig)
+	}
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cluster kubeconfig: %w", err)
+	}
+	return client.New(cfg, client.Options{Scheme: scheme})
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/ironcore-dev/ipam/dhcp"
+)
+
+var (
+	exportNamespace string
+	exportSelector  string
+	exportFormat    string
+)
+
+func NewExportCommand() *cobra.Command {
+	export := &cobra.Command{
+		Use:   "export",
+		Short: "Export allocated resources as configuration of other systems",
+		Args:  cobra.NoArgs,
+	}
+
+	dhcpCommand := &cobra.Command{
+		Use:   "dhcp",
+		Short: "Export Subnets and IPs as DHCP server configuration",
+		Args:  cobra.NoArgs,
+		RunE:  runExportDHCP,
+	}
+	dhcpCommand.Flags().StringVarP(&exportNamespace, "namespace", "n", "",
+		"namespace to export Subnets from. Defaults to all namespaces if not specified")
+	dhcpCommand.Flags().StringVarP(&exportSelector, "selector", "l", "",
+		"label selector of exported Subnets. Defaults to all Subnets if not specified")
+	dhcpCommand.Flags().StringVarP(&exportFormat, "format", "f", dhcp.KeaDHCP4Format,
+		fmt.Sprintf("output format, any of %s", strings.Join(dhcp.Formats, ", ")))
+
+	export.AddCommand(dhcpCommand)
+	return export
+}
+
+func runExportDHCP(cmd *cobra.Command, _ []string) error {
+	selector, err := labels.Parse(exportSelector)
+	if err != nil {
+		return fmt.Errorf("invalid selector %s: %w", exportSelector, err)
+	}
+	if !slices.Contains(dhcp.Formats, exportFormat) {
+		return fmt.Errorf("unknown format %s, expected any of %s",
+			exportFormat, strings.Join(dhcp.Formats, ", "))
+	}
+
+	cl, err := makeClusterClient()
+	if err != nil {
+		return err
+	}
+	export, err := dhcp.Collect(cmd.Context(), cl, exportNamespace, selector,
+		dhcp.ReferringIPs(cl, exportNamespace), dhcp.ConsumerMAC(cl))
+	if err != nil {
+		return err
+	}
+	return dhcp.Render(cmd.OutOrStdout(), export, exportFormat)
+}
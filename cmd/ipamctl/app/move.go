@@ -6,8 +6,11 @@ package app
 import (
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/spf13/cobra"
@@ -22,11 +25,18 @@ var (
 	sourceKubeconfig string
 	targetKubeconfig string
 	namespace        string
+	selector         string
+	kinds            []string
+	namePrefix       string
+	stateFile        string
 	requireOwners    bool
 	dryRun           bool
 	verbose          bool
 )
 
+// movableKinds are kinds of IPAM CRs, which may be selected with --kinds.
+var movableKinds = []string{"Network", "Subnet", "IP", "NetworkCounter"}
+
 func NewMoveCommand() *cobra.Command {
 	move := &cobra.Command{
 		Use:   "move",
@@ -37,8 +47,19 @@ func NewMoveCommand() *cobra.Command {
 	move.Flags().StringVar(&targetKubeconfig, "target-kubeconfig", "", "Kubeconfig pointing to the target cluster")
 	move.Flags().StringVar(&namespace, "namespace", "",
 		"namespace to filter CRs to migrate. Defaults to all namespaces if not specified")
+	move.Flags().StringVar(&selector, "selector", "",
+		"label selector to filter CRs to migrate, e.g. 'env=prod,tier!=edge'. Defaults to all CRs if not specified")
+	move.Flags().StringSliceVar(&kinds, "kinds", nil,
+		fmt.Sprintf("kinds of CRs to migrate, any of %s. Defaults to all kinds if not specified",
+			strings.Join(movableKinds, ", ")))
+	move.Flags().StringVar(&namePrefix, "name-prefix", "",
+		"prefix to prepend to names of migrated Networks, Subnets and IPs, and to references to them")
+	move.Flags().StringVar(&stateFile, "state-file", "",
+		"local file to record the migration progress in. If set, a failed migration is not cleaned up "+
+			"and can be resumed by running it again with the same state file")
 	move.Flags().BoolVar(&requireOwners, "require-owners", false,
-		"if set to true, an error will be returned if for any custom resource an owner ServerBootConfiguration") //nolint:lll
+		"if set to true, an error will be returned if for any custom resource an owner is neither migrated "+
+			"nor present in the target cluster")
 	move.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be moved without executing the migration")
 	move.Flags().BoolVar(&verbose, "verbose", false, "enable verbose logging for detailed output during migration")
 	_ = move.MarkFlagRequired("source-kubeconfig")
@@ -74,6 +95,16 @@ func makeClients() (utils.Clients, error) {
 }
 
 func runMove(cmd *cobra.Command, args []string) error {
+	for _, kind := range kinds {
+		if !slices.Contains(movableKinds, kind) {
+			return fmt.Errorf("unknown kind %s, expected any of %s", kind, strings.Join(movableKinds, ", "))
+		}
+	}
+	labelSelector, err := labels.Parse(selector)
+	if err != nil {
+		return fmt.Errorf("invalid selector: %w", err)
+	}
+
 	clients, err := makeClients()
 	if err != nil {
 		return err
@@ -86,7 +117,8 @@ func runMove(cmd *cobra.Command, args []string) error {
 	}
 	crsSchema := []schema.GroupVersionKind{}
 	for _, crd := range crdList.Items {
-		if crd.Spec.Group == ipamv1alpha1.SchemeGroupVersion.Group {
+		if crd.Spec.Group == ipamv1alpha1.SchemeGroupVersion.Group &&
+			(len(kinds) == 0 || slices.Contains(kinds, crd.Spec.Names.Kind)) {
 			crsSchema = append(crsSchema, schema.GroupVersionKind{
 				Group:   crd.Spec.Group,
 				Version: crd.Spec.Versions[0].Name,
@@ -94,5 +126,12 @@ func runMove(cmd *cobra.Command, args []string) error {
 			})
 		}
 	}
-	return utils.Move(ctx, clients, crsSchema, namespace, dryRun)
+	return utils.Move(ctx, clients, crsSchema, utils.MoveOptions{
+		Namespace:     namespace,
+		Selector:      labelSelector,
+		RequireOwners: requireOwners,
+		NamePrefix:    namePrefix,
+		StateFile:     stateFile,
+		DryRun:        dryRun,
+	})
 }
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	utils "github.com/ironcore-dev/ipam/cmdutils"
+	"github.com/ironcore-dev/ipam/netconfig"
+)
+
+var (
+	renderNamespace string
+	renderConsumer  string
+	renderFormat    string
+	renderInterface string
+)
+
+func NewRenderCommand() *cobra.Command {
+	render := &cobra.Command{
+		Use:   "render",
+		Short: "Render configuration from allocated resources",
+		Args:  cobra.NoArgs,
+	}
+
+	networkConfig := &cobra.Command{
+		Use:   "network-config",
+		Short: "Render network configuration of a consumer from its IPs",
+		Args:  cobra.NoArgs,
+		RunE:  runRenderNetworkConfig,
+	}
+	networkConfig.Flags().StringVarP(&renderNamespace, "namespace", "n", "",
+		"namespace to look up IPs in. Defaults to all namespaces if not specified")
+	networkConfig.Flags().StringVar(&renderConsumer, "consumer", "",
+		"consumer of the IPs, as kind/name or apiVersion/kind/name")
+	networkConfig.Flags().StringVarP(&renderFormat, "format", "f", netconfig.CloudInitFormat,
+		fmt.Sprintf("output format, any of %s", strings.Join(netconfig.Formats, ", ")))
+	networkConfig.Flags().StringVar(&renderInterface, "interface", netconfig.DefaultInterface,
+		"interface to configure IPs without the interface annotation on")
+	_ = networkConfig.MarkFlagRequired("consumer")
+
+	render.AddCommand(networkConfig)
+	return render
+}
+
+func runRenderNetworkConfig(cmd *cobra.Command, _ []string) error {
+	consumer, err := utils.ParseConsumer(renderConsumer)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(netconfig.Formats, renderFormat) {
+		return fmt.Errorf("unknown format %s, expected any of %s",
+			renderFormat, strings.Join(netconfig.Formats, ", "))
+	}
+
+	cl, err := makeClusterClient()
+	if err != nil {
+		return err
+	}
+	ips, subnets, err := netconfig.ConsumerIPs(cmd.Context(), cl, renderNamespace, consumer)
+	if err != nil {
+		return err
+	}
+	if len(ips) == 0 {
+		return fmt.Errorf("no ips found for consumer %s", renderConsumer)
+	}
+	config, err := netconfig.Build(ips, subnets, netconfig.Options{DefaultInterface: renderInterface})
+	if err != nil {
+		return err
+	}
+	return netconfig.Render(cmd.OutOrStdout(), config, renderFormat)
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+var (
+	summarizeNamespace     string
+	summarizeNetwork       string
+	summarizeMaxAggregates int
+)
+
+func NewSummarizeCommand() *cobra.Command {
+	summarize := &cobra.Command{
+		Use:   "summarize [cidr...]",
+		Short: "Summarize CIDRs or top level Subnets of a Network into aggregates",
+		Long: "Summarize CIDRs or top level Subnets of a Network into the minimal list of aggregates covering them. " +
+			"The number of aggregates per address family may be limited by allowing them to cover unallocated addresses.",
+		RunE: runSummarize,
+	}
+	summarize.Flags().StringVarP(&summarizeNamespace, "namespace", "n", "default",
+		"namespace of the Network")
+	summarize.Flags().StringVar(&summarizeNetwork, "network", "",
+		"Network which top level Subnets are summarized, if no CIDRs are given")
+	summarize.Flags().IntVar(&summarizeMaxAggregates, "max-aggregates", 0,
+		"maximal number of aggregates per address family. Aggregates are minimal if 0")
+	return summarize
+}
+
+func runSummarize(cmd *cobra.Command, args []string) error {
+	if summarizeMaxAggregates < 0 {
+		return fmt.Errorf("max aggregates should not be negative")
+	}
+	if len(args) > 0 && summarizeNetwork != "" {
+		return fmt.Errorf("either CIDRs or a network should be set")
+	}
+
+	var aggregates []v1alpha1.CIDR
+	if len(args) > 0 {
+		cidrs := make([]v1alpha1.CIDR, 0, len(args))
+		for _, arg := range args {
+			cidr, err := v1alpha1.CIDRFromString(arg)
+			if err != nil {
+				return fmt.Errorf("invalid cidr %s: %w", arg, err)
+			}
+			cidrs = append(cidrs, *cidr)
+		}
+		aggregates = v1alpha1.SummarizeCIDRs(cidrs, summarizeMaxAggregates)
+	} else {
+		if summarizeNetwork == "" {
+			return fmt.Errorf("either CIDRs or a network should be set")
+		}
+		cl, err := makeClusterClient()
+		if err != nil {
+			return err
+		}
+		network := &v1alpha1.Network{}
+		if err := cl.Get(cmd.Context(), types.NamespacedName{Namespace: summarizeNamespace, Name: summarizeNetwork}, network); err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("max-aggregates") {
+			maxAggregates := int32(summarizeMaxAggregates)
+			network.Spec.MaxAggregates = &maxAggregates
+			if summarizeMaxAggregates == 0 {
+				network.Spec.MaxAggregates = nil
+			}
+		}
+		ipv4, ipv6 := network.Aggregates()
+		aggregates = append(ipv4, ipv6...)
+	}
+
+	for _, aggregate := range aggregates {
+		if _, err := fmt.Fprintln(cmd.OutOrStdout(), aggregate.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
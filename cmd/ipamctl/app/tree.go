@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	ipamv1alpha1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+	utils "github.com/ironcore-dev/ipam/cmdutils"
+)
+
+var (
+	treeNamespace string
+	treeFamily    string
+	treeRegion    string
+	treeOutput    string
+)
+
+func NewTreeCommand() *cobra.Command {
+	tree := &cobra.Command{
+		Use:   "tree [network]",
+		Short: "Show Networks, Subnets and IPs as a tree with their capacity and utilization",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runTree,
+	}
+	tree.Flags().StringVarP(&treeNamespace, "namespace", "n", "",
+		"namespace to show CRs from. Defaults to all namespaces if not specified")
+	tree.Flags().StringVar(&treeFamily, "family", "",
+		"address family of Subnets to show, IPv4 or IPv6. Defaults to both families if not specified")
+	tree.Flags().StringVar(&treeRegion, "region", "",
+		"region of Subnets to show. Defaults to all regions if not specified")
+	tree.Flags().StringVarP(&treeOutput, "output", "o", utils.TableOutputFormat,
+		fmt.Sprintf("output format, any of %s", strings.Join(utils.OutputFormats, ", ")))
+	return tree
+}
+
+func runTree(cmd *cobra.Command, args []string) error {
+	family := ipamv1alpha1.SubnetAddressType(treeFamily)
+	if family != "" && family != ipamv1alpha1.IPv4SubnetType && family != ipamv1alpha1.IPv6SubnetType {
+		return fmt.Errorf("unknown family %s, expected any of %s, %s",
+			treeFamily, ipamv1alpha1.IPv4SubnetType, ipamv1alpha1.IPv6SubnetType)
+	}
+	if !slices.Contains(utils.OutputFormats, treeOutput) {
+		return fmt.Errorf("unknown output format %s, expected any of %s",
+			treeOutput, strings.Join(utils.OutputFormats, ", "))
+	}
+
+	cl, err := makeClusterClient()
+	if err != nil {
+		return err
+	}
+
+	opts := utils.TreeOptions{
+		Namespace: treeNamespace,
+		Family:    family,
+		Region:    treeRegion,
+	}
+	if len(args) > 0 {
+		opts.Network = args[0]
+	}
+	nodes, err := utils.GetTree(cmd.Context(), cl, opts)
+	if err != nil {
+		return err
+	}
+	return utils.PrintTree(cmd.OutOrStdout(), nodes, treeOutput)
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	utils "github.com/ironcore-dev/ipam/cmdutils"
+)
+
+var (
+	whoisNamespace string
+	whoisOutput    string
+)
+
+func NewWhoisCommand() *cobra.Command {
+	whois := &cobra.Command{
+		Use:   "whois <ip|cidr>",
+		Short: "Show Networks, Subnets and IPs owning an address or a CIDR",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runWhois,
+	}
+	whois.Flags().StringVarP(&whoisNamespace, "namespace", "n", "",
+		"namespace to look up CRs in. Defaults to all namespaces if not specified")
+	whois.Flags().StringVarP(&whoisOutput, "output", "o", utils.TableOutputFormat,
+		fmt.Sprintf("output format, any of %s", strings.Join(utils.OutputFormats, ", ")))
+	return whois
+}
+
+func runWhois(cmd *cobra.Command, args []string) error {
+	cidr, err := utils.ParseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(utils.OutputFormats, whoisOutput) {
+		return fmt.Errorf("unknown output format %s, expected any of %s",
+			whoisOutput, strings.Join(utils.OutputFormats, ", "))
+	}
+
+	cl, err := makeClusterClient()
+	if err != nil {
+		return err
+	}
+	results, err := utils.Whois(cmd.Context(), cl, whoisNamespace, cidr)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("%s is not owned by any network", args[0])
+	}
+	return utils.PrintWhois(cmd.OutOrStdout(), results, whoisOutput)
+}
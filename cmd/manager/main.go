@@ -24,6 +24,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
@@ -50,6 +51,12 @@ func main() {
 	var enableHTTP2 bool
 	var enableLeaderElection bool
 	var probeAddr string
+	var consumerIPKinds string
+	var loadBalancerClass string
+	var nodeCIDRPool string
+	var nodeCIDRIPv4PrefixBits, nodeCIDRIPv6PrefixBits uint
+	var enableDHCPExport bool
+	var dnsRecords string
 	var tlsOpts []func(*tls.Config)
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
@@ -63,6 +70,22 @@ func main() {
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics server")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&consumerIPKinds, "consumer-ip-kinds", "",
+		"Comma separated list of kinds in apiVersion/Kind form, e.g. v1/Pod,v1/Service, "+
+			"resources of which get IPs allocated if annotated with a subnet or a pool. Disabled if empty.")
+	flag.StringVar(&loadBalancerClass, "load-balancer-class", "",
+		"Load balancer class of LoadBalancer Services which get ingress IPs allocated. Disabled if empty.")
+	flag.StringVar(&nodeCIDRPool, "node-cidr-pool", "",
+		"Pool of Subnets which pod CIDRs of Nodes are allocated from. Disabled if empty.")
+	flag.UintVar(&nodeCIDRIPv4PrefixBits, "node-cidr-ipv4-prefix-bits", 24,
+		"Prefix length of IPv4 pod CIDRs of Nodes. IPv4 pod CIDRs are not allocated if 0.")
+	flag.UintVar(&nodeCIDRIPv6PrefixBits, "node-cidr-ipv6-prefix-bits", 0,
+		"Prefix length of IPv6 pod CIDRs of Nodes, e.g. 64 for dual-stack clusters. IPv6 pod CIDRs are not allocated if 0.")
+	flag.BoolVar(&enableDHCPExport, "enable-dhcp-export", false,
+		"If set, DHCP server configuration is written to ConfigMaps labeled with "+ipamv1alpha1.DHCPExportLabel+".")
+	flag.StringVar(&dnsRecords, "dns-records", "",
+		"Output of DNS records of IPs of Subnets with DNS configuration, "+
+			"dnsendpoint for external-dns DNSEndpoints or zonefile for ConfigMaps of zone files. Disabled if empty.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -174,6 +197,22 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Subnet")
 		os.Exit(1)
 	}
+	if err = (&controllers.SubnetSplitReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("SubnetSplit"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SubnetSplit")
+		os.Exit(1)
+	}
+	if err = (&controllers.AddressPlanReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("AddressPlan"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AddressPlan")
+		os.Exit(1)
+	}
 	if err = (&controllers.IPReconciler{
 		Client: mgr.GetClient(),
 		Log:    ctrl.Log.WithName("controllers").WithName("IP"),
@@ -182,6 +221,87 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "IP")
 		os.Exit(1)
 	}
+	if err = (&controllers.IPAMQuotaReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("IPAMQuota"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IPAMQuota")
+		os.Exit(1)
+	}
+	kinds, err := controllers.ParseConsumerKinds(consumerIPKinds)
+	if err != nil {
+		setupLog.Error(err, "unable to parse consumer ip kinds")
+		os.Exit(1)
+	}
+	for _, gvk := range kinds {
+		if err = (&controllers.ConsumerIPReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("ConsumerIP").WithName(gvk.Kind),
+			Scheme: mgr.GetScheme(),
+			GVK:    gvk,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ConsumerIP", "kind", gvk.String())
+			os.Exit(1)
+		}
+	}
+	if loadBalancerClass != "" {
+		if err = (&controllers.LoadBalancerReconciler{
+			Client:            mgr.GetClient(),
+			Log:               ctrl.Log.WithName("controllers").WithName("LoadBalancer"),
+			Scheme:            mgr.GetScheme(),
+			LoadBalancerClass: loadBalancerClass,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "LoadBalancer")
+			os.Exit(1)
+		}
+	}
+	if nodeCIDRPool != "" {
+		if nodeCIDRIPv4PrefixBits > 32 || nodeCIDRIPv6PrefixBits > 128 {
+			setupLog.Error(nil, "invalid node cidr prefix bits",
+				"ipv4", nodeCIDRIPv4PrefixBits, "ipv6", nodeCIDRIPv6PrefixBits)
+			os.Exit(1)
+		}
+		if err = (&controllers.NodeIPAMReconciler{
+			Client:         mgr.GetClient(),
+			Log:            ctrl.Log.WithName("controllers").WithName("NodeIPAM"),
+			Scheme:         mgr.GetScheme(),
+			Pool:           nodeCIDRPool,
+			IPv4PrefixBits: byte(nodeCIDRIPv4PrefixBits),
+			IPv6PrefixBits: byte(nodeCIDRIPv6PrefixBits),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "NodeIPAM")
+			os.Exit(1)
+		}
+	}
+	if enableDHCPExport {
+		if err = (&controllers.DHCPExportReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("DHCPExport"),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "DHCPExport")
+			os.Exit(1)
+		}
+	}
+	if dnsRecords != "" {
+		if err = (&controllers.DNSRecordReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("DNSRecord"),
+			Scheme: mgr.GetScheme(),
+			Output: dnsRecords,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "DNSRecord")
+			os.Exit(1)
+		}
+	}
+	if err = metrics.Registry.Register(&controllers.CapacityCollector{
+		Reader: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("metrics").WithName("Capacity"),
+	}); err != nil {
+		setupLog.Error(err, "unable to register metrics collector", "collector", "Capacity")
+		os.Exit(1)
+	}
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		if err = v1alpha1.SetupNetworkCounterWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "NetworkCounter")
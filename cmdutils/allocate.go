@@ -0,0 +1,216 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmdutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ipamv1alphav1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+// AllocateIPOptions describe an IP to allocate.
+type AllocateIPOptions struct {
+	Namespace string
+	// Name of the IP. If empty, the name is generated from the Subnet name.
+	Name   string
+	Subnet string
+	// IP is a desired address. If empty, the next free address of the Subnet is allocated.
+	IP       string
+	Consumer *ipamv1alphav1.ResourceReference
+	// Wait is a time to wait for the IP to be reserved. The IP is not waited for if zero.
+	Wait time.Duration
+}
+
+// AllocateSubnetOptions describe a Subnet to allocate from a parent Subnet.
+type AllocateSubnetOptions struct {
+	Namespace string
+	// Name of the Subnet. If empty, the name is generated from the parent Subnet name.
+	Name   string
+	Parent string
+	// CIDR is a desired CIDR. If empty, the next free CIDR with PrefixBits is allocated.
+	CIDR       string
+	PrefixBits byte
+	Consumer   *ipamv1alphav1.ResourceReference
+	// Wait is a time to wait for the Subnet to be reserved. The Subnet is not waited for if zero.
+	Wait time.Duration
+}
+
+// ParseConsumer parses a consumer reference in kind/name or apiVersion/kind/name format.
+func ParseConsumer(consumer string) (*ipamv1alphav1.ResourceReference, error) {
+	if consumer == "" {
+		return nil, nil
+	}
+	parts := strings.Split(consumer, "/")
+	if len(parts) < 2 || slices.Contains(parts, "") {
+		return nil, fmt.Errorf("invalid consumer %q, expected kind/name or apiVersion/kind/name", consumer)
+	}
+	return &ipamv1alphav1.ResourceReference{
+		APIVersion: strings.Join(parts[:len(parts)-2], "/"),
+		Kind:       parts[len(parts)-2],
+		Name:       parts[len(parts)-1],
+	}, nil
+}
+
+// AllocateIP creates an IP in the Subnet, and waits until it is reserved if requested.
+func AllocateIP(ctx context.Context, cl client.Client, opts AllocateIPOptions) (*ipamv1alphav1.IP, error) {
+	ip := &ipamv1alphav1.IP{
+		ObjectMeta: objectMeta(opts.Namespace, opts.Name, opts.Subnet),
+		Spec: ipamv1alphav1.IPSpec{
+			Subnet:   ipamv1alphav1.SubnetReference{Name: opts.Subnet},
+			Consumer: opts.Consumer,
+		},
+	}
+	if opts.IP != "" {
+		addr, err := ipamv1alphav1.IPAddrFromString(opts.IP)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ip %s: %w", opts.IP, err)
+		}
+		ip.Spec.IP = addr
+	}
+
+	if err := cl.Create(ctx, ip); err != nil {
+		return nil, fmt.Errorf("couldn't create ip: %w", err)
+	}
+	if opts.Wait == 0 {
+		return ip, nil
+	}
+
+	err := waitFor(ctx, cl, ip, opts.Wait, func() (bool, error) {
+		switch ip.Status.State {
+		case ipamv1alphav1.FinishedIPState:
+			return true, nil
+		case ipamv1alphav1.FailedIPState:
+			return false, fmt.Errorf("ip %s/%s allocation failed: %s", ip.Namespace, ip.Name, ip.Status.Message)
+		}
+		return false, nil
+	})
+	return ip, err
+}
+
+// AllocateSubnet creates a Subnet in the parent Subnet, and waits until it is reserved if requested.
+// The Subnet belongs to the Network and the regions of the parent Subnet.
+func AllocateSubnet(ctx context.Context, cl client.Client, opts AllocateSubnetOptions) (*ipamv1alphav1.Subnet, error) {
+	parent := &ipamv1alphav1.Subnet{}
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Parent}, parent); err != nil {
+		return nil, fmt.Errorf("couldn't get parent subnet: %w", err)
+	}
+
+	subnet := &ipamv1alphav1.Subnet{
+		ObjectMeta: objectMeta(opts.Namespace, opts.Name, opts.Parent),
+		Spec: ipamv1alphav1.SubnetSpec{
+			ParentSubnet: ipamv1alphav1.SubnetReference{Name: opts.Parent},
+			Network:      parent.Spec.Network,
+			Regions:      parent.Spec.Regions,
+			Consumer:     opts.Consumer,
+		},
+	}
+	switch {
+	case opts.CIDR != "":
+		cidr, err := ipamv1alphav1.CIDRFromString(opts.CIDR)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cidr %s: %w", opts.CIDR, err)
+		}
+		subnet.Spec.CIDR = cidr
+	case opts.PrefixBits != 0:
+		subnet.Spec.PrefixBits = &opts.PrefixBits
+	default:
+		return nil, errors.New("either cidr or prefix bits should be set")
+	}
+
+	if err := cl.Create(ctx, subnet); err != nil {
+		return nil, fmt.Errorf("couldn't create subnet: %w", err)
+	}
+	if opts.Wait == 0 {
+		return subnet, nil
+	}
+
+	err := waitFor(ctx, cl, subnet, opts.Wait, func() (bool, error) {
+		switch subnet.Status.State {
+		case ipamv1alphav1.FinishedSubnetState:
+			return true, nil
+		case ipamv1alphav1.FailedSubnetState:
+			return false, fmt.Errorf("subnet %s/%s allocation failed: %s", subnet.Namespace, subnet.Name, subnet.Status.Message)
+		}
+		return false, nil
+	})
+	return subnet, err
+}
+
+// Release deletes an IP or a Subnet, and waits until it is gone if requested,
+// so its addresses are returned to the parent Subnet.
+func Release(ctx context.Context, cl client.Client, obj client.Object, timeout time.Duration) error {
+	if err := cl.Delete(ctx, obj); err != nil {
+		return fmt.Errorf("couldn't delete %s: %w", obj.GetName(), err)
+	}
+	if timeout == 0 {
+		return nil
+	}
+
+	err := wait.PollUntilContextTimeout(ctx, pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		err := cl.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("%s is not released: %w", obj.GetName(), err)
+	}
+	return nil
+}
+
+// NextFree previews the CIDR with the prefix bits, that would be allocated next in the Subnet.
+// Nothing is reserved. If prefix bits are zero, a single address is previewed.
+func NextFree(ctx context.Context, cl client.Client, namespace, name string, prefixBits byte) (*ipamv1alphav1.CIDR, error) {
+	subnet := &ipamv1alphav1.Subnet{}
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, subnet); err != nil {
+		return nil, fmt.Errorf("couldn't get subnet: %w", err)
+	}
+	if subnet.Status.State != ipamv1alphav1.FinishedSubnetState {
+		return nil, fmt.Errorf("subnet %s/%s is not reserved yet, state %q", namespace, name, subnet.Status.State)
+	}
+
+	if prefixBits == 0 {
+		prefixBits = 32
+		if subnet.Status.Type == ipamv1alphav1.IPv6SubnetType {
+			prefixBits = 128
+		}
+	}
+	cidr, err := subnet.ProposeForBits(prefixBits)
+	if err != nil {
+		return nil, fmt.Errorf("no free /%d in subnet %s/%s: %w", prefixBits, namespace, name, err)
+	}
+	return cidr, nil
+}
+
+func objectMeta(namespace, name, generateFrom string) metav1.ObjectMeta {
+	if name != "" {
+		return metav1.ObjectMeta{Namespace: namespace, Name: name}
+	}
+	return metav1.ObjectMeta{Namespace: namespace, GenerateName: generateFrom + "-"}
+}
+
+// waitFor polls the object until done returns true or an error.
+func waitFor(ctx context.Context, cl client.Client, obj client.Object, timeout time.Duration, done func() (bool, error)) error {
+	err := wait.PollUntilContextTimeout(ctx, pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		if err := cl.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			return false, err
+		}
+		return done()
+	})
+	if wait.Interrupted(err) {
+		return fmt.Errorf("%s is not reserved in %s", obj.GetName(), timeout)
+	}
+	return err
+}
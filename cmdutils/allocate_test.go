@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmdutils
+
+import (
+	"time"
+
+	ipamv1alphav1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("ipamctl allocate", func() {
+	const allocateNs = "allocate-namespace"
+
+	var parent *ipamv1alphav1.Subnet
+
+	BeforeEach(func(ctx SpecContext) {
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: allocateNs}}
+		Expect(client.IgnoreAlreadyExists(clients.Source.Create(ctx, namespace))).To(Succeed())
+
+		parent = &ipamv1alphav1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: allocateNs, GenerateName: "parent-"},
+			Spec: ipamv1alphav1.SubnetSpec{
+				CIDR:    ipamv1alphav1.CidrMustParse("10.0.0.0/24"),
+				Network: corev1.LocalObjectReference{Name: "network"},
+				Regions: []ipamv1alphav1.Region{{Name: "eu", AvailabilityZones: []string{"a"}}},
+			},
+		}
+		Expect(clients.Source.Create(ctx, parent)).To(Succeed())
+		parent.FillStatusFromCidr(parent.Spec.CIDR)
+		Expect(parent.Reserve(ipamv1alphav1.CidrMustParse("10.0.0.0/26"))).To(Succeed())
+		Expect(clients.Source.Status().Update(ctx, parent)).To(Succeed())
+	})
+
+	It("Should parse consumer references", func() {
+		consumer, err := ParseConsumer("Machine/m1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(consumer).To(Equal(&ipamv1alphav1.ResourceReference{Kind: "Machine", Name: "m1"}))
+
+		consumer, err = ParseConsumer("apps/v1/Deployment/d1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(consumer).To(Equal(&ipamv1alphav1.ResourceReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "d1"}))
+
+		consumer, err = ParseConsumer("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(consumer).To(BeNil())
+
+		_, err = ParseConsumer("m1")
+		Expect(err).To(HaveOccurred())
+		_, err = ParseConsumer("Machine/")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should preview the next free CIDR", func(ctx SpecContext) {
+		cidr, err := NextFree(ctx, clients.Source, allocateNs, parent.Name, 28)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cidr.String()).To(Equal("10.0.0.64/28"))
+
+		cidr, err = NextFree(ctx, clients.Source, allocateNs, parent.Name, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cidr.String()).To(Equal("10.0.0.64/32"))
+
+		_, err = NextFree(ctx, clients.Source, allocateNs, parent.Name, 24)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should create an IP in the Subnet", func(ctx SpecContext) {
+		ip, err := AllocateIP(ctx, clients.Source, AllocateIPOptions{
+			Namespace: allocateNs,
+			Subnet:    parent.Name,
+			IP:        "10.0.0.65",
+			Consumer:  &ipamv1alphav1.ResourceReference{Kind: "Machine", Name: "m1"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ip.Name).To(HavePrefix(parent.Name + "-"))
+		Expect(ip.Spec.Subnet.Name).To(Equal(parent.Name))
+		Expect(ip.Spec.IP).To(Equal(ipamv1alphav1.IPMustParse("10.0.0.65")))
+		Expect(ip.Spec.Consumer.Name).To(Equal("m1"))
+	})
+
+	It("Should surface the failure message of an IP", func(ctx SpecContext) {
+		go func() {
+			defer GinkgoRecover()
+			Eventually(func(g Gomega) {
+				ip := &ipamv1alphav1.IP{}
+				g.Expect(clients.Source.Get(ctx, client.ObjectKey{Namespace: allocateNs, Name: "failed-ip"}, ip)).To(Succeed())
+				ip.Status.State = ipamv1alphav1.FailedIPState
+				ip.Status.Message = "no free addresses"
+				g.Expect(clients.Source.Status().Update(ctx, ip)).To(Succeed())
+			}).Should(Succeed())
+		}()
+
+		_, err := AllocateIP(ctx, clients.Source, AllocateIPOptions{
+			Namespace: allocateNs,
+			Name:      "failed-ip",
+			Subnet:    parent.Name,
+			Wait:      eventuallyTimeout,
+		})
+		Expect(err).To(MatchError(ContainSubstring("no free addresses")))
+	})
+
+	It("Should create a Subnet in the parent Subnet", func(ctx SpecContext) {
+		subnet, err := AllocateSubnet(ctx, clients.Source, AllocateSubnetOptions{
+			Namespace:  allocateNs,
+			Parent:     parent.Name,
+			PrefixBits: 26,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subnet.Spec.ParentSubnet.Name).To(Equal(parent.Name))
+		Expect(subnet.Spec.Network.Name).To(Equal("network"))
+		Expect(subnet.Spec.Regions).To(Equal(parent.Spec.Regions))
+		Expect(*subnet.Spec.PrefixBits).To(Equal(byte(26)))
+
+		_, err = AllocateSubnet(ctx, clients.Source, AllocateSubnetOptions{Namespace: allocateNs, Parent: parent.Name})
+		Expect(err).To(HaveOccurred())
+
+		Expect(Release(ctx, clients.Source, subnet, time.Second)).To(Succeed())
+	})
+})
@@ -10,11 +10,15 @@ import (
 	"log/slog"
 	"reflect"
 	"slices"
+	"strings"
 	"time"
 
 	ipamv1alphav1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -32,6 +36,7 @@ func getCrs(
 	cl client.Client,
 	crsGvk []schema.GroupVersionKind,
 	namespace string,
+	selector labels.Selector,
 ) ([]*unstructured.Unstructured, error) {
 	crs := make([]*unstructured.Unstructured, 0)
 
@@ -39,7 +44,7 @@ func getCrs(
 		crsList := &unstructured.UnstructuredList{}
 		crsList.SetGroupVersionKind(crGvk)
 
-		if err := cl.List(ctx, crsList, &client.ListOptions{Namespace: namespace}); err != nil {
+		if err := cl.List(ctx, crsList, &client.ListOptions{Namespace: namespace, LabelSelector: selector}); err != nil {
 			return nil, fmt.Errorf("couldn't list CRs: %w", err)
 		}
 		for _, cr := range crsList.Items { // won't work with go version <1.22
@@ -50,6 +55,86 @@ func getCrs(
 	return crs, nil
 }
 
+// prefixedKinds are kinds of CRs, which names are prefixed on move.
+// NetworkCounter names are fixed by the controllers, therefore they are never prefixed.
+var prefixedKinds = []string{"Network", "Subnet", "IP"}
+
+var referenceFields = [][]string{
+	{"spec", "network", "name"},
+	{"spec", "parentSubnet", "name"},
+	{"spec", "subnet", "name"},
+}
+
+// prefixNames prepends prefix to names of CRs and to all references to them.
+func prefixNames(crs []*unstructured.Unstructured, prefix string) []*unstructured.Unstructured {
+	if prefix == "" {
+		return crs
+	}
+
+	prefixedCrs := make([]*unstructured.Unstructured, 0, len(crs))
+	for _, cr := range crs {
+		cr = cr.DeepCopy()
+		if slices.Contains(prefixedKinds, cr.GetObjectKind().GroupVersionKind().Kind) {
+			cr.SetName(prefix + cr.GetName())
+		}
+
+		for _, fields := range referenceFields {
+			name, _, _ := unstructured.NestedString(cr.Object, fields...)
+			if name == "" {
+				continue
+			}
+			if err := unstructured.SetNestedField(cr.Object, prefix+name, fields...); err != nil {
+				slog.Error("couldn't prefix reference", slog.String("CR", crName(cr)), slog.Any("error", err))
+			}
+		}
+
+		ownerReferences := cr.GetOwnerReferences()
+		for i := range ownerReferences {
+			gv, err := schema.ParseGroupVersion(ownerReferences[i].APIVersion)
+			if err == nil && gv.Group == ipamv1alphav1.SchemeGroupVersion.Group &&
+				slices.Contains(prefixedKinds, ownerReferences[i].Kind) {
+				ownerReferences[i].Name = prefix + ownerReferences[i].Name
+			}
+		}
+		cr.SetOwnerReferences(ownerReferences)
+
+		prefixedCrs = append(prefixedCrs, cr)
+	}
+	return prefixedCrs
+}
+
+// checkOwners returns an error if CR owners are neither moved nor present in the target cluster.
+func checkOwners(ctx context.Context, targetClient client.Client, crs []*unstructured.Unstructured) error {
+	uids := make(map[types.UID]bool, len(crs))
+	for _, cr := range crs {
+		uids[cr.GetUID()] = true
+	}
+
+	missingOwnerErrs := make([]error, 0)
+	for _, cr := range crs {
+		for _, ownerReference := range cr.GetOwnerReferences() {
+			if uids[ownerReference.UID] {
+				continue
+			}
+
+			owner := &unstructured.Unstructured{}
+			owner.SetAPIVersion(ownerReference.APIVersion)
+			owner.SetKind(ownerReference.Kind)
+			err := targetClient.Get(ctx, client.ObjectKey{Namespace: cr.GetNamespace(), Name: ownerReference.Name}, owner)
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				missingOwnerErrs = append(missingOwnerErrs, fmt.Errorf(
+					"owner %s %s of CR %s is neither moved nor present in the target cluster",
+					ownerReference.Kind, ownerReference.Name, crName(cr)))
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to check owner existence in the target cluster: %w", err)
+			}
+		}
+	}
+	return errors.Join(missingOwnerErrs...)
+}
+
 func clearFields(obj client.Object) map[string]any {
 	so, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
 
@@ -70,9 +155,16 @@ func getCrsToBeMoved(
 	ctx context.Context,
 	targetClient client.Client,
 	sourceCrs []*unstructured.Unstructured,
+	state *moveState,
 ) ([]*unstructured.Unstructured, error) {
 	crsToMove := make([]*unstructured.Unstructured, 0, len(sourceCrs))
 	for _, sourceCr := range sourceCrs {
+		// CRs created by a previous run of a resumed move are moved again to finish copying their status.
+		if state.created(crName(sourceCr)) {
+			crsToMove = append(crsToMove, sourceCr)
+			continue
+		}
+
 		targetCr := sourceCr.DeepCopy()
 		err := targetClient.Get(ctx, client.ObjectKeyFromObject(sourceCr), targetCr)
 		if apierrors.IsNotFound(err) {
@@ -100,24 +192,89 @@ type Node struct {
 	Children []*Node
 }
 
-func crsOwnerReferenceTrees(crs []*unstructured.Unstructured) []*Node {
-	nodeMap := make(map[types.UID]*Node)
+// crParentName returns a name of the CR the given CR has to be moved after.
+// IPAM CRs refer to their parents by name in spec, and usually have no owner references.
+func crParentName(cr *unstructured.Unstructured) string {
+	// Subnet references may point to another namespace
+	parentName := func(kind string, fields ...string) string {
+		name, _, _ := unstructured.NestedString(cr.Object, append(fields, "name")...)
+		if name == "" {
+			return ""
+		}
+		namespace, _, _ := unstructured.NestedString(cr.Object, append(fields, "namespace")...)
+		if namespace == "" {
+			namespace = cr.GetNamespace()
+		}
+		return kind + ":" + namespace + "/" + name
+	}
+
+	switch cr.GetObjectKind().GroupVersionKind().Kind {
+	case "Subnet":
+		if name := parentName("Subnet", "spec", "parentSubnet"); name != "" {
+			return name
+		}
+		return parentName("Network", "spec", "network")
+	case "IP":
+		return parentName("Subnet", "spec", "subnet")
+	}
+	return ""
+}
+
+// crsDependencyTrees orders CRs in trees, where children depend on their parent
+// either with IPAM spec references or with owner references.
+func crsDependencyTrees(crs []*unstructured.Unstructured) []*Node {
+	nodeMap := make(map[string]*Node)
+	uidNodeMap := make(map[types.UID]*Node)
+	for _, cr := range crs {
+		node := &Node{Cr: cr}
+		nodeMap[crName(cr)] = node
+		uidNodeMap[cr.GetUID()] = node
+	}
 
+	parents := make(map[*Node]*Node)
 	for _, cr := range crs {
-		nodeMap[cr.GetUID()] = &Node{Cr: cr}
+		node := nodeMap[crName(cr)]
+		if parent, ok := nodeMap[crParentName(cr)]; ok {
+			parents[node] = parent
+			continue
+		}
+		if ownerReferences := cr.GetOwnerReferences(); len(ownerReferences) > 0 {
+			if owner, ok := uidNodeMap[ownerReferences[0].UID]; ok {
+				parents[node] = owner
+			}
+		}
 	}
+
 	roots := []*Node{}
 	for _, cr := range crs {
-		if len(cr.GetOwnerReferences()) == 0 || nodeMap[cr.GetOwnerReferences()[0].UID] == nil {
-			roots = append(roots, nodeMap[cr.GetUID()])
-		} else {
-			owner := nodeMap[cr.GetOwnerReferences()[0].UID]
-			owner.Children = append(owner.Children, nodeMap[cr.GetUID()])
+		node := nodeMap[crName(cr)]
+		parent, ok := parents[node]
+		// CRs referring to themselves through their parents are moved as roots,
+		// otherwise they would never be moved.
+		if !ok || dependsOn(parents, parent, node) {
+			roots = append(roots, node)
+			continue
 		}
+		parent.Children = append(parent.Children, node)
 	}
 	return roots
 }
 
+// dependsOn checks if node is found in the parents chain of the child.
+func dependsOn(parents map[*Node]*Node, child, node *Node) bool {
+	for range len(parents) + 1 {
+		if child == node {
+			return true
+		}
+		parent, ok := parents[child]
+		if !ok {
+			return false
+		}
+		child = parent
+	}
+	return false
+}
+
 func cleanup(ctx context.Context, cl client.Client, crs []*unstructured.Unstructured) error {
 	cleanupErrs := make([]error, 0)
 	for _, cr := range crs {
@@ -132,23 +289,49 @@ func moveCrs(
 	ctx context.Context,
 	cl client.Client,
 	crsTrees []*Node,
-	ownerUid ...types.UID,
+	uids map[types.UID]types.UID,
+	state *moveState,
 ) ([]*unstructured.Unstructured, error) {
 	movedCrs := make([]*unstructured.Unstructured, 0)
 
 	for _, crsTree := range crsTrees {
 		cr := crsTree.Cr.DeepCopy()
+		if state.created(crName(cr)) {
+			if err := cl.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+				err = fmt.Errorf("CR %s created by a previous move couldn't be fetched from the target cluster: %w",
+					crName(cr), err)
+				return movedCrs, err
+			}
+			uids[crsTree.Cr.GetUID()] = cr.GetUID()
+			movedCrs = append(movedCrs, cr)
+			continue
+		}
+
 		ownerReferences := cr.GetOwnerReferences()
-		if len(ownerReferences) == 1 && len(ownerUid) == 1 {
-			ownerReferences[0].UID = ownerUid[0]
-			cr.SetOwnerReferences(ownerReferences)
+		for i := range ownerReferences {
+			if uid, ok := uids[ownerReferences[i].UID]; ok {
+				ownerReferences[i].UID = uid
+			}
 		}
+		cr.SetOwnerReferences(ownerReferences)
 		cr.SetResourceVersion("")
+		// Target controllers should not process the CR until its status is copied,
+		// otherwise they would try to reserve the same resources again.
+		annotations := cr.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[ipamv1alphav1.ReconcilePausedAnnotation] = "true"
+		cr.SetAnnotations(annotations)
 		if err := cl.Create(ctx, cr); err != nil {
 			err = fmt.Errorf("CR %s couldn't be created in the target cluster: %w", crName(cr), err)
 			return movedCrs, err
 		}
+		uids[crsTree.Cr.GetUID()] = cr.GetUID()
 		movedCrs = append(movedCrs, cr)
+		if err := state.addCreated(crName(cr)); err != nil {
+			return movedCrs, err
+		}
 	}
 
 	for _, crsTree := range crsTrees {
@@ -159,12 +342,15 @@ func moveCrs(
 				return false, client.IgnoreNotFound(err)
 			}
 
-			if err := copyStatus(ctx, cl, crsTree.Cr, cr); err != nil {
-				return false, err
+			// CRs with resumed reconciliation were completely moved by a previous run of a resumed move
+			if ipamv1alphav1.IsReconcilePaused(cr.GetAnnotations()) {
+				if err := copyStatus(ctx, cl, crsTree.Cr, cr); err != nil {
+					return false, err
+				}
 			}
 
 			// create children CRs
-			movedChildrenCrs, err := moveCrs(ctx, cl, crsTree.Children, cr.GetUID())
+			movedChildrenCrs, err := moveCrs(ctx, cl, crsTree.Children, uids, state)
 			movedCrs = slices.Concat(movedCrs, movedChildrenCrs)
 			return true, err
 		})
@@ -176,6 +362,76 @@ func moveCrs(
 	return movedCrs, nil
 }
 
+// resumeCrs lets target controllers process moved CRs again.
+func resumeCrs(ctx context.Context, cl client.Client, crs []*unstructured.Unstructured) error {
+	for _, cr := range crs {
+		targetCr := cr.DeepCopy()
+		if err := cl.Get(ctx, client.ObjectKeyFromObject(cr), targetCr); err != nil {
+			return fmt.Errorf("CR %s couldn't be fetched from the target cluster: %w", crName(cr), err)
+		}
+		base := targetCr.DeepCopy()
+		annotations := targetCr.GetAnnotations()
+		delete(annotations, ipamv1alphav1.ReconcilePausedAnnotation)
+		targetCr.SetAnnotations(annotations)
+		if err := cl.Patch(ctx, targetCr, client.MergeFrom(base)); err != nil {
+			return fmt.Errorf("reconciliation of CR %s couldn't be resumed in the target cluster: %w", crName(cr), err)
+		}
+	}
+	return nil
+}
+
+var capacityFields = [][]string{
+	{"status", "capacity"},
+	{"status", "capacityLeft"},
+	{"status", "ipv4Capacity"},
+	{"status", "ipv6Capacity"},
+}
+
+// compareCapacities returns an error if capacities in the target CR status differ from the source CR.
+func compareCapacities(sourceCr, targetCr *unstructured.Unstructured) error {
+	for _, fields := range capacityFields {
+		sourceValue, found, err := unstructured.NestedString(sourceCr.Object, fields...)
+		if err != nil || !found {
+			continue
+		}
+		targetValue, _, err := unstructured.NestedString(targetCr.Object, fields...)
+		if err != nil {
+			return err
+		}
+		sourceQuantity, err := resource.ParseQuantity(sourceValue)
+		if err != nil {
+			return err
+		}
+		targetQuantity, err := resource.ParseQuantity(targetValue)
+		if err != nil || sourceQuantity.Cmp(targetQuantity) != 0 {
+			return fmt.Errorf("CR %s has %s %q in the source cluster and %q in the target cluster",
+				crName(sourceCr), strings.Join(fields, "."), sourceValue, targetValue)
+		}
+	}
+	return nil
+}
+
+// verifyCapacities waits until capacities of moved CRs in the target cluster match the source cluster.
+func verifyCapacities(ctx context.Context, cl client.Client, sourceCrs []*unstructured.Unstructured) error {
+	var mismatchErr error
+	err := wait.PollUntilContextTimeout(ctx, pollInterval, pollTimeout, true, func(ctx context.Context) (bool, error) {
+		for _, sourceCr := range sourceCrs {
+			targetCr := sourceCr.DeepCopy()
+			if err := cl.Get(ctx, client.ObjectKeyFromObject(sourceCr), targetCr); err != nil {
+				return false, err
+			}
+			if mismatchErr = compareCapacities(sourceCr, targetCr); mismatchErr != nil {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if mismatchErr != nil {
+		return fmt.Errorf("target cluster capacities don't match the source cluster: %w", mismatchErr)
+	}
+	return err
+}
+
 func copyStatus(ctx context.Context, cl client.Client, sourceCr, targetCr *unstructured.Unstructured) error {
 	status, found, err := unstructured.NestedMap(sourceCr.Object, "status")
 	if err != nil {
@@ -191,39 +447,77 @@ func copyStatus(ctx context.Context, cl client.Client, sourceCr, targetCr *unstr
 	return cl.Status().Update(ctx, targetCr)
 }
 
+// MoveOptions configures which CRs are moved and how.
+type MoveOptions struct {
+	// Namespace limits moved CRs to a single namespace. CRs from all namespaces are moved if empty.
+	Namespace string
+	// Selector limits moved CRs to the ones matching labels. All CRs are moved if nil.
+	Selector labels.Selector
+	// RequireOwners refuses the move if CR owners are neither moved nor present in the target cluster.
+	RequireOwners bool
+	// NamePrefix is prepended to names of moved Networks, Subnets and IPs, and to references to them.
+	NamePrefix string
+	// StateFile is a path to a local file recording the move progress. If set, a failed move is not cleaned up
+	// and can be resumed by running it again with the same state file.
+	StateFile string
+	// DryRun only logs CRs to be moved.
+	DryRun bool
+}
+
 func Move(
 	ctx context.Context,
 	clients Clients,
 	crsGvk []schema.GroupVersionKind,
-	namespace string,
-	dryRun bool,
+	opts MoveOptions,
 ) error {
-	sourceCrs, err := getCrs(ctx, clients.Source, crsGvk, namespace)
+	sourceCrs, err := getCrs(ctx, clients.Source, crsGvk, opts.Namespace, opts.Selector)
 	if err != nil {
 		return err
 	}
+	sourceCrs = prefixNames(sourceCrs, opts.NamePrefix)
 	slog.Debug(fmt.Sprintf("found %s CRs in the source cluster", ipamv1alphav1.SchemeGroupVersion.Group),
 		slog.Any("CRs", transform(sourceCrs, crName)))
 
-	crsToMove, err := getCrsToBeMoved(ctx, clients.Target, sourceCrs)
+	if opts.RequireOwners {
+		if err := checkOwners(ctx, clients.Target, sourceCrs); err != nil {
+			return fmt.Errorf("CRs with missing owners can't be moved: %w", err)
+		}
+	}
+
+	state, err := loadMoveState(opts.StateFile)
+	if err != nil {
+		return err
+	}
+
+	crsToMove, err := getCrsToBeMoved(ctx, clients.Target, sourceCrs, state)
 	if err != nil {
 		return err
 	}
 	slog.Debug("moving", slog.Any("CRs", transform(crsToMove, crName)))
 
-	if !dryRun {
-		crsTrees := crsOwnerReferenceTrees(crsToMove)
-		var movedCrs []*unstructured.Unstructured
-		if movedCrs, err = moveCrs(ctx, clients.Target, crsTrees); err != nil {
-			cleanupErr := cleanup(ctx, clients.Target, movedCrs)
-			err = errors.Join(err,
-				fmt.Errorf("clean up of CRs was performed to restore a target cluster's state with error result: %w",
-					cleanupErr))
-		} else {
-			slog.Debug(fmt.Sprintf("all %s CRs from the source cluster were moved to the target cluster",
-				ipamv1alphav1.SchemeGroupVersion.Group))
-		}
+	if opts.DryRun {
+		return nil
 	}
 
-	return err
+	crsTrees := crsDependencyTrees(crsToMove)
+	movedCrs, err := moveCrs(ctx, clients.Target, crsTrees, make(map[types.UID]types.UID), state)
+	if err == nil {
+		err = resumeCrs(ctx, clients.Target, movedCrs)
+	}
+	if err != nil && state.resumable() {
+		return fmt.Errorf("%w; the move can be resumed with the state file %s", err, opts.StateFile)
+	}
+	if err != nil {
+		cleanupErr := cleanup(ctx, clients.Target, movedCrs)
+		return errors.Join(err,
+			fmt.Errorf("clean up of CRs was performed to restore a target cluster's state with error result: %w",
+				cleanupErr))
+	}
+	slog.Debug(fmt.Sprintf("all %s CRs from the source cluster were moved to the target cluster",
+		ipamv1alphav1.SchemeGroupVersion.Group))
+
+	if err := state.remove(); err != nil {
+		return err
+	}
+	return verifyCapacities(ctx, clients.Target, crsToMove)
 }
@@ -6,13 +6,17 @@ package cmdutils
 import (
 	"context"
 	"log/slog"
+	"path/filepath"
 
 	ipamv1alphav1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
 )
@@ -52,15 +56,33 @@ var _ = Describe("ipamctl move", func() {
 		sourceSubnet.Spec.CIDR = testCidr
 		sourceSubnet = create(ctx, clients.Source, sourceSubnet)
 
+		sourceChildSubnet := namedObj(&ipamv1alphav1.Subnet{}, "child-subnet")
+		sourceChildSubnet.Spec.Network.Name = sourceNetwork.Name
+		sourceChildSubnet.Spec.ParentSubnet.Name = sourceSubnet.Name
+		sourceChildSubnet.Spec.PrefixBits = ptr.To[byte](28)
+		sourceChildSubnet = create(ctx, clients.Source, sourceChildSubnet)
+
 		sourceIP := namedObj(&ipamv1alphav1.IP{}, "ip")
-		sourceIP.Spec.Subnet.Name = sourceSubnet.Name
+		sourceIP.Spec.Subnet.Name = sourceChildSubnet.Name
 		sourceIP.Spec.IP = ipamv1alphav1.IPMustParse(testIP)
 		sourceIP = create(ctx, clients.Source, sourceIP)
 
+		// statuses as they would be set by the source cluster controllers
+		Expect(sourceNetwork.Reserve(testCidr)).To(Succeed())
+		Expect(clients.Source.Status().Update(ctx, sourceNetwork)).To(Succeed())
+		sourceSubnet.FillStatusFromCidr(testCidr)
+		childCidr := ipamv1alphav1.CidrMustParse(testIP + "/28")
+		Expect(sourceSubnet.Reserve(childCidr)).To(Succeed())
+		Expect(clients.Source.Status().Update(ctx, sourceSubnet)).To(Succeed())
+		sourceChildSubnet.FillStatusFromCidr(childCidr)
+		Expect(sourceChildSubnet.Reserve(sourceIP.Spec.IP.AsCidr())).To(Succeed())
+		Expect(clients.Source.Status().Update(ctx, sourceChildSubnet)).To(Succeed())
+
 		// target cluster setup
 		create(ctx, clients.Target, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}})
 		targetNetwork := namedObj(&ipamv1alphav1.Network{}, sourceNetwork.Name)
 		targetSubnet := namedObj(&ipamv1alphav1.Subnet{}, sourceSubnet.Name)
+		targetChildSubnet := namedObj(&ipamv1alphav1.Subnet{}, sourceChildSubnet.Name)
 		targetIP := namedObj(&ipamv1alphav1.IP{}, sourceIP.Name)
 
 		// TEST
@@ -69,19 +91,120 @@ var _ = Describe("ipamctl move", func() {
 			crsSchema = append(crsSchema,
 				schema.GroupVersionKind{Group: "ipam.metal.ironcore.dev", Version: "v1alpha1", Kind: crdKind})
 		}
-		err := Move(context.TODO(), clients, crsSchema, "", false)
+		err := Move(context.TODO(), clients, crsSchema, MoveOptions{})
 		Expect(err).ToNot(HaveOccurred())
 
 		SetClient(clients.Target)
 
 		Eventually(Get(targetNetwork)).Should(Succeed())
+		Expect(targetNetwork.Annotations).NotTo(HaveKey(ipamv1alphav1.ReconcilePausedAnnotation))
+		Expect(targetNetwork.Status.IPv4Capacity.Cmp(sourceNetwork.Status.IPv4Capacity)).To(BeZero())
 
 		Eventually(Get(targetSubnet)).Should(Succeed())
 		Expect(targetSubnet.Spec.Network.Name).To(Equal(targetNetwork.Name))
 		Expect(targetSubnet.Spec.CIDR).To(Equal(testCidr))
+		Expect(targetSubnet.Status.CapacityLeft.Cmp(sourceSubnet.Status.CapacityLeft)).To(BeZero())
+
+		Eventually(Get(targetChildSubnet)).Should(Succeed())
+		Expect(targetChildSubnet.Spec.ParentSubnet.Name).To(Equal(targetSubnet.Name))
+		Expect(targetChildSubnet.Status.Reserved).To(Equal(sourceChildSubnet.Status.Reserved))
+		Expect(targetChildSubnet.Status.CapacityLeft.Cmp(sourceChildSubnet.Status.CapacityLeft)).To(BeZero())
 
 		Eventually(Get(targetIP)).Should(Succeed())
-		Expect(targetIP.Spec.Subnet.Name).To(Equal(targetSubnet.Name))
+		Expect(targetIP.Spec.Subnet.Name).To(Equal(targetChildSubnet.Name))
 		Expect(targetIP.Spec.IP).To(Equal(ipamv1alphav1.IPMustParse(testIP)))
+		Expect(targetIP.Annotations).NotTo(HaveKey(ipamv1alphav1.ReconcilePausedAnnotation))
+	})
+
+	It("Should order CRs by IPAM spec references", func() {
+		cr := func(kind, name string, spec map[string]any) *unstructured.Unstructured {
+			obj := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+			obj.SetGroupVersionKind(ipamv1alphav1.SchemeGroupVersion.WithKind(kind))
+			obj.SetNamespace(ns)
+			obj.SetName(name)
+			obj.SetUID(types.UID(name))
+			return obj
+		}
+		ref := func(name string) map[string]any {
+			return map[string]any{"name": name}
+		}
+
+		ip := cr("IP", "ip", map[string]any{"subnet": ref("child-subnet")})
+		childSubnet := cr("Subnet", "child-subnet",
+			map[string]any{"network": ref("network"), "parentSubnet": ref("subnet")})
+		subnet := cr("Subnet", "subnet", map[string]any{"network": ref("network")})
+		network := cr("Network", "network", map[string]any{})
+
+		trees := crsDependencyTrees([]*unstructured.Unstructured{ip, childSubnet, subnet, network})
+		Expect(trees).To(HaveLen(1))
+		Expect(trees[0].Cr).To(Equal(network))
+		Expect(trees[0].Children).To(HaveLen(1))
+		Expect(trees[0].Children[0].Cr).To(Equal(subnet))
+		Expect(trees[0].Children[0].Children).To(HaveLen(1))
+		Expect(trees[0].Children[0].Children[0].Cr).To(Equal(childSubnet))
+		Expect(trees[0].Children[0].Children[0].Children).To(HaveLen(1))
+		Expect(trees[0].Children[0].Children[0].Children[0].Cr).To(Equal(ip))
+	})
+
+	It("Should prefix names of CRs and references to them", func() {
+		subnet := &unstructured.Unstructured{Object: map[string]any{
+			"spec": map[string]any{
+				"network":      map[string]any{"name": "network"},
+				"parentSubnet": map[string]any{"name": "parent"},
+			},
+		}}
+		subnet.SetGroupVersionKind(ipamv1alphav1.SchemeGroupVersion.WithKind("Subnet"))
+		subnet.SetName("subnet")
+		subnet.SetOwnerReferences([]metav1.OwnerReference{
+			{APIVersion: ipamv1alphav1.SchemeGroupVersion.String(), Kind: "Network", Name: "network"},
+			{APIVersion: "v1", Kind: "ConfigMap", Name: "config"},
+		})
+		counter := &unstructured.Unstructured{Object: map[string]any{}}
+		counter.SetGroupVersionKind(ipamv1alphav1.SchemeGroupVersion.WithKind("NetworkCounter"))
+		counter.SetName("k8s-vxlan-network-counter")
+
+		prefixed := prefixNames([]*unstructured.Unstructured{subnet, counter}, "moved-")
+		Expect(prefixed[0].GetName()).To(Equal("moved-subnet"))
+		Expect(prefixed[0].Object["spec"]).To(Equal(map[string]any{
+			"network":      map[string]any{"name": "moved-network"},
+			"parentSubnet": map[string]any{"name": "moved-parent"},
+		}))
+		Expect(prefixed[0].GetOwnerReferences()[0].Name).To(Equal("moved-network"))
+		Expect(prefixed[0].GetOwnerReferences()[1].Name).To(Equal("config"))
+		Expect(prefixed[1].GetName()).To(Equal("k8s-vxlan-network-counter"))
+		Expect(subnet.GetName()).To(Equal("subnet"))
+	})
+
+	It("Should refuse to move CRs with missing owners", func(ctx SpecContext) {
+		owned := namedObj(&ipamv1alphav1.Network{}, "owned-network")
+		owned.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Name:       "missing-owner",
+			UID:        types.UID("missing-owner"),
+		}}
+		owned = create(ctx, clients.Source, owned)
+		DeferCleanup(clients.Source.Delete, owned)
+
+		crsSchema := []schema.GroupVersionKind{ipamv1alphav1.SchemeGroupVersion.WithKind("Network")}
+		err := Move(ctx, clients, crsSchema, MoveOptions{Namespace: ns, RequireOwners: true, DryRun: true})
+		Expect(err).To(MatchError(ContainSubstring("owner ConfigMap missing-owner")))
+	})
+
+	It("Should record the move progress in a state file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "state.json")
+
+		state, err := loadMoveState(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state.resumable()).To(BeTrue())
+		Expect(state.addCreated("Network:ns/network")).To(Succeed())
+
+		state, err = loadMoveState(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state.created("Network:ns/network")).To(BeTrue())
+		Expect(state.created("Subnet:ns/subnet")).To(BeFalse())
+
+		Expect(state.remove()).To(Succeed())
+		Expect(path).NotTo(BeAnExistingFile())
 	})
 })
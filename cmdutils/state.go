@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmdutils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// moveState is a progress of a resumable move, that is stored in a local state file.
+type moveState struct {
+	path string
+
+	// Created contains names of CRs already created in the target cluster.
+	Created []string `json:"created"`
+}
+
+// loadMoveState reads the move progress from the state file.
+// If path is empty, the progress is not recorded.
+func loadMoveState(path string) (*moveState, error) {
+	state := &moveState{path: path}
+	if path == "" {
+		return state, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read move state file: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse move state file %s: %w", path, err)
+	}
+	return state, nil
+}
+
+func (s *moveState) resumable() bool {
+	return s.path != ""
+}
+
+func (s *moveState) created(name string) bool {
+	return slices.Contains(s.Created, name)
+}
+
+// addCreated records the CR as created in the target cluster.
+func (s *moveState) addCreated(name string) error {
+	if s.created(name) {
+		return nil
+	}
+	s.Created = append(s.Created, name)
+	return s.save()
+}
+
+func (s *moveState) save() error {
+	if !s.resumable() {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	// write to a temporary file first, so the state file stays consistent if the move is interrupted
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write move state file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write move state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write move state file: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// remove deletes the state file once the move is completed.
+func (s *moveState) remove() error {
+	if !s.resumable() {
+		return nil
+	}
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove move state file: %w", err)
+	}
+	return nil
+}
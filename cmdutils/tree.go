@@ -0,0 +1,305 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmdutils
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	ipamv1alphav1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+const (
+	TableOutputFormat = "table"
+	JSONOutputFormat  = "json"
+	YAMLOutputFormat  = "yaml"
+)
+
+// OutputFormats are formats supported by ipamctl commands printing resources.
+var OutputFormats = []string{TableOutputFormat, JSONOutputFormat, YAMLOutputFormat}
+
+// TreeNode is a Network, Subnet or IP in the IPAM resource hierarchy.
+type TreeNode struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// CIDR is a reserved CIDR of a Subnet, or a reserved address of an IP
+	CIDR  string `json:"cidr,omitempty"`
+	State string `json:"state,omitempty"`
+	// Capacity is a total address capacity; for Networks it is a capacity of shown top level Subnets
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+	// CapacityLeft is a remaining address capacity; for Networks it is a capacity left in shown top level Subnets
+	CapacityLeft *resource.Quantity `json:"capacityLeft,omitempty"`
+	// Utilization is a percentage of used address capacity
+	Utilization *float64 `json:"utilization,omitempty"`
+	// CapacityLow is a reason of the raised CapacityLow condition, if utilization has crossed a threshold
+	CapacityLow string                           `json:"capacityLow,omitempty"`
+	Consumer    *ipamv1alphav1.ResourceReference `json:"consumer,omitempty"`
+	Children    []*TreeNode                      `json:"children,omitempty"`
+}
+
+// TreeOptions filters resources shown in the tree.
+type TreeOptions struct {
+	// Namespace limits the tree to a single namespace. All namespaces are shown if empty.
+	Namespace string
+	// Network limits the tree to a single Network. All Networks are shown if empty.
+	Network string
+	// Family limits Subnets to the address family, IPv4 or IPv6. Both families are shown if empty.
+	Family ipamv1alphav1.SubnetAddressType
+	// Region limits Subnets to the ones present in the region. All Subnets are shown if empty.
+	Region string
+}
+
+// GetTree lists Networks, Subnets and IPs, and arranges them in a hierarchy.
+func GetTree(ctx context.Context, cl client.Client, opts TreeOptions) ([]*TreeNode, error) {
+	networks := &ipamv1alphav1.NetworkList{}
+	if err := cl.List(ctx, networks, client.InNamespace(opts.Namespace)); err != nil {
+		return nil, fmt.Errorf("couldn't list networks: %w", err)
+	}
+	subnets := &ipamv1alphav1.SubnetList{}
+	if err := cl.List(ctx, subnets, client.InNamespace(opts.Namespace)); err != nil {
+		return nil, fmt.Errorf("couldn't list subnets: %w", err)
+	}
+	ips := &ipamv1alphav1.IPList{}
+	if err := cl.List(ctx, ips, client.InNamespace(opts.Namespace)); err != nil {
+		return nil, fmt.Errorf("couldn't list ips: %w", err)
+	}
+	return buildTree(networks.Items, subnets.Items, ips.Items, opts), nil
+}
+
+func buildTree(
+	networks []ipamv1alphav1.Network,
+	subnets []ipamv1alphav1.Subnet,
+	ips []ipamv1alphav1.IP,
+	opts TreeOptions,
+) []*TreeNode {
+	key := func(namespace, name string) string {
+		return namespace + "/" + name
+	}
+
+	networkNodes := make(map[string]*TreeNode)
+	roots := make([]*TreeNode, 0)
+	for _, network := range networks {
+		if opts.Network != "" && network.Name != opts.Network {
+			continue
+		}
+		node := &TreeNode{
+			Kind:        "Network",
+			Namespace:   network.Namespace,
+			Name:        network.Name,
+			State:       string(network.Status.State),
+			CapacityLow: capacityLow(network.Status.Conditions),
+		}
+		networkNodes[key(network.Namespace, network.Name)] = node
+		roots = append(roots, node)
+	}
+
+	subnetNodes := make(map[string]*TreeNode)
+	for _, subnet := range subnets {
+		if !subnetMatches(&subnet, opts) {
+			continue
+		}
+		subnetNodes[key(subnet.Namespace, subnet.Name)] = subnetNode(&subnet)
+	}
+
+	for _, subnet := range subnets {
+		node, ok := subnetNodes[key(subnet.Namespace, subnet.Name)]
+		if !ok {
+			continue
+		}
+		if subnet.Spec.ParentSubnet.Name == "" {
+			if network, ok := networkNodes[key(subnet.Namespace, subnet.Spec.Network.Name)]; ok {
+				network.Children = append(network.Children, node)
+			}
+			continue
+		}
+		parentName := subnet.Spec.ParentSubnet.NamespacedName(subnet.Namespace)
+		if parent, ok := subnetNodes[key(parentName.Namespace, parentName.Name)]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+
+	for _, ip := range ips {
+		parentName := ip.Spec.Subnet.NamespacedName(ip.Namespace)
+		parent, ok := subnetNodes[key(parentName.Namespace, parentName.Name)]
+		if !ok {
+			continue
+		}
+		node := &TreeNode{
+			Kind:      "IP",
+			Namespace: ip.Namespace,
+			Name:      ip.Name,
+			State:     string(ip.Status.State),
+			Consumer:  ip.Spec.Consumer,
+		}
+		if ip.Status.Reserved != nil {
+			node.CIDR = ip.Status.Reserved.String()
+		}
+		parent.Children = append(parent.Children, node)
+	}
+
+	for _, network := range roots {
+		capacity := resource.Quantity{}
+		capacityLeft := resource.Quantity{}
+		for _, subnet := range network.Children {
+			capacity.Add(*subnet.Capacity)
+			capacityLeft.Add(*subnet.CapacityLeft)
+		}
+		network.Capacity = &capacity
+		network.CapacityLeft = &capacityLeft
+		network.Utilization = utilization(network.Capacity, network.CapacityLeft)
+	}
+
+	sortTree(roots)
+	return roots
+}
+
+func subnetMatches(subnet *ipamv1alphav1.Subnet, opts TreeOptions) bool {
+	if opts.Family != "" && subnet.Status.Type != opts.Family {
+		return false
+	}
+	if opts.Region != "" && !slices.ContainsFunc(subnet.Spec.Regions, func(region ipamv1alphav1.Region) bool {
+		return region.Name == opts.Region
+	}) {
+		return false
+	}
+	return true
+}
+
+func subnetNode(subnet *ipamv1alphav1.Subnet) *TreeNode {
+	node := &TreeNode{
+		Kind:         "Subnet",
+		Namespace:    subnet.Namespace,
+		Name:         subnet.Name,
+		State:        string(subnet.Status.State),
+		Capacity:     ptr.To(subnet.Status.Capacity.DeepCopy()),
+		CapacityLeft: ptr.To(subnet.Status.CapacityLeft.DeepCopy()),
+		CapacityLow:  capacityLow(subnet.Status.Conditions),
+		Consumer:     subnet.Spec.Consumer,
+	}
+	if subnet.Status.Reserved != nil {
+		node.CIDR = subnet.Status.Reserved.String()
+	}
+	node.Utilization = utilization(node.Capacity, node.CapacityLeft)
+	return node
+}
+
+// utilization returns a percentage of used capacity, or nil if capacity is unknown.
+func utilization(capacity, capacityLeft *resource.Quantity) *float64 {
+	if capacity == nil || capacity.IsZero() {
+		return nil
+	}
+	used := capacity.DeepCopy()
+	used.Sub(*capacityLeft)
+	percentage := used.AsApproximateFloat64() / capacity.AsApproximateFloat64() * 100
+	return &percentage
+}
+
+// capacityLow returns a reason of the CapacityLow condition, if it is raised.
+func capacityLow(conditions []metav1.Condition) string {
+	if !meta.IsStatusConditionTrue(conditions, ipamv1alphav1.CapacityLowCondition) {
+		return ""
+	}
+	return meta.FindStatusCondition(conditions, ipamv1alphav1.CapacityLowCondition).Reason
+}
+
+// sortTree orders nodes by kind, address and name, so Subnets are listed before IPs in address order.
+func sortTree(nodes []*TreeNode) {
+	slices.SortFunc(nodes, func(a, b *TreeNode) int {
+		return cmp.Or(
+			-strings.Compare(a.Kind, b.Kind),
+			compareAddresses(a.CIDR, b.CIDR),
+			strings.Compare(a.Namespace, b.Namespace),
+			strings.Compare(a.Name, b.Name),
+		)
+	})
+	for _, node := range nodes {
+		sortTree(node.Children)
+	}
+}
+
+func compareAddresses(a, b string) int {
+	aCidr, aErr := ParseAddress(a)
+	bCidr, bErr := ParseAddress(b)
+	switch {
+	case aErr != nil && bErr != nil:
+		return 0
+	case aErr != nil:
+		return 1
+	case bErr != nil:
+		return -1
+	}
+	return cmp.Or(aCidr.Net.Addr().Compare(bCidr.Net.Addr()), cmp.Compare(aCidr.Net.Bits(), bCidr.Net.Bits()))
+}
+
+// PrintTree writes the tree in the output format.
+func PrintTree(w io.Writer, nodes []*TreeNode, format string) error {
+	switch format {
+	case JSONOutputFormat:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(nodes)
+	case YAMLOutputFormat:
+		data, err := yaml.Marshal(nodes)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case TableOutputFormat, "":
+		tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "NAME\tCIDR\tSTATE\tCAPACITY\tCAPACITY LEFT\tUTILIZATION\tCONSUMER")
+		for _, node := range nodes {
+			printTreeNode(tw, node, "", "")
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %s, expected any of %s", format, strings.Join(OutputFormats, ", "))
+	}
+}
+
+func printTreeNode(w io.Writer, node *TreeNode, prefix, childPrefix string) {
+	capacity, capacityLeft, utilizationPercentage, consumer := "", "", "", ""
+	if node.Capacity != nil {
+		capacity = node.Capacity.String()
+	}
+	if node.CapacityLeft != nil {
+		capacityLeft = node.CapacityLeft.String()
+	}
+	if node.Utilization != nil {
+		utilizationPercentage = fmt.Sprintf("%.2f%%", *node.Utilization)
+	}
+	switch node.CapacityLow {
+	case ipamv1alphav1.UtilizationWarningReason:
+		utilizationPercentage += " (warning)"
+	case ipamv1alphav1.UtilizationCriticalReason:
+		utilizationPercentage += " (critical)"
+	}
+	if node.Consumer != nil {
+		consumer = node.Consumer.Kind + "/" + node.Consumer.Name
+	}
+	_, _ = fmt.Fprintf(w, "%s%s %s/%s\t%s\t%s\t%s\t%s\t%s\t%s\n", prefix, node.Kind, node.Namespace, node.Name,
+		node.CIDR, node.State, capacity, capacityLeft, utilizationPercentage, consumer)
+
+	for i, child := range node.Children {
+		if i == len(node.Children)-1 {
+			printTreeNode(w, child, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			printTreeNode(w, child, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmdutils
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ipamv1alphav1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+var _ = Describe("ipamctl tree", func() {
+	newSubnet := func(name, cidr, network, parent string, regions ...string) ipamv1alphav1.Subnet {
+		subnet := ipamv1alphav1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: ipamv1alphav1.SubnetSpec{
+				CIDR:    ipamv1alphav1.CidrMustParse(cidr),
+				Network: v1.LocalObjectReference{Name: network},
+			},
+		}
+		if parent != "" {
+			subnet.Spec.ParentSubnet = ipamv1alphav1.SubnetReference{Name: parent}
+		}
+		for _, region := range regions {
+			subnet.Spec.Regions = append(subnet.Spec.Regions, ipamv1alphav1.Region{Name: region, AvailabilityZones: []string{"az"}})
+		}
+		subnet.FillStatusFromCidr(subnet.Spec.CIDR)
+		return subnet
+	}
+	newIP := func(name, addr, subnet string) ipamv1alphav1.IP {
+		ip, err := ipamv1alphav1.IPAddrFromString(addr)
+		Expect(err).NotTo(HaveOccurred())
+		return ipamv1alphav1.IP{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: ipamv1alphav1.IPSpec{
+				Subnet: ipamv1alphav1.SubnetReference{Name: subnet},
+			},
+			Status: ipamv1alphav1.IPStatus{
+				State:    ipamv1alphav1.FinishedIPState,
+				Reserved: ip,
+			},
+		}
+	}
+
+	var (
+		networks []ipamv1alphav1.Network
+		subnets  []ipamv1alphav1.Subnet
+		ips      []ipamv1alphav1.IP
+	)
+
+	BeforeEach(func() {
+		networks = []ipamv1alphav1.Network{{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "default"},
+		}, {
+			ObjectMeta: metav1.ObjectMeta{Name: "other-network", Namespace: "default"},
+		}}
+
+		parent := newSubnet("parent", "10.0.0.0/16", "network", "", "eu")
+		child := newSubnet("child", "10.0.1.0/24", "network", "parent", "eu")
+		firstChild := newSubnet("first-child", "10.0.0.0/24", "network", "parent", "eu")
+		Expect(parent.Reserve(child.Spec.CIDR)).To(Succeed())
+		Expect(parent.Reserve(firstChild.Spec.CIDR)).To(Succeed())
+		Expect(child.Reserve(ipamv1alphav1.CidrMustParse("10.0.1.1/32"))).To(Succeed())
+		child.Status.Conditions = []metav1.Condition{{
+			Type:   ipamv1alphav1.CapacityLowCondition,
+			Status: metav1.ConditionTrue,
+			Reason: ipamv1alphav1.UtilizationCriticalReason,
+		}}
+		v6 := newSubnet("v6", "fd00::/64", "network", "", "us")
+		subnets = []ipamv1alphav1.Subnet{child, v6, parent, firstChild}
+		ips = []ipamv1alphav1.IP{newIP("ip", "10.0.1.1", "child")}
+	})
+
+	It("Should arrange Networks, Subnets and IPs in a hierarchy", func() {
+		nodes := buildTree(networks, subnets, ips, TreeOptions{})
+
+		Expect(nodes).To(HaveLen(2))
+		network := nodes[0]
+		Expect(network.Name).To(Equal("network"))
+		Expect(network.Children).To(HaveLen(2))
+		Expect(network.Children[0].Name).To(Equal("parent"))
+		Expect(network.Children[1].Name).To(Equal("v6"))
+
+		parent := network.Children[0]
+		Expect(parent.CIDR).To(Equal("10.0.0.0/16"))
+		Expect(parent.Children).To(HaveLen(2))
+		Expect(parent.Children[0].Name).To(Equal("first-child"))
+		Expect(parent.Children[1].Name).To(Equal("child"))
+		Expect(parent.CapacityLeft.Value()).To(Equal(int64(65536 - 512)))
+		Expect(*parent.Utilization).To(BeNumerically("~", 0.78, 0.01))
+
+		Expect(parent.CapacityLow).To(BeEmpty())
+
+		child := parent.Children[1]
+		Expect(child.CapacityLow).To(Equal(ipamv1alphav1.UtilizationCriticalReason))
+		Expect(child.Children).To(HaveLen(1))
+		Expect(child.Children[0].Kind).To(Equal("IP"))
+		Expect(child.Children[0].CIDR).To(Equal("10.0.1.1"))
+
+		Expect(nodes[1].Name).To(Equal("other-network"))
+		Expect(nodes[1].Children).To(BeEmpty())
+		Expect(nodes[1].Utilization).To(BeNil())
+	})
+
+	It("Should attach IPs referring Subnets of another namespace", func() {
+		tenantIP := newIP("tenant-ip", "10.0.1.2", "child")
+		tenantIP.Namespace = "tenant"
+		tenantIP.Spec.Subnet.Namespace = "default"
+		ips = append(ips, tenantIP)
+
+		nodes := buildTree(networks, subnets, ips, TreeOptions{})
+		child := nodes[0].Children[0].Children[1]
+		Expect(child.Children).To(HaveLen(2))
+		Expect(child.Children[1].Namespace).To(Equal("tenant"))
+		Expect(child.Children[1].Name).To(Equal("tenant-ip"))
+	})
+
+	It("Should filter Subnets by network, family and region", func() {
+		nodes := buildTree(networks, subnets, ips, TreeOptions{Network: "network", Family: ipamv1alphav1.IPv6SubnetType})
+		Expect(nodes).To(HaveLen(1))
+		Expect(nodes[0].Children).To(HaveLen(1))
+		Expect(nodes[0].Children[0].Name).To(Equal("v6"))
+
+		nodes = buildTree(networks, subnets, ips, TreeOptions{Network: "network", Region: "eu"})
+		Expect(nodes).To(HaveLen(1))
+		Expect(nodes[0].Children).To(HaveLen(1))
+		Expect(nodes[0].Children[0].Name).To(Equal("parent"))
+		Expect(nodes[0].Capacity.Value()).To(Equal(int64(65536)))
+	})
+
+	It("Should print the tree", func() {
+		nodes := buildTree(networks, subnets, ips, TreeOptions{Network: "network", Region: "eu"})
+
+		out := &bytes.Buffer{}
+		Expect(PrintTree(out, nodes, TableOutputFormat)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("Network default/network"))
+		Expect(out.String()).To(ContainSubstring("└── Subnet default/parent"))
+		Expect(out.String()).To(ContainSubstring("    ├── Subnet default/first-child"))
+		Expect(out.String()).To(ContainSubstring("        └── IP default/ip"))
+		Expect(out.String()).To(ContainSubstring("0.39% (critical)"))
+
+		out.Reset()
+		Expect(PrintTree(out, nodes, JSONOutputFormat)).To(Succeed())
+		var printed []TreeNode
+		Expect(json.Unmarshal(out.Bytes(), &printed)).To(Succeed())
+		Expect(printed).To(HaveLen(1))
+		Expect(printed[0].Children[0].Children).To(HaveLen(2))
+
+		Expect(PrintTree(out, nodes, "xml")).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,198 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmdutils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	ipamv1alphav1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+// WhoisObject is a Network, Subnet or IP owning a looked up address.
+type WhoisObject struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// CIDR is a reserved CIDR of a Subnet, or a reserved address of an IP
+	CIDR              string                           `json:"cidr,omitempty"`
+	Consumer          *ipamv1alphav1.ResourceReference `json:"consumer,omitempty"`
+	CreationTimestamp metav1.Time                      `json:"creationTimestamp"`
+}
+
+// WhoisResult describes which objects of a Network own a looked up address.
+type WhoisResult struct {
+	Network WhoisObject `json:"network"`
+	// Subnets is a chain of Subnets containing the address, from the top level Subnet to the most specific one
+	Subnets []WhoisObject `json:"subnets,omitempty"`
+	// IP is an IP object with exactly the looked up address, if any
+	IP *WhoisObject `json:"ip,omitempty"`
+}
+
+// ParseAddress parses an address or a CIDR.
+func ParseAddress(address string) (*ipamv1alphav1.CIDR, error) {
+	if cidr, err := ipamv1alphav1.CIDRFromString(address); err == nil {
+		return cidr, nil
+	}
+	ip, err := ipamv1alphav1.IPAddrFromString(address)
+	if err != nil {
+		return nil, fmt.Errorf("%s is neither an ip address nor a cidr", address)
+	}
+	return ip.AsCidr(), nil
+}
+
+// Whois looks up Networks, Subnets and IPs owning the address or CIDR.
+// Since address spaces of Networks may overlap, there is a result for every Network containing the address.
+func Whois(ctx context.Context, cl client.Client, namespace string, cidr *ipamv1alphav1.CIDR) ([]WhoisResult, error) {
+	networks := &ipamv1alphav1.NetworkList{}
+	if err := cl.List(ctx, networks, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("couldn't list networks: %w", err)
+	}
+	subnets := &ipamv1alphav1.SubnetList{}
+	if err := cl.List(ctx, subnets, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("couldn't list subnets: %w", err)
+	}
+	ips := &ipamv1alphav1.IPList{}
+	if err := cl.List(ctx, ips, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("couldn't list ips: %w", err)
+	}
+	return whois(networks.Items, subnets.Items, ips.Items, cidr), nil
+}
+
+func whois(
+	networks []ipamv1alphav1.Network,
+	subnets []ipamv1alphav1.Subnet,
+	ips []ipamv1alphav1.IP,
+	cidr *ipamv1alphav1.CIDR,
+) []WhoisResult {
+	results := make([]WhoisResult, 0)
+	for _, network := range networks {
+		// the most specific Subnet of the Network containing the address
+		var owner *ipamv1alphav1.Subnet
+		for i := range subnets {
+			subnet := &subnets[i]
+			if subnet.Namespace != network.Namespace || subnet.Spec.Network.Name != network.Name ||
+				subnet.Status.Reserved == nil || !subnet.Status.Reserved.CanReserve(cidr) {
+				continue
+			}
+			if owner == nil || subnet.Status.Reserved.MaskOnes() > owner.Status.Reserved.MaskOnes() {
+				owner = subnet
+			}
+		}
+
+		inRanges := slices.ContainsFunc(slices.Concat(network.Status.IPv4Ranges, network.Status.IPv6Ranges),
+			func(r ipamv1alphav1.CIDR) bool {
+				return r.CanReserve(cidr)
+			})
+		if owner == nil && !inRanges {
+			continue
+		}
+
+		result := WhoisResult{
+			Network: WhoisObject{
+				Kind:              "Network",
+				Namespace:         network.Namespace,
+				Name:              network.Name,
+				CreationTimestamp: network.CreationTimestamp,
+			},
+		}
+		if owner == nil {
+			results = append(results, result)
+			continue
+		}
+
+		for subnet := owner; subnet != nil; subnet = findSubnet(subnets, subnet.Spec.ParentSubnet.NamespacedName(subnet.Namespace)) {
+			result.Subnets = append(result.Subnets, WhoisObject{
+				Kind:              "Subnet",
+				Namespace:         subnet.Namespace,
+				Name:              subnet.Name,
+				CIDR:              subnet.Status.Reserved.String(),
+				Consumer:          subnet.Spec.Consumer,
+				CreationTimestamp: subnet.CreationTimestamp,
+			})
+		}
+		slices.Reverse(result.Subnets)
+
+		for _, ip := range ips {
+			if ip.Spec.Subnet.NamespacedName(ip.Namespace) == client.ObjectKeyFromObject(owner) &&
+				ip.Status.Reserved != nil && ip.Status.Reserved.AsCidr().Equal(cidr) {
+				result.IP = &WhoisObject{
+					Kind:              "IP",
+					Namespace:         ip.Namespace,
+					Name:              ip.Name,
+					CIDR:              ip.Status.Reserved.String(),
+					Consumer:          ip.Spec.Consumer,
+					CreationTimestamp: ip.CreationTimestamp,
+				}
+				break
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func findSubnet(subnets []ipamv1alphav1.Subnet, name types.NamespacedName) *ipamv1alphav1.Subnet {
+	if name.Name == "" {
+		return nil
+	}
+	for i := range subnets {
+		if subnets[i].Namespace == name.Namespace && subnets[i].Name == name.Name {
+			return &subnets[i]
+		}
+	}
+	return nil
+}
+
+// PrintWhois writes the lookup results in the output format.
+func PrintWhois(w io.Writer, results []WhoisResult, format string) error {
+	switch format {
+	case JSONOutputFormat:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	case YAMLOutputFormat:
+		data, err := yaml.Marshal(results)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case TableOutputFormat, "":
+		tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "KIND\tNAME\tCIDR\tCONSUMER\tCREATED")
+		for _, result := range results {
+			printWhoisObject(tw, result.Network)
+			for _, subnet := range result.Subnets {
+				printWhoisObject(tw, subnet)
+			}
+			if result.IP != nil {
+				printWhoisObject(tw, *result.IP)
+			}
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %s, expected any of %s", format, strings.Join(OutputFormats, ", "))
+	}
+}
+
+func printWhoisObject(w io.Writer, obj WhoisObject) {
+	consumer := ""
+	if obj.Consumer != nil {
+		consumer = obj.Consumer.Kind + "/" + obj.Consumer.Name
+	}
+	_, _ = fmt.Fprintf(w, "%s\t%s/%s\t%s\t%s\t%s\n", obj.Kind, obj.Namespace, obj.Name, obj.CIDR, consumer,
+		obj.CreationTimestamp.UTC().Format(time.RFC3339))
+}
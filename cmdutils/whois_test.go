@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmdutils
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ipamv1alphav1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+var _ = Describe("ipamctl whois", func() {
+	var (
+		networks []ipamv1alphav1.Network
+		subnets  []ipamv1alphav1.Subnet
+		ips      []ipamv1alphav1.IP
+	)
+
+	BeforeEach(func() {
+		newSubnet := func(namespace, name, cidr, parent string) ipamv1alphav1.Subnet {
+			subnet := ipamv1alphav1.Subnet{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec: ipamv1alphav1.SubnetSpec{
+					Network:      v1.LocalObjectReference{Name: "network"},
+					ParentSubnet: ipamv1alphav1.SubnetReference{Name: parent},
+				},
+			}
+			subnet.FillStatusFromCidr(ipamv1alphav1.CidrMustParse(cidr))
+			return subnet
+		}
+
+		networks = []ipamv1alphav1.Network{{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "first"},
+			Status: ipamv1alphav1.NetworkStatus{
+				IPv4Ranges: []ipamv1alphav1.CIDR{*ipamv1alphav1.CidrMustParse("10.0.0.0/16")},
+			},
+		}, {
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "second"},
+			Status: ipamv1alphav1.NetworkStatus{
+				IPv4Ranges: []ipamv1alphav1.CIDR{*ipamv1alphav1.CidrMustParse("10.0.0.0/8")},
+			},
+		}}
+		subnets = []ipamv1alphav1.Subnet{
+			newSubnet("first", "parent", "10.0.0.0/16", ""),
+			newSubnet("first", "child", "10.0.4.0/24", "parent"),
+			newSubnet("first", "other-child", "10.0.5.0/24", "parent"),
+		}
+		subnets[1].Spec.Consumer = &ipamv1alphav1.ResourceReference{Kind: "Cluster", Name: "c1"}
+		ips = []ipamv1alphav1.IP{{
+			ObjectMeta: metav1.ObjectMeta{Name: "ip", Namespace: "first"},
+			Spec: ipamv1alphav1.IPSpec{
+				Subnet:   ipamv1alphav1.SubnetReference{Name: "child"},
+				Consumer: &ipamv1alphav1.ResourceReference{Kind: "Machine", Name: "m1"},
+			},
+			Status: ipamv1alphav1.IPStatus{Reserved: ipamv1alphav1.IPMustParse("10.0.4.5")},
+		}}
+	})
+
+	It("Should find the most specific Subnet and the IP owning an address", func() {
+		cidr, err := ParseAddress("10.0.4.5")
+		Expect(err).NotTo(HaveOccurred())
+
+		results := whois(networks, subnets, ips, cidr)
+		Expect(results).To(HaveLen(2))
+
+		Expect(results[0].Network.Namespace).To(Equal("first"))
+		Expect(results[0].Subnets).To(HaveLen(2))
+		Expect(results[0].Subnets[0].Name).To(Equal("parent"))
+		Expect(results[0].Subnets[1].Name).To(Equal("child"))
+		Expect(results[0].Subnets[1].Consumer.Name).To(Equal("c1"))
+		Expect(results[0].IP).NotTo(BeNil())
+		Expect(results[0].IP.Name).To(Equal("ip"))
+		Expect(results[0].IP.Consumer.Name).To(Equal("m1"))
+
+		Expect(results[1].Network.Namespace).To(Equal("second"))
+		Expect(results[1].Subnets).To(BeEmpty())
+		Expect(results[1].IP).To(BeNil())
+
+		out := &bytes.Buffer{}
+		Expect(PrintWhois(out, results, TableOutputFormat)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("first/child"))
+		Expect(out.String()).To(ContainSubstring("Machine/m1"))
+	})
+
+	It("Should look up a CIDR", func() {
+		cidr, err := ParseAddress("10.0.5.128/25")
+		Expect(err).NotTo(HaveOccurred())
+
+		results := whois(networks, subnets, ips, cidr)
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Subnets[len(results[0].Subnets)-1].Name).To(Equal("other-child"))
+		Expect(results[0].IP).To(BeNil())
+
+		cidr, err = ParseAddress("192.168.0.0/24")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(whois(networks, subnets, ips, cidr)).To(BeEmpty())
+
+		_, err = ParseAddress("not-an-address")
+		Expect(err).To(HaveOccurred())
+	})
+})
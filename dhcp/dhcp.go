@@ -0,0 +1,292 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package dhcp exports Subnets and IPs as DHCP server configuration,
+// in Kea subnet definitions or dnsmasq configuration format.
+package dhcp
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ipamv1alpha1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+const (
+	// KeaDHCP4Format is a JSON list of Kea DHCPv4 subnet4 definitions
+	KeaDHCP4Format = "kea-dhcp4"
+	// KeaDHCP6Format is a JSON list of Kea DHCPv6 subnet6 definitions
+	KeaDHCP6Format = "kea-dhcp6"
+	// DnsmasqFormat is dnsmasq configuration of both address families
+	DnsmasqFormat = "dnsmasq"
+)
+
+// Formats are supported output formats.
+var Formats = []string{KeaDHCP4Format, KeaDHCP6Format, DnsmasqFormat}
+
+// Export is DHCP configuration of Subnets.
+type Export struct {
+	// Subnets are ordered by namespace and name
+	Subnets []Subnet
+}
+
+// Subnet is DHCP configuration of a Subnet.
+type Subnet struct {
+	Namespace string
+	Name      string
+	// ID is a stable identifier of the Subnet derived from its namespace and name
+	ID uint32
+	// Prefix is the reserved CIDR of the Subnet
+	Prefix netip.Prefix
+	// Pools are ranges of vacant addresses of the Subnet, which are leased dynamically.
+	// Addresses of child Subnets and IPs, the gateway, and the network and broadcast addresses of IPv4 on-link prefixes
+	// are excluded.
+	Pools []Range
+	// Config is the network configuration of the Subnet
+	Config ipamv1alpha1.NetworkConfig
+	// Hosts are static reservations of IPs with hardware addresses, ordered by address
+	Hosts []Host
+}
+
+// Range is an inclusive range of addresses.
+type Range struct {
+	Start netip.Addr
+	End   netip.Addr
+}
+
+// Host is a static reservation of an address for a hardware address.
+type Host struct {
+	MAC     string
+	Address netip.Addr
+}
+
+// MACFunc returns the hardware address of the IP, or an empty string if it has none.
+type MACFunc func(ctx context.Context, ip *ipamv1alpha1.IP) (string, error)
+
+// IPsFunc lists IPs referring the Subnet, which may reside in other namespaces than the Subnet.
+type IPsFunc func(ctx context.Context, subnet types.NamespacedName) ([]ipamv1alpha1.IP, error)
+
+// Collect lists Subnets of the namespace matching the selector, and builds their export with the IPs reserved in them.
+// Subnets are looked up in all namespaces if the namespace is empty.
+func Collect(ctx context.Context, cl client.Reader, namespace string, selector labels.Selector, ips IPsFunc, mac MACFunc) (*Export, error) {
+	subnets := &ipamv1alpha1.SubnetList{}
+	if err := cl.List(ctx, subnets, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("couldn't list subnets: %w", err)
+	}
+	var reserved []ipamv1alpha1.IP
+	for i := range subnets.Items {
+		subnet := &subnets.Items[i]
+		if subnet.Status.State != ipamv1alpha1.FinishedSubnetState {
+			continue
+		}
+		items, err := ips(ctx, client.ObjectKeyFromObject(subnet))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't list ips of subnet %s/%s: %w", subnet.Namespace, subnet.Name, err)
+		}
+		reserved = append(reserved, items...)
+	}
+	return Build(ctx, subnets.Items, reserved, mac)
+}
+
+// ReferringIPs returns IPsFunc, which lists IPs of all namespaces once and selects IPs referring the Subnet.
+// If IPs may not be listed in all namespaces, only IPs of the namespace are considered.
+func ReferringIPs(cl client.Reader, namespace string) IPsFunc {
+	var ips *ipamv1alpha1.IPList
+	return func(ctx context.Context, subnet types.NamespacedName) ([]ipamv1alpha1.IP, error) {
+		if ips == nil {
+			list := &ipamv1alpha1.IPList{}
+			err := cl.List(ctx, list)
+			if apierrors.IsForbidden(err) && namespace != "" {
+				err = cl.List(ctx, list, client.InNamespace(namespace))
+			}
+			if err != nil {
+				return nil, err
+			}
+			ips = list
+		}
+		var referring []ipamv1alpha1.IP
+		for _, ip := range ips.Items {
+			if ip.Spec.Subnet.NamespacedName(ip.Namespace) == subnet {
+				referring = append(referring, ip)
+			}
+		}
+		return referring, nil
+	}
+}
+
+// ConsumerMAC returns MACFunc, which takes the hardware address from the annotation of the IP,
+// or of its consumer, if the IP is not annotated. Consumers which can not be read are considered to have no address.
+func ConsumerMAC(cl client.Reader) MACFunc {
+	return func(ctx context.Context, ip *ipamv1alpha1.IP) (string, error) {
+		if mac, ok := ip.Annotations[ipamv1alpha1.MACAddressAnnotation]; ok {
+			return mac, nil
+		}
+		consumer := ip.Spec.Consumer
+		if consumer == nil || consumer.APIVersion == "" || consumer.Kind == "" {
+			return "", nil
+		}
+		gv, err := schema.ParseGroupVersion(consumer.APIVersion)
+		if err != nil {
+			return "", nil
+		}
+		obj := &metav1.PartialObjectMetadata{}
+		obj.SetGroupVersionKind(gv.WithKind(consumer.Kind))
+		err = cl.Get(ctx, types.NamespacedName{Namespace: ip.Namespace, Name: consumer.Name}, obj)
+		if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) || meta.IsNoMatchError(err) {
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("couldn't get consumer %s/%s of ip %s/%s: %w",
+				consumer.Kind, consumer.Name, ip.Namespace, ip.Name, err)
+		}
+		return obj.Annotations[ipamv1alpha1.MACAddressAnnotation], nil
+	}
+}
+
+// Build builds the export of finished Subnets, with static reservations of IPs having a valid hardware address.
+func Build(ctx context.Context, subnets []ipamv1alpha1.Subnet, ips []ipamv1alpha1.IP, mac MACFunc) (*Export, error) {
+	export := &Export{}
+	ids := map[uint32]types.NamespacedName{}
+	for i := range subnets {
+		subnet := &subnets[i]
+		if subnet.Status.State != ipamv1alpha1.FinishedSubnetState || subnet.Status.Reserved == nil {
+			continue
+		}
+		name := types.NamespacedName{Namespace: subnet.Namespace, Name: subnet.Name}
+		id := subnetID(name)
+		if other, ok := ids[id]; ok {
+			return nil, fmt.Errorf("subnets %s and %s have the same id %d", other, name, id)
+		}
+		ids[id] = name
+
+		exported := Subnet{
+			Namespace: subnet.Namespace,
+			Name:      subnet.Name,
+			ID:        id,
+			Prefix:    subnet.Status.Reserved.Net,
+		}
+		if subnet.Status.NetworkConfig != nil {
+			exported.Config = *subnet.Status.NetworkConfig.DeepCopy()
+		} else {
+			exported.Config.PrefixLength = subnet.Status.Reserved.MaskOnes()
+		}
+		exported.Pools = pools(subnet, exported.Config.PrefixLength)
+
+		for j := range ips {
+			ip := &ips[j]
+			if ip.Spec.Subnet.NamespacedName(ip.Namespace) != name ||
+				ip.Status.State != ipamv1alpha1.FinishedIPState || ip.Status.Reserved == nil {
+				continue
+			}
+			value, err := mac(ctx, ip)
+			if err != nil {
+				return nil, err
+			}
+			hw, err := net.ParseMAC(value)
+			if err != nil {
+				continue
+			}
+			exported.Hosts = append(exported.Hosts, Host{MAC: hw.String(), Address: ip.Status.Reserved.Net})
+		}
+		slices.SortFunc(exported.Hosts, func(a, b Host) int {
+			return a.Address.Compare(b.Address)
+		})
+
+		export.Subnets = append(export.Subnets, exported)
+	}
+	slices.SortFunc(export.Subnets, func(a, b Subnet) int {
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+	return export, nil
+}
+
+// subnetID derives a Kea subnet id from the Subnet name, within the range of valid ids 1..2^32-2.
+func subnetID(name types.NamespacedName) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name.String()))
+	return h.Sum32()%(math.MaxUint32-1) + 1
+}
+
+// pools merges adjacent vacant CIDRs of the Subnet into ranges.
+// Network and broadcast addresses of the on-link prefix of IPv4 Subnets are excluded.
+func pools(subnet *ipamv1alpha1.Subnet, prefixLength byte) []Range {
+	vacant := slices.Clone(subnet.Status.Vacant)
+	slices.SortFunc(vacant, func(a, b ipamv1alpha1.CIDR) int {
+		return a.Net.Addr().Compare(b.Net.Addr())
+	})
+
+	var ranges []Range
+	for i := range vacant {
+		start, end := vacant[i].ToAddressRange()
+		if len(ranges) > 0 && ranges[len(ranges)-1].End.Next() == start {
+			ranges[len(ranges)-1].End = end
+			continue
+		}
+		ranges = append(ranges, Range{Start: start, End: end})
+	}
+
+	prefix, err := subnet.Status.Reserved.Net.Addr().Prefix(int(prefixLength))
+	if err != nil || !prefix.Addr().Is4() || prefix.Bits() >= 31 {
+		return ranges
+	}
+	network, broadcast := (&ipamv1alpha1.CIDR{Net: prefix}).ToAddressRange()
+	result := ranges[:0]
+	for _, r := range ranges {
+		if r.Start == network {
+			r.Start = r.Start.Next()
+		}
+		if r.End == broadcast {
+			r.End = r.End.Prev()
+		}
+		if r.Start.Compare(r.End) <= 0 {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// onLinkMask returns the netmask of the on-link prefix, if it differs from the Subnet prefix.
+func (s *Subnet) onLinkMask() (net.IPMask, bool) {
+	bits := s.Prefix.Addr().BitLen()
+	return net.CIDRMask(int(s.Config.PrefixLength), bits), int(s.Config.PrefixLength) != s.Prefix.Bits()
+}
+
+// dnsServers returns DNS servers of the address family.
+func (s *Subnet) dnsServers(is4 bool) []string {
+	var servers []string
+	for _, server := range s.Config.DNSServers {
+		if server.Net.Is4() == is4 {
+			servers = append(servers, server.String())
+		}
+	}
+	return servers
+}
+
+// Render writes the export in the format.
+func Render(w io.Writer, export *Export, format string) error {
+	switch format {
+	case KeaDHCP4Format:
+		return renderKea(w, export, true)
+	case KeaDHCP6Format:
+		return renderKea(w, export, false)
+	case DnsmasqFormat:
+		return renderDnsmasq(w, export)
+	default:
+		return fmt.Errorf("unknown format %s, expected any of %s", format, strings.Join(Formats, ", "))
+	}
+}
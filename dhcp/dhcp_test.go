@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package dhcp
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ipamv1alpha1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+var _ = Describe("DHCP export", func() {
+	var (
+		subnets []ipamv1alpha1.Subnet
+		ips     []ipamv1alpha1.IP
+	)
+
+	annotationMAC := func(_ context.Context, ip *ipamv1alpha1.IP) (string, error) {
+		return ip.Annotations[ipamv1alpha1.MACAddressAnnotation], nil
+	}
+
+	newSubnet := func(name, cidr string, reserved ...string) ipamv1alpha1.Subnet {
+		subnet := ipamv1alpha1.Subnet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		subnet.FillStatusFromCidr(ipamv1alpha1.CidrMustParse(cidr))
+		subnet.Status.State = ipamv1alpha1.FinishedSubnetState
+		for _, r := range reserved {
+			Expect(subnet.Reserve(ipamv1alpha1.CidrMustParse(r))).To(Succeed())
+		}
+		return subnet
+	}
+
+	newIP := func(name, subnet, address, mac string) ipamv1alpha1.IP {
+		ip := ipamv1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       ipamv1alpha1.IPSpec{Subnet: ipamv1alpha1.SubnetReference{Name: subnet}},
+			Status: ipamv1alpha1.IPStatus{
+				State:    ipamv1alpha1.FinishedIPState,
+				Reserved: ipamv1alpha1.IPMustParse(address),
+			},
+		}
+		if mac != "" {
+			ip.Annotations = map[string]string{ipamv1alpha1.MACAddressAnnotation: mac}
+		}
+		return ip
+	}
+
+	BeforeEach(func() {
+		mtu := int32(9000)
+		v4 := newSubnet("v4", "10.0.0.0/24", "10.0.0.1/32", "10.0.0.10/32", "10.0.0.11/32", "10.0.0.12/32", "10.0.0.64/26")
+		v4.Status.NetworkConfig = &ipamv1alpha1.NetworkConfig{
+			PrefixLength:  24,
+			Gateway:       ipamv1alpha1.IPMustParse("10.0.0.1"),
+			DNSServers:    []ipamv1alpha1.IPAddr{*ipamv1alpha1.IPMustParse("10.0.0.53"), *ipamv1alpha1.IPMustParse("fd00::53")},
+			SearchDomains: []string{"example.com"},
+			MTU:           &mtu,
+		}
+		child := newSubnet("child", "10.1.2.0/24")
+		child.Status.NetworkConfig = &ipamv1alpha1.NetworkConfig{
+			PrefixLength: 16,
+			Gateway:      ipamv1alpha1.IPMustParse("10.1.0.1"),
+		}
+		v6 := newSubnet("v6", "fd00::/120", "fd00::10/128")
+		v6.Status.NetworkConfig = &ipamv1alpha1.NetworkConfig{
+			PrefixLength: 120,
+			DNSServers:   []ipamv1alpha1.IPAddr{*ipamv1alpha1.IPMustParse("10.0.0.53"), *ipamv1alpha1.IPMustParse("fd00::53")},
+		}
+		pending := newSubnet("pending", "10.2.0.0/24")
+		pending.Status.State = ipamv1alpha1.ProcessingSubnetState
+		subnets = []ipamv1alpha1.Subnet{v6, v4, child, pending}
+
+		ips = []ipamv1alpha1.IP{
+			newIP("host-b", "v4", "10.0.0.11", "AA-BB-CC-DD-EE-02"),
+			newIP("host-a", "v4", "10.0.0.10", "aa:bb:cc:dd:ee:01"),
+			newIP("invalid-mac", "v4", "10.0.0.12", "not-a-mac"),
+			newIP("host-v6", "v6", "fd00::10", "aa:bb:cc:dd:ee:01"),
+		}
+	})
+
+	DescribeTable("Should render the export in the format",
+		func(format, golden string) {
+			export, err := Build(context.Background(), subnets, ips, annotationMAC)
+			Expect(err).NotTo(HaveOccurred())
+
+			out := &bytes.Buffer{}
+			Expect(Render(out, export, format)).To(Succeed())
+
+			path := filepath.Join("testdata", golden)
+			if *update {
+				Expect(os.WriteFile(path, out.Bytes(), 0644)).To(Succeed())
+			}
+			expected, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out.String()).To(Equal(string(expected)))
+		},
+		Entry("Kea DHCPv4", KeaDHCP4Format, "kea-dhcp4.json"),
+		Entry("Kea DHCPv6", KeaDHCP6Format, "kea-dhcp6.json"),
+		Entry("dnsmasq", DnsmasqFormat, "dnsmasq.conf"),
+	)
+
+	It("Should exclude reserved addresses from pools", func() {
+		export, err := Build(context.Background(), subnets, ips, annotationMAC)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(export.Subnets).To(HaveLen(3))
+
+		v4 := export.Subnets[1]
+		Expect(v4.Name).To(Equal("v4"))
+		Expect(v4.Pools).To(HaveLen(3))
+		Expect(v4.Pools[0].Start.String()).To(Equal("10.0.0.2"))
+		Expect(v4.Pools[0].End.String()).To(Equal("10.0.0.9"))
+		Expect(v4.Pools[2].Start.String()).To(Equal("10.0.0.128"))
+		Expect(v4.Pools[2].End.String()).To(Equal("10.0.0.254"))
+		Expect(v4.Hosts).To(HaveLen(2))
+		Expect(v4.Hosts[1].MAC).To(Equal("aa:bb:cc:dd:ee:02"))
+	})
+
+	It("Should keep subnet ids stable", func() {
+		export, err := Build(context.Background(), subnets, ips, annotationMAC)
+		Expect(err).NotTo(HaveOccurred())
+		other, err := Build(context.Background(), subnets[:2], nil, annotationMAC)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(other.Subnets[0].ID).To(Equal(export.Subnets[1].ID))
+		Expect(other.Subnets[1].ID).To(Equal(export.Subnets[2].ID))
+	})
+})
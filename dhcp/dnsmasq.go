@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package dhcp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// renderDnsmasq writes dnsmasq configuration of Subnets of both address families.
+// Options and hosts of a Subnet are bound to its range with a tag named after the Subnet.
+func renderDnsmasq(w io.Writer, export *Export) error {
+	bw := bufio.NewWriter(w)
+	for i := range export.Subnets {
+		subnet := &export.Subnets[i]
+		is4 := subnet.Prefix.Addr().Is4()
+		// Underscore can't be a part of Kubernetes names, so tags of different Subnets can't clash
+		tag := subnet.Namespace + "_" + subnet.Name
+
+		if i > 0 {
+			_, _ = fmt.Fprintln(bw)
+		}
+		_, _ = fmt.Fprintf(bw, "# Subnet %s/%s %s\n", subnet.Namespace, subnet.Name, subnet.Prefix.String())
+
+		mask := fmt.Sprint(subnet.Config.PrefixLength)
+		if is4 {
+			onLinkMask, _ := subnet.onLinkMask()
+			mask = net.IP(onLinkMask).String()
+		}
+		for _, pool := range subnet.Pools {
+			_, _ = fmt.Fprintf(bw, "dhcp-range=set:%s,%s,%s,%s\n", tag, pool.Start, pool.End, mask)
+		}
+		if len(subnet.Pools) == 0 {
+			_, _ = fmt.Fprintf(bw, "dhcp-range=set:%s,%s,static,%s\n", tag, subnet.Prefix.Addr(), mask)
+		}
+
+		if is4 {
+			if subnet.Config.Gateway != nil {
+				_, _ = fmt.Fprintf(bw, "dhcp-option=tag:%s,option:router,%s\n", tag, subnet.Config.Gateway)
+			}
+			if servers := subnet.dnsServers(true); len(servers) > 0 {
+				_, _ = fmt.Fprintf(bw, "dhcp-option=tag:%s,option:dns-server,%s\n", tag, strings.Join(servers, ","))
+			}
+			if len(subnet.Config.SearchDomains) > 0 {
+				_, _ = fmt.Fprintf(bw, "dhcp-option=tag:%s,option:domain-search,%s\n", tag, strings.Join(subnet.Config.SearchDomains, ","))
+			}
+			if subnet.Config.MTU != nil {
+				_, _ = fmt.Fprintf(bw, "dhcp-option=tag:%s,option:mtu,%d\n", tag, *subnet.Config.MTU)
+			}
+		} else {
+			if servers := subnet.dnsServers(false); len(servers) > 0 {
+				_, _ = fmt.Fprintf(bw, "dhcp-option=tag:%s,option6:dns-server,[%s]\n", tag, strings.Join(servers, "],["))
+			}
+			if len(subnet.Config.SearchDomains) > 0 {
+				_, _ = fmt.Fprintf(bw, "dhcp-option=tag:%s,option6:domain-search,%s\n", tag, strings.Join(subnet.Config.SearchDomains, ","))
+			}
+		}
+
+		for _, host := range subnet.Hosts {
+			address := host.Address.String()
+			if !is4 {
+				address = "[" + address + "]"
+			}
+			_, _ = fmt.Fprintf(bw, "dhcp-host=%s,set:%s,%s\n", host.MAC, tag, address)
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package dhcp
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// keaSubnet is a subnet4 or subnet6 definition of Kea configuration.
+type keaSubnet struct {
+	ID           uint32           `json:"id"`
+	Subnet       string           `json:"subnet"`
+	Pools        []keaPool        `json:"pools"`
+	OptionData   []keaOption      `json:"option-data,omitempty"`
+	Reservations []keaReservation `json:"reservations,omitempty"`
+	UserContext  keaUserContext   `json:"user-context"`
+}
+
+type keaPool struct {
+	Pool string `json:"pool"`
+}
+
+type keaOption struct {
+	Name string `json:"name"`
+	Data string `json:"data"`
+}
+
+type keaReservation struct {
+	HWAddress   string   `json:"hw-address"`
+	IPAddress   string   `json:"ip-address,omitempty"`
+	IPAddresses []string `json:"ip-addresses,omitempty"`
+}
+
+// keaUserContext refers the Subnet the definition is exported from.
+type keaUserContext struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// renderKea writes a JSON list of subnet definitions of the address family,
+// which may be included as subnet4 or subnet6 list of Kea configuration.
+func renderKea(w io.Writer, export *Export, is4 bool) error {
+	subnets := make([]keaSubnet, 0)
+	for i := range export.Subnets {
+		subnet := &export.Subnets[i]
+		if subnet.Prefix.Addr().Is4() != is4 {
+			continue
+		}
+
+		exported := keaSubnet{
+			ID:          subnet.ID,
+			Subnet:      subnet.Prefix.String(),
+			Pools:       make([]keaPool, 0, len(subnet.Pools)),
+			UserContext: keaUserContext{Namespace: subnet.Namespace, Name: subnet.Name},
+		}
+		for _, pool := range subnet.Pools {
+			exported.Pools = append(exported.Pools, keaPool{Pool: pool.Start.String() + " - " + pool.End.String()})
+		}
+		exported.OptionData = keaOptions(subnet, is4)
+		for _, host := range subnet.Hosts {
+			reservation := keaReservation{HWAddress: host.MAC}
+			if is4 {
+				reservation.IPAddress = host.Address.String()
+			} else {
+				reservation.IPAddresses = []string{host.Address.String()}
+			}
+			exported.Reservations = append(exported.Reservations, reservation)
+		}
+		subnets = append(subnets, exported)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(subnets)
+}
+
+func keaOptions(subnet *Subnet, is4 bool) []keaOption {
+	var options []keaOption
+	if !is4 {
+		if servers := subnet.dnsServers(false); len(servers) > 0 {
+			options = append(options, keaOption{Name: "dns-servers", Data: strings.Join(servers, ", ")})
+		}
+		if len(subnet.Config.SearchDomains) > 0 {
+			options = append(options, keaOption{Name: "domain-search", Data: strings.Join(subnet.Config.SearchDomains, ", ")})
+		}
+		return options
+	}
+
+	if mask, differs := subnet.onLinkMask(); differs {
+		options = append(options, keaOption{Name: "subnet-mask", Data: net.IP(mask).String()})
+	}
+	if subnet.Config.Gateway != nil {
+		options = append(options, keaOption{Name: "routers", Data: subnet.Config.Gateway.String()})
+	}
+	if servers := subnet.dnsServers(true); len(servers) > 0 {
+		options = append(options, keaOption{Name: "domain-name-servers", Data: strings.Join(servers, ", ")})
+	}
+	if len(subnet.Config.SearchDomains) > 0 {
+		options = append(options, keaOption{Name: "domain-search", Data: strings.Join(subnet.Config.SearchDomains, ", ")})
+	}
+	if subnet.Config.MTU != nil {
+		options = append(options, keaOption{Name: "interface-mtu", Data: strconv.Itoa(int(*subnet.Config.MTU))})
+	}
+	return options
+}
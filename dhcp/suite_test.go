@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package dhcp
+
+import (
+	"flag"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// update rewrites golden files with the rendered output instead of comparing it.
+var update = flag.Bool("update", false, "update golden files")
+
+func TestDHCP(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "DHCP Suite")
+}
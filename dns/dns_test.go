@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package dns
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	ipamv1alpha1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+var _ = Describe("DNS records", func() {
+	var (
+		subnet *ipamv1alpha1.Subnet
+		ips    []ipamv1alpha1.IP
+	)
+
+	newIP := func(name, address, consumer string) ipamv1alpha1.IP {
+		ip := ipamv1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       ipamv1alpha1.IPSpec{Subnet: ipamv1alpha1.SubnetReference{Name: "rack-1"}},
+			Status: ipamv1alpha1.IPStatus{
+				State:    ipamv1alpha1.FinishedIPState,
+				Reserved: ipamv1alpha1.IPMustParse(address),
+			},
+		}
+		if consumer != "" {
+			ip.Spec.Consumer = &ipamv1alpha1.ResourceReference{Kind: "Machine", Name: consumer}
+		}
+		return ip
+	}
+
+	expectGolden := func(golden, content string) {
+		path := filepath.Join("testdata", golden)
+		if *update {
+			Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		}
+		expected, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(content).To(Equal(string(expected)))
+	}
+
+	BeforeEach(func() {
+		ttl := int32(300)
+		subnet = &ipamv1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: "rack-1", Namespace: "default"},
+			Spec: ipamv1alpha1.SubnetSpec{
+				DNS: &ipamv1alpha1.SubnetDNS{
+					Zone:             "example.net",
+					HostnameTemplate: "{consumer.name}.{subnet.name}.{zone}",
+					TTL:              &ttl,
+					Nameservers:      []string{"ns1.example.net", "ns2.example.net"},
+				},
+			},
+		}
+		ips = []ipamv1alpha1.IP{
+			newIP("m1-v4", "10.0.1.10", "m1"),
+			newIP("m1-v6", "fd00::10", "m1"),
+			newIP("m2", "10.0.0.20", "M2"),
+			newIP("unconsumed", "10.0.0.30", ""),
+			newIP("invalid", "10.0.0.40", "not_a_host"),
+		}
+		ips = append(ips, newIP("pending", "10.0.0.50", "m3"))
+		ips[len(ips)-1].Status.State = ipamv1alpha1.ProcessingIPState
+	})
+
+	It("Should derive forward and reverse records of finished IPs", func() {
+		records, errs := Records(subnet, ips)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Error()).To(ContainSubstring("not_a_host"))
+
+		out, err := yaml.Marshal(NewDNSEndpoint("default", "rack-1-dns", records, subnet.Spec.DNS.TTL))
+		Expect(err).NotTo(HaveOccurred())
+		expectGolden("dnsendpoint.yaml", string(out))
+	})
+
+	It("Should omit reverse records if disabled", func() {
+		subnet.Spec.DNS.DisableReverse = true
+		records, _ := Records(subnet, ips)
+		Expect(records).NotTo(ContainElement(HaveField("Type", PTRRecordType)))
+	})
+
+	DescribeTable("Should render zone files aligned to the subnet",
+		func(cidr, golden string) {
+			records, _ := Records(subnet, ips)
+			files, err := ZoneFiles(subnet.Spec.DNS, netip.MustParsePrefix(cidr), records, 7)
+			Expect(err).NotTo(HaveOccurred())
+
+			keys := slices.Sorted(func(yield func(string) bool) {
+				for key := range files {
+					if !yield(key) {
+						return
+					}
+				}
+			})
+			var content strings.Builder
+			for _, key := range keys {
+				content.WriteString("; " + key + "\n" + files[key])
+			}
+			expectGolden(golden, content.String())
+		},
+		Entry("IPv4 prefix split into octet aligned zones", "10.0.0.0/23", "ipv4-split.zone"),
+		Entry("IPv4 prefix longer than /24", "10.0.1.0/26", "ipv4-classless.zone"),
+		Entry("IPv6 prefix", "fd00::/64", "ipv6.zone"),
+	)
+
+	It("Should align reverse zones to octet and nibble boundaries", func() {
+		names := func(prefix string) []string {
+			var names []string
+			for _, zone := range ReverseZones(netip.MustParsePrefix(prefix)) {
+				names = append(names, zone.Name)
+			}
+			return names
+		}
+		Expect(names("10.0.0.0/16")).To(Equal([]string{"0.10.in-addr.arpa"}))
+		Expect(names("10.0.2.0/23")).To(Equal([]string{"2.0.10.in-addr.arpa", "3.0.10.in-addr.arpa"}))
+		Expect(names("192.0.2.64/26")).To(Equal([]string{"64-26.2.0.192.in-addr.arpa"}))
+		Expect(names("2001:db8::/47")).To(Equal([]string{"0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa", "1.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa"}))
+	})
+})
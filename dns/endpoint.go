@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package dns
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DNSEndpointGVK is the kind of external-dns DNSEndpoint objects.
+var DNSEndpointGVK = schema.GroupVersionKind{Group: "externaldns.k8s.io", Version: "v1alpha1", Kind: "DNSEndpoint"}
+
+// NewDNSEndpoint builds an external-dns DNSEndpoint object holding the records.
+func NewDNSEndpoint(namespace, name string, records []Record, ttl *int32) *unstructured.Unstructured {
+	endpoints := make([]any, 0, len(records))
+	for _, record := range records {
+		targets := make([]any, 0, len(record.Targets))
+		for _, target := range record.Targets {
+			targets = append(targets, target)
+		}
+		endpoint := map[string]any{
+			"dnsName":    record.Name,
+			"recordType": record.Type,
+			"targets":    targets,
+		}
+		if ttl != nil {
+			endpoint["recordTTL"] = int64(*ttl)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(DNSEndpointGVK)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.Object["spec"] = map[string]any{"endpoints": endpoints}
+	return obj
+}
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package dns derives forward and reverse DNS records of IPs from the DNS configuration of their Subnets,
+// and renders them as RFC 1035 zone files.
+package dns
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	ipamv1alpha1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+const (
+	ARecordType    = "A"
+	AAAARecordType = "AAAA"
+	PTRRecordType  = "PTR"
+)
+
+// Record is a DNS record set, names and targets are fully qualified without the trailing dot.
+type Record struct {
+	Name    string
+	Type    string
+	Targets []string
+}
+
+// Hostname expands the hostname template of the Subnet for the IP.
+// False is returned if the template refers the consumer, but the IP has none.
+func Hostname(config *ipamv1alpha1.SubnetDNS, subnetName string, ip *ipamv1alpha1.IP) (string, bool, error) {
+	template := config.HostnameTemplate
+	if template == "" {
+		template = "{ip.name}.{zone}"
+	}
+
+	consumerKind, consumerName := "", ""
+	if ip.Spec.Consumer != nil {
+		consumerKind, consumerName = strings.ToLower(ip.Spec.Consumer.Kind), ip.Spec.Consumer.Name
+	} else if strings.Contains(template, "{consumer.") {
+		return "", false, nil
+	}
+
+	hostname := strings.NewReplacer(
+		"{ip.name}", ip.Name,
+		"{consumer.kind}", consumerKind,
+		"{consumer.name}", consumerName,
+		"{subnet.name}", subnetName,
+		"{namespace}", ip.Namespace,
+		"{zone}", config.Zone,
+	).Replace(template)
+	hostname = strings.TrimSuffix(strings.ToLower(hostname), ".")
+
+	if msgs := validation.IsDNS1123Subdomain(hostname); len(msgs) > 0 {
+		return "", false, fmt.Errorf("hostname %s of ip %s/%s is invalid: %s", hostname, ip.Namespace, ip.Name, strings.Join(msgs, ", "))
+	}
+	if !InZone(hostname, config.Zone) {
+		return "", false, fmt.Errorf("hostname %s of ip %s/%s is out of zone %s", hostname, ip.Namespace, ip.Name, config.Zone)
+	}
+	return hostname, true, nil
+}
+
+// InZone checks the name belongs to the zone.
+func InZone(name, zone string) bool {
+	zone = strings.TrimSuffix(strings.ToLower(zone), ".")
+	return name == zone || strings.HasSuffix(name, "."+zone)
+}
+
+// Records derives records of finished IPs of the Subnet, sorted by name and type.
+// IPs with invalid hostnames are skipped, and their errors are returned along with the records of other IPs.
+func Records(subnet *ipamv1alpha1.Subnet, ips []ipamv1alpha1.IP) ([]Record, []error) {
+	config := subnet.Spec.DNS
+	if config == nil {
+		return nil, nil
+	}
+
+	var errs []error
+	sets := map[[2]string][]string{}
+	for i := range ips {
+		ip := &ips[i]
+		if ip.Status.State != ipamv1alpha1.FinishedIPState || ip.Status.Reserved == nil {
+			continue
+		}
+		hostname, ok, err := Hostname(config, subnet.Name, ip)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		address := ip.Status.Reserved.Net
+		recordType := ARecordType
+		if address.Is6() {
+			recordType = AAAARecordType
+		}
+		key := [2]string{hostname, recordType}
+		sets[key] = append(sets[key], address.String())
+		if !config.DisableReverse {
+			key := [2]string{ReverseName(address), PTRRecordType}
+			sets[key] = append(sets[key], hostname)
+		}
+	}
+
+	records := make([]Record, 0, len(sets))
+	for key, targets := range sets {
+		slices.Sort(targets)
+		records = append(records, Record{Name: key[0], Type: key[1], Targets: slices.Compact(targets)})
+	}
+	slices.SortFunc(records, func(a, b Record) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Type, b.Type)
+	})
+	return records, errs
+}
+
+// ReverseName returns the in-addr.arpa or ip6.arpa name of the address.
+func ReverseName(address netip.Addr) string {
+	octets := address.AsSlice()
+	labels := make([]string, 0, 2*len(octets)+2)
+	for i := len(octets) - 1; i >= 0; i-- {
+		if address.Is4() {
+			labels = append(labels, fmt.Sprint(octets[i]))
+			continue
+		}
+		labels = append(labels, fmt.Sprintf("%x", octets[i]&0x0f), fmt.Sprintf("%x", octets[i]>>4))
+	}
+	if address.Is4() {
+		return strings.Join(append(labels, "in-addr", "arpa"), ".")
+	}
+	return strings.Join(append(labels, "ip6", "arpa"), ".")
+}
@@ -0,0 +1,211 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package dns
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	ipamv1alpha1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+// DefaultTTL is the TTL of zone files of Subnets without a TTL set.
+const DefaultTTL int32 = 3600
+
+// Zone is a reverse zone of a part of a Subnet.
+type Zone struct {
+	// Name is the zone name
+	Name string
+	// Prefix is the part of the Subnet the zone holds records of
+	Prefix netip.Prefix
+	// classless is set for RFC 2317 zones of IPv4 prefixes longer than /24,
+	// which are delegated from the parent zone by CNAME records
+	classless bool
+}
+
+// ReverseZones returns reverse zones aligned to the Subnet prefix. Prefixes not aligned to octet (IPv4) or nibble
+// (IPv6) boundaries are split into zones of the next boundary, and IPv4 prefixes longer than /24 get a single RFC 2317
+// zone named <first address>-<prefix length> under the /24 zone, e.g. 64-26.2.0.192.in-addr.arpa.
+func ReverseZones(prefix netip.Prefix) []Zone {
+	prefix = prefix.Masked()
+	step := 4
+	if prefix.Addr().Is4() {
+		step = 8
+	}
+	bits := prefix.Bits()
+
+	if prefix.Addr().Is4() && bits > 24 && bits%8 != 0 {
+		labels := strings.Split(ReverseName(prefix.Addr()), ".")
+		name := fmt.Sprintf("%s-%d.%s", labels[0], bits, strings.Join(labels[1:], "."))
+		return []Zone{{Name: name, Prefix: prefix, classless: true}}
+	}
+
+	boundary := (bits + step - 1) / step * step
+	zones := make([]Zone, 0, 1<<(boundary-bits))
+	for addr := prefix.Addr(); prefix.Contains(addr); {
+		zonePrefix := netip.PrefixFrom(addr, boundary)
+		zones = append(zones, Zone{Name: zoneName(zonePrefix, step), Prefix: zonePrefix})
+		addr = lastAddr(zonePrefix).Next()
+		if !addr.IsValid() {
+			break
+		}
+	}
+	return zones
+}
+
+func zoneName(prefix netip.Prefix, step int) string {
+	labels := strings.Split(ReverseName(prefix.Addr()), ".")
+	// the last two labels are in-addr.arpa or ip6.arpa
+	hostLabels := len(labels) - 2 - prefix.Bits()/step
+	return strings.Join(labels[hostLabels:], ".")
+}
+
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	octets := prefix.Addr().AsSlice()
+	for i := prefix.Bits(); i < len(octets)*8; i++ {
+		octets[i/8] |= 0x80 >> (i % 8)
+	}
+	addr, _ := netip.AddrFromSlice(octets)
+	return addr
+}
+
+// Records selects PTR records of the zone, owner names of RFC 2317 zones are moved under the zone.
+func (z *Zone) Records(records []Record) []Record {
+	var selected []Record
+	for _, record := range records {
+		if record.Type != PTRRecordType {
+			continue
+		}
+		addr, ok := parseReverseName(record.Name)
+		if !ok || !z.Prefix.Contains(addr) {
+			continue
+		}
+		if z.classless {
+			labels := strings.SplitN(record.Name, ".", 2)
+			record.Name = labels[0] + "." + z.Name
+		}
+		selected = append(selected, record)
+	}
+	return selected
+}
+
+// parseReverseName parses the in-addr.arpa or ip6.arpa name of an address.
+func parseReverseName(name string) (netip.Addr, bool) {
+	if labels, ok := strings.CutSuffix(name, ".in-addr.arpa"); ok {
+		octets := strings.Split(labels, ".")
+		if len(octets) != 4 {
+			return netip.Addr{}, false
+		}
+		var addr [4]byte
+		for i, octet := range octets {
+			value, err := strconv.ParseUint(octet, 10, 8)
+			if err != nil {
+				return netip.Addr{}, false
+			}
+			addr[3-i] = byte(value)
+		}
+		return netip.AddrFrom4(addr), true
+	}
+	if labels, ok := strings.CutSuffix(name, ".ip6.arpa"); ok {
+		nibbles := strings.Split(labels, ".")
+		if len(nibbles) != 32 {
+			return netip.Addr{}, false
+		}
+		var addr [16]byte
+		for i, nibble := range nibbles {
+			value, err := strconv.ParseUint(nibble, 16, 4)
+			if err != nil {
+				return netip.Addr{}, false
+			}
+			position := 31 - i
+			addr[position/2] |= byte(value) << (4 * (1 - position%2))
+		}
+		return netip.AddrFrom16(addr), true
+	}
+	return netip.Addr{}, false
+}
+
+// SOA holds parameters of the SOA and NS records of a zone.
+type SOA struct {
+	// Nameservers are authoritative nameservers of the zone, the first one is the primary
+	Nameservers []string
+	// Serial is the serial number of the zone
+	Serial uint32
+	// Mailbox is the mailbox of the zone administrator in domain name form, hostmaster of the zone if empty
+	Mailbox string
+}
+
+// RenderZone writes records of the zone in RFC 1035 format. SOA and NS records are written only if the SOA is set,
+// without them the zone file is a fragment, which may be included into a zone maintained elsewhere.
+func RenderZone(w io.Writer, origin string, records []Record, ttl int32, soa *SOA) error {
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintf(bw, "$ORIGIN %s.\n", origin)
+	_, _ = fmt.Fprintf(bw, "$TTL %d\n", ttl)
+	if soa != nil && len(soa.Nameservers) > 0 {
+		mailbox := soa.Mailbox
+		if mailbox == "" {
+			mailbox = "hostmaster." + origin
+		}
+		_, _ = fmt.Fprintf(bw, "@\tIN\tSOA\t%s. %s. %d 3600 600 1209600 %d\n",
+			strings.TrimSuffix(soa.Nameservers[0], "."), strings.TrimSuffix(mailbox, "."), soa.Serial, ttl)
+		for _, nameserver := range soa.Nameservers {
+			_, _ = fmt.Fprintf(bw, "@\tIN\tNS\t%s.\n", strings.TrimSuffix(nameserver, "."))
+		}
+	}
+	for _, record := range records {
+		owner := "@"
+		if record.Name != origin {
+			owner = strings.TrimSuffix(record.Name, "."+origin)
+		}
+		for _, target := range record.Targets {
+			if record.Type == PTRRecordType {
+				target += "."
+			}
+			_, _ = fmt.Fprintf(bw, "%s\tIN\t%s\t%s\n", owner, record.Type, target)
+		}
+	}
+	return bw.Flush()
+}
+
+// ZoneFiles renders the forward records as a zone file fragment of the forward zone, which is usually shared with
+// other Subnets, and the reverse records as zone files of reverse zones of the prefix, keyed by <zone name>.zone.
+// Reverse zones get SOA and NS records if nameservers are configured.
+func ZoneFiles(config *ipamv1alpha1.SubnetDNS, prefix netip.Prefix, records []Record, serial uint32) (map[string]string, error) {
+	ttl := DefaultTTL
+	if config.TTL != nil {
+		ttl = *config.TTL
+	}
+	files := map[string]string{}
+
+	zone := strings.TrimSuffix(strings.ToLower(config.Zone), ".")
+	var forward []Record
+	for _, record := range records {
+		if record.Type != PTRRecordType {
+			forward = append(forward, record)
+		}
+	}
+	out := &bytes.Buffer{}
+	if err := RenderZone(out, zone, forward, ttl, nil); err != nil {
+		return nil, err
+	}
+	files[zone+".zone"] = out.String()
+
+	if config.DisableReverse {
+		return files, nil
+	}
+	for _, reverse := range ReverseZones(prefix) {
+		out := &bytes.Buffer{}
+		soa := &SOA{Nameservers: config.Nameservers, Serial: serial, Mailbox: "hostmaster." + zone}
+		if err := RenderZone(out, reverse.Name, reverse.Records(records), ttl, soa); err != nil {
+			return nil, err
+		}
+		files[reverse.Name+".zone"] = out.String()
+	}
+	return files, nil
+}
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Command update-special-purpose-registry regenerates the special-purpose address registry embedded into the API
+// package from the IANA IPv4 and IPv6 Special-Purpose Address Registries.
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const header = `# Generated by hack/update-special-purpose-registry from the IANA IPv4 and IPv6 Special-Purpose Address Registries
+# and the IANA multicast address spaces. Private-Use and Unique-Local blocks are left out, since they are ordinary
+# address space of Networks. Run ` + "`make update-special-purpose-registry`" + ` to update.
+`
+
+var registries = []string{
+	"https://www.iana.org/assignments/iana-ipv4-special-registry/iana-ipv4-special-registry-1.csv",
+	"https://www.iana.org/assignments/iana-ipv6-special-registry/iana-ipv6-special-registry-1.csv",
+}
+
+// Multicast address spaces are maintained in registries of their own
+var multicast = map[int][]string{
+	0: {"224.0.0.0/4", "Multicast", "RFC5771"},
+	1: {"ff00::/8", "Multicast", "RFC4291"},
+}
+
+// excluded blocks are ordinary address space of Networks
+var excluded = []string{"Private-Use", "Unique-Local"}
+
+var (
+	footnote = regexp.MustCompile(`\s*\[\d+\]`)
+	rfc      = regexp.MustCompile(`RFC\d+`)
+)
+
+func main() {
+	output := flag.String("output", "api/ipam/v1alpha1/specialpurpose_registry.csv", "Path of the registry file.")
+	flag.Parse()
+
+	out := &bytes.Buffer{}
+	out.WriteString(header)
+	writer := csv.NewWriter(out)
+	_ = writer.Write([]string{"prefix", "name", "rfc"})
+
+	for i, url := range registries {
+		records, err := fetch(url)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "unable to fetch %s: %s\n", url, err)
+			os.Exit(1)
+		}
+		for _, record := range records {
+			if err := writer.Write(record); err != nil {
+				fmt.Fprintf(os.Stderr, "unable to write registry: %s\n", err)
+				os.Exit(1)
+			}
+		}
+		_ = writer.Write(multicast[i])
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to write registry: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*output, out.Bytes(), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to write %s: %s\n", *output, err)
+		os.Exit(1)
+	}
+}
+
+// fetch reads blocks of the registry as prefix, name and RFC records.
+// Address block cells may hold several blocks and footnote references, e.g. "192.0.0.170/32, 192.0.0.171/32 [5]".
+func fetch(url string) ([][]string, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks [][]string
+	// the first record is the header
+	for _, record := range records[1:] {
+		name := footnote.ReplaceAllString(record[1], "")
+		skip := false
+		for _, exclusion := range excluded {
+			skip = skip || strings.EqualFold(name, exclusion)
+		}
+		if skip {
+			continue
+		}
+		for _, block := range strings.Split(footnote.ReplaceAllString(record[0], ""), ",") {
+			prefix, err := netip.ParsePrefix(strings.TrimSpace(block))
+			if err != nil {
+				return nil, fmt.Errorf("invalid address block %q: %w", record[0], err)
+			}
+			blocks = append(blocks, []string{prefix.String(), name, rfc.FindString(record[2])})
+		}
+	}
+	return blocks, nil
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cni
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/containernetworking/cni/pkg/types"
+	"github.com/pkg/errors"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// NetConf is the network configuration passed to the plugin, only the ipam section is used.
+type NetConf struct {
+	types.NetConf
+	IPAM *IPAMConfig `json:"ipam"`
+}
+
+// IPAMConfig configures the Subnets IPs of the attachment are allocated from,
+// and the cluster the IP objects are created in.
+type IPAMConfig struct {
+	Type string `json:"type"`
+	// Kubeconfig is the path of the kubeconfig file, in-cluster config is used if empty
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// Subnets are the Subnets an IP is allocated from for each, e.g. an IPv4 and an IPv6 one.
+	// Name refers a Subnet of the Pod namespace, namespace/name refers a Subnet of another namespace.
+	Subnets []string `json:"subnets"`
+	// Timeout is the duration to wait for IPs being reserved, 30s if empty
+	Timeout string `json:"timeout,omitempty"`
+	// Gateway is returned for IPs of the Subnet containing it
+	Gateway string `json:"gateway,omitempty"`
+	// Routes are returned along with the IPs
+	Routes []*types.Route `json:"routes,omitempty"`
+	// DNS is returned along with the IPs
+	DNS types.DNS `json:"dns,omitempty"`
+
+	timeout time.Duration
+}
+
+// K8sArgs are the Pod identity arguments passed in CNI_ARGS by the container runtime.
+type K8sArgs struct {
+	types.CommonArgs
+	K8S_POD_NAMESPACE types.UnmarshallableString //nolint:revive,stylecheck
+	K8S_POD_NAME      types.UnmarshallableString //nolint:revive,stylecheck
+	K8S_POD_UID       types.UnmarshallableString //nolint:revive,stylecheck
+}
+
+// LoadConf parses and validates the network configuration.
+func LoadConf(data []byte) (*NetConf, error) {
+	conf := &NetConf{}
+	if err := json.Unmarshal(data, conf); err != nil {
+		return nil, errors.Wrap(err, "unable to parse network configuration")
+	}
+	if conf.IPAM == nil {
+		return nil, errors.New("ipam configuration is missing")
+	}
+	if len(conf.IPAM.Subnets) == 0 {
+		return nil, errors.New("at least one subnet should be configured")
+	}
+
+	conf.IPAM.timeout = defaultTimeout
+	if conf.IPAM.Timeout != "" {
+		timeout, err := time.ParseDuration(conf.IPAM.Timeout)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse timeout %s", conf.IPAM.Timeout)
+		}
+		conf.IPAM.timeout = timeout
+	}
+	return conf, nil
+}
+
+// LoadK8sArgs parses the Pod identity arguments, Pod namespace and name are required.
+func LoadK8sArgs(args string) (*K8sArgs, error) {
+	k8sArgs := &K8sArgs{}
+	if err := types.LoadArgs(args, k8sArgs); err != nil {
+		return nil, errors.Wrap(err, "unable to parse CNI_ARGS")
+	}
+	if k8sArgs.K8S_POD_NAMESPACE == "" || k8sArgs.K8S_POD_NAME == "" {
+		return nil, errors.New("K8S_POD_NAMESPACE and K8S_POD_NAME should be passed in CNI_ARGS")
+	}
+	return k8sArgs, nil
+}
@@ -0,0 +1,340 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cni implements a CNI IPAM plugin, allocating addresses of container attachments as IP objects.
+package cni
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/containernetworking/cni/pkg/skel"
+	cnitypes "github.com/containernetworking/cni/pkg/types"
+	current "github.com/containernetworking/cni/pkg/types/100"
+	"github.com/containernetworking/cni/pkg/version"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+const (
+	// ContainerIDAnnotation contains the ID of the container the IP is allocated for
+	ContainerIDAnnotation = "ipam.metal.ironcore.dev/cni-container-id"
+)
+
+// Plugin handles CNI commands with a client of the cluster IPs are allocated in.
+type Plugin struct {
+	// NewClient builds the client from the ipam configuration
+	NewClient func(conf *IPAMConfig) (client.Client, error)
+	// PollInterval is the interval IPs are polled at while waiting for the reservation
+	PollInterval time.Duration
+}
+
+// NewPlugin creates a plugin building clients from the configured kubeconfig.
+func NewPlugin() *Plugin {
+	return &Plugin{
+		NewClient:    newClient,
+		PollInterval: 200 * time.Millisecond,
+	}
+}
+
+// Funcs returns the CNI command handlers of the plugin.
+func (p *Plugin) Funcs() skel.CNIFuncs {
+	return skel.CNIFuncs{
+		Add:   p.Add,
+		Check: p.Check,
+		Del:   p.Del,
+	}
+}
+
+// Add creates an IP for each configured Subnet, waits for the reservation and prints the result.
+// Gateway, routes and DNS configuration of the Subnets complement the ones of the plugin configuration.
+func (p *Plugin) Add(args *skel.CmdArgs) error {
+	conf, k8sArgs, c, err := p.load(args)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), conf.IPAM.timeout)
+	defer cancel()
+
+	pod := &corev1.Pod{}
+	podNamespacedName := types.NamespacedName{Namespace: string(k8sArgs.K8S_POD_NAMESPACE), Name: string(k8sArgs.K8S_POD_NAME)}
+	if err := c.Get(ctx, podNamespacedName, pod); err != nil {
+		return errors.Wrapf(err, "unable to get pod %s", podNamespacedName)
+	}
+	if k8sArgs.K8S_POD_UID != "" && string(pod.UID) != string(k8sArgs.K8S_POD_UID) {
+		return errors.Errorf("pod %s has uid %s, while %s is expected", podNamespacedName, pod.UID, k8sArgs.K8S_POD_UID)
+	}
+
+	result := &current.Result{
+		CNIVersion: current.ImplementedSpecVersion,
+		Routes:     conf.IPAM.Routes,
+		DNS:        conf.IPAM.DNS,
+	}
+	var gateway netip.Addr
+	if conf.IPAM.Gateway != "" {
+		if gateway, err = netip.ParseAddr(conf.IPAM.Gateway); err != nil {
+			return errors.Wrapf(err, "unable to parse gateway %s", conf.IPAM.Gateway)
+		}
+	}
+	for _, subnet := range conf.IPAM.Subnets {
+		ip, err := p.reserve(ctx, c, newAttachmentIP(pod, args, subnet))
+		if err != nil {
+			return err
+		}
+		config, err := attachmentNetworkConfig(ctx, c, ip)
+		if err != nil {
+			return err
+		}
+		result.IPs = append(result.IPs, attachmentIPConfig(ip, config, gateway))
+		result.Routes = append(result.Routes, subnetRoutes(config)...)
+		if result.DNS.IsEmpty() {
+			for _, server := range config.DNSServers {
+				result.DNS.Nameservers = append(result.DNS.Nameservers, server.String())
+			}
+			result.DNS.Search = config.SearchDomains
+		}
+	}
+
+	return cnitypes.PrintResult(result, conf.CNIVersion)
+}
+
+// Check verifies IPs of the attachment are still reserved with the addresses of the previous result.
+func (p *Plugin) Check(args *skel.CmdArgs) error {
+	conf, k8sArgs, c, err := p.load(args)
+	if err != nil {
+		return err
+	}
+	if conf.RawPrevResult == nil {
+		return errors.New("previous result is missing")
+	}
+	if err := version.ParsePrevResult(&conf.NetConf); err != nil {
+		return errors.Wrap(err, "unable to parse previous result")
+	}
+	prevResult, err := current.NewResultFromResult(conf.PrevResult)
+	if err != nil {
+		return errors.Wrap(err, "unable to convert previous result")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), conf.IPAM.timeout)
+	defer cancel()
+
+	for _, subnet := range conf.IPAM.Subnets {
+		ip := &v1alpha1.IP{}
+		name := types.NamespacedName{Namespace: string(k8sArgs.K8S_POD_NAMESPACE), Name: attachmentIPName(args, subnet)}
+		if err := c.Get(ctx, name, ip); err != nil {
+			return errors.Wrapf(err, "unable to get ip %s", name)
+		}
+		if ip.Status.State != v1alpha1.FinishedIPState || ip.Status.Reserved == nil {
+			return errors.Errorf("ip %s is not reserved", name)
+		}
+		found := false
+		for _, ipConfig := range prevResult.IPs {
+			found = found || ipConfig.Address.IP.Equal(net.IP(ip.Status.Reserved.Net.AsSlice()))
+		}
+		if !found {
+			return errors.Errorf("address %s of ip %s is missing in the previous result", ip.Status.Reserved.String(), name)
+		}
+	}
+	return nil
+}
+
+// Del releases IPs of the attachment. IPs which don't exist anymore are ignored.
+func (p *Plugin) Del(args *skel.CmdArgs) error {
+	conf, k8sArgs, c, err := p.load(args)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), conf.IPAM.timeout)
+	defer cancel()
+
+	for _, subnet := range conf.IPAM.Subnets {
+		ip := &v1alpha1.IP{}
+		name := types.NamespacedName{Namespace: string(k8sArgs.K8S_POD_NAMESPACE), Name: attachmentIPName(args, subnet)}
+		err := c.Get(ctx, name, ip)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "unable to get ip %s", name)
+		}
+		// Consumer is removed first, since the webhook prevents deletion of IPs with existing consumers.
+		if ip.Spec.Consumer != nil {
+			ip.Spec.Consumer = nil
+			if err := c.Update(ctx, ip); err != nil {
+				return errors.Wrapf(err, "unable to remove consumer of ip %s", name)
+			}
+		}
+		if err := client.IgnoreNotFound(c.Delete(ctx, ip)); err != nil {
+			return errors.Wrapf(err, "unable to delete ip %s", name)
+		}
+	}
+	return nil
+}
+
+func (p *Plugin) load(args *skel.CmdArgs) (*NetConf, *K8sArgs, client.Client, error) {
+	conf, err := LoadConf(args.StdinData)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	k8sArgs, err := LoadK8sArgs(args.Args)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	c, err := p.NewClient(conf.IPAM)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "unable to create client")
+	}
+	return conf, k8sArgs, c, nil
+}
+
+// reserve creates the IP, or reuses it if it has been created by a repeated ADD, and waits for the reservation.
+// Failed IP is deleted, so the next attempt does not reuse it.
+func (p *Plugin) reserve(ctx context.Context, c client.Client, ip *v1alpha1.IP) (*v1alpha1.IP, error) {
+	name := client.ObjectKeyFromObject(ip)
+	if err := c.Create(ctx, ip); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, errors.Wrapf(err, "unable to create ip %s", name)
+	}
+
+	err := wait.PollUntilContextCancel(ctx, p.PollInterval, true, func(ctx context.Context) (bool, error) {
+		if err := c.Get(ctx, name, ip); err != nil {
+			return false, err
+		}
+		return ip.Status.State == v1alpha1.FinishedIPState || ip.Status.State == v1alpha1.FailedIPState, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to wait for reservation of ip %s", name)
+	}
+
+	if ip.Status.State == v1alpha1.FailedIPState {
+		ip.Spec.Consumer = nil
+		if err := c.Update(ctx, ip); err == nil {
+			_ = c.Delete(ctx, ip)
+		}
+		return nil, errors.Errorf("reservation of ip %s failed: %s", name, ip.Status.Message)
+	}
+	return ip, nil
+}
+
+// newAttachmentIP builds an IP consumed and owned by the Pod, so it is garbage collected
+// with the Pod even if DEL is never called.
+func newAttachmentIP(pod *corev1.Pod, args *skel.CmdArgs, subnet string) *v1alpha1.IP {
+	return &v1alpha1.IP{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      attachmentIPName(args, subnet),
+			Namespace: pod.Namespace,
+			Annotations: map[string]string{
+				ContainerIDAnnotation:        args.ContainerID,
+				v1alpha1.InterfaceAnnotation: args.IfName,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(pod, corev1.SchemeGroupVersion.WithKind("Pod")),
+			},
+		},
+		Spec: v1alpha1.IPSpec{
+			Subnet: v1alpha1.SubnetReferenceFromAnnotation(subnet),
+			Consumer: &v1alpha1.ResourceReference{
+				APIVersion: "v1",
+				Kind:       "Pod",
+				Name:       pod.Name,
+			},
+		},
+	}
+}
+
+// attachmentIPName is derived from the attachment and the Subnet, so repeated commands find the same IP.
+func attachmentIPName(args *skel.CmdArgs, subnet string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%s", args.ContainerID, args.IfName, subnet)))
+	return "cni-" + hex.EncodeToString(sum[:])[:20]
+}
+
+// attachmentNetworkConfig returns network configuration of the IP resolved from its Subnet.
+// IPs reserved by managers not resolving the configuration get the prefix length of the Subnet only.
+func attachmentNetworkConfig(ctx context.Context, c client.Client, ip *v1alpha1.IP) (*v1alpha1.NetworkConfig, error) {
+	if ip.Status.NetworkConfig != nil {
+		return ip.Status.NetworkConfig, nil
+	}
+
+	subnet := &v1alpha1.Subnet{}
+	subnetNamespacedName := ip.Spec.Subnet.NamespacedName(ip.Namespace)
+	if err := c.Get(ctx, subnetNamespacedName, subnet); err != nil {
+		return nil, errors.Wrapf(err, "unable to get subnet %s", subnetNamespacedName)
+	}
+	if subnet.Status.Reserved == nil {
+		return nil, errors.Errorf("subnet %s has no reserved cidr", subnetNamespacedName)
+	}
+	return &v1alpha1.NetworkConfig{PrefixLength: subnet.Status.Reserved.MaskOnes()}, nil
+}
+
+// attachmentIPConfig returns the reserved address with the on-link prefix length and the gateway of the Subnet.
+// Configured gateway is used only if the Subnet has none and the on-link prefix contains it.
+func attachmentIPConfig(ip *v1alpha1.IP, config *v1alpha1.NetworkConfig, gateway netip.Addr) *current.IPConfig {
+	address := ip.Status.Reserved.Net
+	ipConfig := &current.IPConfig{
+		Address: net.IPNet{
+			IP:   net.IP(address.AsSlice()),
+			Mask: net.CIDRMask(int(config.PrefixLength), address.BitLen()),
+		},
+	}
+	onLink := netip.PrefixFrom(address, int(config.PrefixLength)).Masked()
+	switch {
+	case config.Gateway != nil:
+		ipConfig.Gateway = net.IP(config.Gateway.Net.AsSlice())
+	case gateway.IsValid() && onLink.Contains(gateway):
+		ipConfig.Gateway = net.IP(gateway.AsSlice())
+	}
+	return ipConfig
+}
+
+// subnetRoutes converts static routes of the Subnet, routes without a next hop are routed via the gateway.
+func subnetRoutes(config *v1alpha1.NetworkConfig) []*cnitypes.Route {
+	routes := make([]*cnitypes.Route, 0, len(config.Routes))
+	for _, route := range config.Routes {
+		prefix := route.Destination.Net
+		r := &cnitypes.Route{
+			Dst: net.IPNet{IP: net.IP(prefix.Addr().AsSlice()), Mask: net.CIDRMask(prefix.Bits(), prefix.Addr().BitLen())},
+		}
+		switch {
+		case route.Gateway != nil:
+			r.GW = net.IP(route.Gateway.Net.AsSlice())
+		case config.Gateway != nil:
+			r.GW = net.IP(config.Gateway.Net.AsSlice())
+		}
+		if route.Metric != nil {
+			r.Priority = int(*route.Metric)
+		}
+		routes = append(routes, r)
+	}
+	return routes
+}
+
+// newClient builds a client from the kubeconfig, or from in-cluster config if the kubeconfig is not set.
+func newClient(conf *IPAMConfig) (client.Client, error) {
+	cfg, err := clientcmd.BuildConfigFromFlags("", conf.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	return client.New(cfg, client.Options{Scheme: scheme})
+}
@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cni
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/containernetworking/cni/pkg/skel"
+	current "github.com/containernetworking/cni/pkg/types/100"
+	"github.com/containernetworking/plugins/pkg/testutils"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CNI plugin", func() {
+	ns := SetupTest()
+
+	var (
+		plugin *Plugin
+		pod    *corev1.Pod
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		plugin = &Plugin{
+			NewClient: func(*IPAMConfig) (client.Client, error) {
+				return k8sClient, nil
+			},
+			PollInterval: pollingInterval,
+		}
+
+		network := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: ns.Name},
+		}
+		Expect(k8sClient.Create(ctx, network)).To(Succeed())
+		Eventually(Object(network)).Should(HaveField("Status.State", v1alpha1.CFinishedNetworkState))
+
+		for name, cidr := range map[string]string{"v4": "10.0.0.0/24", "v6": "fd00::/64"} {
+			subnet := &v1alpha1.Subnet{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns.Name},
+				Spec: v1alpha1.SubnetSpec{
+					CIDR:    v1alpha1.CidrMustParse(cidr),
+					Network: corev1.LocalObjectReference{Name: network.Name},
+				},
+			}
+			Expect(k8sClient.Create(ctx, subnet)).To(Succeed())
+			Eventually(Object(subnet)).Should(HaveField("Status.State", v1alpha1.FinishedSubnetState))
+		}
+
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: ns.Name},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "main", Image: "busybox"}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+	})
+
+	cmdArgs := func(subnets string) *skel.CmdArgs {
+		return &skel.CmdArgs{
+			ContainerID: "container",
+			Netns:       "/var/run/netns/container",
+			IfName:      "net1",
+			Args:        fmt.Sprintf("K8S_POD_NAMESPACE=%s;K8S_POD_NAME=%s;K8S_POD_UID=%s", ns.Name, pod.Name, pod.UID),
+			StdinData: []byte(fmt.Sprintf(`{
+				"cniVersion": "1.0.0",
+				"name": "secondary",
+				"type": "macvlan",
+				"ipam": {
+					"type": "ipam-cni",
+					"subnets": %s,
+					"timeout": "3s",
+					"gateway": "10.0.0.1",
+					"routes": [{"dst": "0.0.0.0/0"}]
+				}
+			}`, subnets)),
+		}
+	}
+
+	It("Should allocate, check and release IPs of the attachment", func(ctx SpecContext) {
+		args := cmdArgs(`["v4", "v6"]`)
+
+		By("Adding the attachment")
+		r, _, err := testutils.CmdAddWithArgs(args, func() error {
+			return plugin.Add(args)
+		})
+		Expect(err).NotTo(HaveOccurred())
+		result, err := current.GetResult(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IPs).To(HaveLen(2))
+		Expect(result.IPs[0].Address.String()).To(Equal("10.0.0.0/24"))
+		Expect(result.IPs[0].Gateway).To(Equal(net.ParseIP("10.0.0.1").To4()))
+		Expect(result.IPs[1].Address.String()).To(Equal("fd00::/64"))
+		Expect(result.IPs[1].Gateway).To(BeNil())
+		Expect(result.Routes).To(HaveLen(1))
+
+		ipList := &v1alpha1.IPList{}
+		Expect(k8sClient.List(ctx, ipList, client.InNamespace(ns.Name))).To(Succeed())
+		Expect(ipList.Items).To(HaveLen(2))
+		Expect(ipList.Items).To(HaveEach(SatisfyAll(
+			HaveField("Spec.Consumer", Equal(&v1alpha1.ResourceReference{APIVersion: "v1", Kind: "Pod", Name: pod.Name})),
+			HaveField("OwnerReferences", ContainElement(HaveField("UID", pod.UID))),
+			HaveField("Annotations", HaveKeyWithValue(ContainerIDAnnotation, "container")),
+			HaveField("Annotations", HaveKeyWithValue(v1alpha1.InterfaceAnnotation, args.IfName)),
+		)))
+
+		By("Repeating ADD of the same attachment")
+		_, _, err = testutils.CmdAddWithArgs(args, func() error {
+			return plugin.Add(args)
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.List(ctx, ipList, client.InNamespace(ns.Name))).To(Succeed())
+		Expect(ipList.Items).To(HaveLen(2))
+
+		By("Checking the attachment")
+		checkArgs := *args
+		checkArgs.StdinData = []byte(`{
+			"cniVersion": "1.0.0",
+			"name": "secondary",
+			"type": "macvlan",
+			"ipam": {"type": "ipam-cni", "subnets": ["v4", "v6"]},
+			"prevResult": {"cniVersion": "1.0.0", "ips": [{"address": "10.0.0.0/24"}, {"address": "fd00::/64"}]}
+		}`)
+		Expect(testutils.CmdCheckWithArgs(&checkArgs, func() error {
+			return plugin.Check(&checkArgs)
+		})).To(Succeed())
+
+		By("Deleting the attachment")
+		Expect(testutils.CmdDelWithArgs(args, func() error {
+			return plugin.Del(args)
+		})).To(Succeed())
+		for i := range ipList.Items {
+			Eventually(Get(&ipList.Items[i])).Should(Satisfy(apierrors.IsNotFound))
+		}
+
+		By("Repeating DEL of the deleted attachment")
+		Expect(testutils.CmdDelWithArgs(args, func() error {
+			return plugin.Del(args)
+		})).To(Succeed())
+	})
+
+	It("Should return network configuration of the subnet", func(ctx SpecContext) {
+		subnet := &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: "configured", Namespace: ns.Name},
+			Spec: v1alpha1.SubnetSpec{
+				CIDR:          v1alpha1.CidrMustParse("10.1.0.0/24"),
+				Network:       corev1.LocalObjectReference{Name: "network"},
+				Gateway:       v1alpha1.IPMustParse("10.1.0.1"),
+				DNSServers:    []v1alpha1.IPAddr{*v1alpha1.IPMustParse("10.1.0.53")},
+				SearchDomains: []string{"example.com"},
+				Routes:        []v1alpha1.SubnetRoute{{Destination: v1alpha1.CidrMustParse("10.2.0.0/16")}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, subnet)).To(Succeed())
+		Eventually(Object(subnet)).Should(HaveField("Status.State", v1alpha1.FinishedSubnetState))
+
+		args := cmdArgs(`["configured"]`)
+		r, _, err := testutils.CmdAddWithArgs(args, func() error {
+			return plugin.Add(args)
+		})
+		Expect(err).NotTo(HaveOccurred())
+		result, err := current.GetResult(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IPs).To(HaveLen(1))
+		Expect(result.IPs[0].Address.String()).To(Equal("10.1.0.0/24"))
+		Expect(result.IPs[0].Gateway).To(Equal(net.ParseIP("10.1.0.1").To4()))
+		Expect(result.Routes).To(HaveLen(2))
+		Expect(result.Routes[1].Dst.String()).To(Equal("10.2.0.0/16"))
+		Expect(result.Routes[1].GW).To(Equal(net.ParseIP("10.1.0.1").To4()))
+		Expect(result.DNS.Nameservers).To(ConsistOf("10.1.0.53"))
+		Expect(result.DNS.Search).To(ConsistOf("example.com"))
+	})
+
+	It("Should fail ADD if the IP can not be reserved", func() {
+		args := cmdArgs(`["missing"]`)
+		args.StdinData = []byte(`{
+			"cniVersion": "1.0.0",
+			"name": "secondary",
+			"type": "macvlan",
+			"ipam": {"type": "ipam-cni", "subnets": ["missing"], "timeout": "1s"}
+		}`)
+
+		start := time.Now()
+		_, _, err := testutils.CmdAddWithArgs(args, func() error {
+			return plugin.Add(args)
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", 3*time.Second))
+	})
+
+	It("Should reject configuration without subnets", func() {
+		_, err := LoadConf([]byte(`{"cniVersion": "1.0.0", "name": "secondary", "ipam": {"type": "ipam-cni"}}`))
+		Expect(err).To(HaveOccurred())
+
+		_, err = LoadK8sArgs("IgnoreUnknown=1")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,32 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+// accessNotPermittedError re-checks allocation from the parent subnet against its access policy.
+// Policy is checked by webhooks on creation, though the parent may not exist on admission, or the policy may be
+// changed before the address is reserved. Users are known on admission only, so allocations created before the
+// parent are rejected if the policy restricts users, since they have not been checked.
+func accessNotPermittedError(parent *v1alpha1.Subnet, consumer *v1alpha1.ResourceReference, explicit bool, created metav1.Time) error {
+	policy := parent.Spec.AccessPolicy
+	if policy == nil {
+		return nil
+	}
+	if !policy.AllowsConsumer(consumer) {
+		return errors.Errorf("access policy of subnet %s does not allow allocations for this consumer", parent.Name)
+	}
+	if explicit && !policy.AllowsExplicitAddresses() {
+		return errors.Errorf("access policy of subnet %s does not allow requests of particular addresses", parent.Name)
+	}
+	if (len(policy.Users) > 0 || len(policy.Groups) > 0) && created.Before(&parent.CreationTimestamp) {
+		return errors.Errorf("access policy of subnet %s restricts users, though the allocation has been created before the subnet", parent.Name)
+	}
+	return nil
+}
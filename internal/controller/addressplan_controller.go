@@ -0,0 +1,590 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+const (
+	CAddressPlanFailureReason = "AddressPlanFailure"
+	CAddressPlanAppliedReason = "AddressPlanApplied"
+)
+
+// AddressPlanReconciler creates, owns and keeps in sync the Network and Subnets rendered by AddressPlans.
+// Changes needed to sync the objects are listed in the plan status, and applied once the revision of the plan
+// is approved. Subnets are deleted or recreated only if neither they nor their descendants have IPs or child
+// Subnets not managed by the plan, otherwise the change is blocked.
+type AddressPlanReconciler struct {
+	client.Client
+	Log           logr.Logger
+	Scheme        *runtime.Scheme
+	EventRecorder events.EventRecorder
+}
+
+// addressPlanObjects are Networks and Subnets controlled by the plan, mapped by name.
+type addressPlanObjects struct {
+	networks map[string]*v1alpha1.Network
+	subnets  map[string]*v1alpha1.Subnet
+}
+
+// +kubebuilder:rbac:groups=ipam.metal.ironcore.dev,resources=addressplans,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ipam.metal.ironcore.dev,resources=addressplans/status,verbs=get;update;patch
+
+// Reconcile plans changes of objects of the AddressPlan, and applies them if the revision is approved.
+func (r *AddressPlanReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("addressplan", req.NamespacedName)
+
+	plan := &v1alpha1.AddressPlan{}
+	err := r.Get(ctx, req.NamespacedName, plan)
+	if apierrors.IsNotFound(err) {
+		log.Info("AddressPlan not found, it might have been deleted.")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if err != nil {
+		log.Error(err, "unable to get address plan resource", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	if plan.GetDeletionTimestamp() != nil || v1alpha1.IsReconcilePaused(plan.Annotations) {
+		return ctrl.Result{}, nil
+	}
+
+	status := plan.Status.DeepCopy()
+	status.ObservedGeneration = plan.Generation
+
+	network, subnets, err := plan.Render()
+	if err != nil {
+		log.Error(err, "unable to render address plan", "name", req.NamespacedName)
+		r.EventRecorder.Eventf(plan, nil, v1.EventTypeWarning, CAddressPlanFailureReason, "AddressPlanRender", err.Error())
+		status.State = v1alpha1.FailedAddressPlanState
+		status.Message = err.Error()
+		status.Revision = ""
+		status.Changes = nil
+		return ctrl.Result{}, r.updateStatus(ctx, plan, status)
+	}
+
+	objects := []client.Object{network}
+	for i := range subnets {
+		objects = append(objects, &subnets[i])
+	}
+	revisionHashes := make([]string, 0, len(objects))
+	for _, object := range objects {
+		if err := controllerutil.SetControllerReference(plan, object, r.Scheme); err != nil {
+			log.Error(err, "unable to set owner reference", "name", req.NamespacedName)
+			return ctrl.Result{}, err
+		}
+		hash := addressPlanObjectHash(object)
+		object.SetAnnotations(map[string]string{v1alpha1.AddressPlanHashAnnotation: hash})
+		revisionHashes = append(revisionHashes, object.GetName()+"="+hash)
+	}
+	revision := addressPlanHash(revisionHashes)
+
+	current, err := r.currentObjects(ctx, plan)
+	if err != nil {
+		log.Error(err, "unable to list address plan objects", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+	changes, err := r.planChanges(ctx, plan, network, subnets, current)
+	if err != nil {
+		log.Error(err, "unable to plan changes", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+	status.Revision = revision
+	status.Changes = changes
+
+	if len(changes) > 0 && !plan.Approves(revision) {
+		status.State = v1alpha1.PlannedAddressPlanState
+		status.Message = fmt.Sprintf("%d changes are awaiting approval of revision %s", len(changes), revision)
+		return ctrl.Result{}, r.updateStatus(ctx, plan, status)
+	}
+
+	message, err := r.apply(ctx, network, subnets, current, changes)
+	if err != nil {
+		log.Error(err, "unable to apply address plan", "name", req.NamespacedName)
+		r.EventRecorder.Eventf(plan, nil, v1.EventTypeWarning, CAddressPlanFailureReason, "AddressPlanApply", err.Error())
+		status.State = v1alpha1.FailedAddressPlanState
+		status.Message = err.Error()
+		if err := r.updateStatus(ctx, plan, status); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, err
+	}
+
+	blocked := 0
+	for _, change := range changes {
+		if change.Blocked {
+			blocked++
+		}
+	}
+	switch {
+	case message != "":
+		status.State = v1alpha1.ApplyingAddressPlanState
+		status.Message = message
+	case blocked > 0:
+		status.State = v1alpha1.BlockedAddressPlanState
+		status.Message = fmt.Sprintf("%d changes are blocked by subnets in use", blocked)
+	default:
+		if status.AppliedRevision != revision {
+			r.EventRecorder.Eventf(plan, nil, v1.EventTypeNormal, CAddressPlanAppliedReason, "AddressPlanApply",
+				"Revision %s applied", revision)
+		}
+		status.State = v1alpha1.SyncedAddressPlanState
+		status.Message = ""
+		status.AppliedRevision = revision
+	}
+
+	return ctrl.Result{}, r.updateStatus(ctx, plan, status)
+}
+
+// currentObjects lists Networks and Subnets of the plan namespace controlled by the plan.
+func (r *AddressPlanReconciler) currentObjects(ctx context.Context, plan *v1alpha1.AddressPlan) (*addressPlanObjects, error) {
+	matchingPlan := []client.ListOption{
+		client.InNamespace(plan.Namespace),
+		client.MatchingLabels{v1alpha1.AddressPlanLabel: plan.Name},
+	}
+
+	networks := &v1alpha1.NetworkList{}
+	if err := r.List(ctx, networks, matchingPlan...); err != nil {
+		return nil, errors.Wrap(err, "unable to list networks")
+	}
+	subnets := &v1alpha1.SubnetList{}
+	if err := r.List(ctx, subnets, matchingPlan...); err != nil {
+		return nil, errors.Wrap(err, "unable to list subnets")
+	}
+
+	current := &addressPlanObjects{
+		networks: make(map[string]*v1alpha1.Network, len(networks.Items)),
+		subnets:  make(map[string]*v1alpha1.Subnet, len(subnets.Items)),
+	}
+	for i := range networks.Items {
+		if metav1.IsControlledBy(&networks.Items[i], plan) {
+			current.networks[networks.Items[i].Name] = &networks.Items[i]
+		}
+	}
+	for i := range subnets.Items {
+		if metav1.IsControlledBy(&subnets.Items[i], plan) {
+			current.subnets[subnets.Items[i].Name] = &subnets.Items[i]
+		}
+	}
+	return current, nil
+}
+
+// parent returns the name of the parent of the subnet, if it is controlled by the plan.
+func (in *addressPlanObjects) parent(subnet *v1alpha1.Subnet) string {
+	if subnet.Spec.ParentSubnet.Namespace != "" && subnet.Spec.ParentSubnet.Namespace != subnet.Namespace {
+		return ""
+	}
+	if _, ok := in.subnets[subnet.Spec.ParentSubnet.Name]; !ok {
+		return ""
+	}
+	return subnet.Spec.ParentSubnet.Name
+}
+
+// planChanges lists changes needed to bring current objects in sync with the rendered ones.
+// Subnets with fields, which may not be changed, are recreated along with their descendants,
+// and so is the Network along with all the Subnets.
+func (r *AddressPlanReconciler) planChanges(ctx context.Context, plan *v1alpha1.AddressPlan,
+	network *v1alpha1.Network, subnets []v1alpha1.Subnet, current *addressPlanObjects) ([]v1alpha1.AddressPlanChange, error) {
+	var changes []v1alpha1.AddressPlanChange
+
+	// Network with fields, which may not be changed, is recreated along with all the Subnets of the plan
+	currentNetwork, networkExists := current.networks[network.Name]
+	recreateNetwork := networkExists && !sameNetworkRequest(currentNetwork, network)
+
+	desired := make(map[string]*v1alpha1.Subnet, len(subnets))
+	for i := range subnets {
+		desired[subnets[i].Name] = &subnets[i]
+	}
+	// Subnets to be deleted or recreated
+	gone := make(map[string]bool)
+	for name, subnet := range current.subnets {
+		if desiredSubnet, ok := desired[name]; !ok || !sameSubnetRequest(subnet, desiredSubnet) ||
+			(recreateNetwork && subnet.Spec.Network.Name == network.Name) {
+			gone[name] = true
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for name, subnet := range current.subnets {
+			if parent := current.parent(subnet); !gone[name] && parent != "" && gone[parent] {
+				gone[name] = true
+				changed = true
+			}
+		}
+	}
+	blocked, err := r.blockedSubnets(ctx, current, gone)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case !networkExists:
+		changes = append(changes, v1alpha1.AddressPlanChange{
+			Action: v1alpha1.CreateAddressPlanChangeAction,
+			Kind:   "Network",
+			Name:   network.Name,
+		})
+	case recreateNetwork:
+		inUse, err := r.networkInUse(ctx, plan.Namespace, network.Name, current)
+		if err != nil {
+			return nil, err
+		}
+		for name := range gone {
+			inUse = inUse || blocked[name]
+		}
+		changes = append(changes, v1alpha1.AddressPlanChange{
+			Action:  v1alpha1.RecreateAddressPlanChangeAction,
+			Kind:    "Network",
+			Name:    network.Name,
+			Details: describeNetworkRequest(currentNetwork) + " -> " + describeNetworkRequest(network),
+			Blocked: inUse,
+		})
+	case currentNetwork.Annotations[v1alpha1.AddressPlanHashAnnotation] != network.Annotations[v1alpha1.AddressPlanHashAnnotation]:
+		changes = append(changes, v1alpha1.AddressPlanChange{
+			Action:  v1alpha1.UpdateAddressPlanChangeAction,
+			Kind:    "Network",
+			Name:    network.Name,
+			Details: "labels or spec changed",
+		})
+	}
+
+	for i := range subnets {
+		subnet := &subnets[i]
+		currentSubnet, ok := current.subnets[subnet.Name]
+		switch {
+		case !ok:
+			changes = append(changes, v1alpha1.AddressPlanChange{
+				Action:  v1alpha1.CreateAddressPlanChangeAction,
+				Kind:    "Subnet",
+				Name:    subnet.Name,
+				Details: describeSubnetRequest(subnet),
+			})
+		case gone[subnet.Name]:
+			changes = append(changes, v1alpha1.AddressPlanChange{
+				Action:  v1alpha1.RecreateAddressPlanChangeAction,
+				Kind:    "Subnet",
+				Name:    subnet.Name,
+				Details: describeSubnetRequest(currentSubnet) + " -> " + describeSubnetRequest(subnet),
+				Blocked: blocked[subnet.Name],
+			})
+		case currentSubnet.Annotations[v1alpha1.AddressPlanHashAnnotation] != subnet.Annotations[v1alpha1.AddressPlanHashAnnotation]:
+			changes = append(changes, v1alpha1.AddressPlanChange{
+				Action:  v1alpha1.UpdateAddressPlanChangeAction,
+				Kind:    "Subnet",
+				Name:    subnet.Name,
+				Details: "labels changed",
+			})
+		}
+	}
+
+	var deleted []string
+	for name := range current.subnets {
+		if _, ok := desired[name]; !ok {
+			deleted = append(deleted, name)
+		}
+	}
+	slices.Sort(deleted)
+	for _, name := range deleted {
+		changes = append(changes, v1alpha1.AddressPlanChange{
+			Action:  v1alpha1.DeleteAddressPlanChangeAction,
+			Kind:    "Subnet",
+			Name:    name,
+			Details: describeSubnetRequest(current.subnets[name]),
+			Blocked: blocked[name],
+		})
+	}
+
+	deleted = nil
+	for name := range current.networks {
+		if name != network.Name {
+			deleted = append(deleted, name)
+		}
+	}
+	slices.Sort(deleted)
+	for _, name := range deleted {
+		inUse, err := r.networkInUse(ctx, plan.Namespace, name, current)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, v1alpha1.AddressPlanChange{
+			Action:  v1alpha1.DeleteAddressPlanChangeAction,
+			Kind:    "Network",
+			Name:    name,
+			Blocked: inUse,
+		})
+	}
+
+	return changes, nil
+}
+
+// blockedSubnets returns subnets going to be deleted or recreated, which may not be deleted,
+// since they or their descendants are in use.
+func (r *AddressPlanReconciler) blockedSubnets(ctx context.Context, current *addressPlanObjects, gone map[string]bool) (map[string]bool, error) {
+	blocked := make(map[string]bool)
+	for name := range gone {
+		subnet := current.subnets[name]
+		inUse, err := r.subnetInUse(ctx, subnet, current)
+		if err != nil {
+			return nil, err
+		}
+		if !inUse {
+			continue
+		}
+		for ; subnet != nil && !blocked[subnet.Name]; subnet = current.subnets[current.parent(subnet)] {
+			blocked[subnet.Name] = true
+		}
+	}
+	return blocked, nil
+}
+
+// subnetInUse checks whether the subnet has finished IPs or finished child Subnets not controlled by the plan.
+func (r *AddressPlanReconciler) subnetInUse(ctx context.Context, subnet *v1alpha1.Subnet, current *addressPlanObjects) (bool, error) {
+	key := client.ObjectKeyFromObject(subnet).String()
+
+	ips := &v1alpha1.IPList{}
+	if err := r.List(ctx, ips, client.MatchingFields{CSubnetIPIndexKey: key}); err != nil {
+		return false, errors.Wrap(err, "unable to list subnet ips")
+	}
+	if len(ips.Items) > 0 {
+		return true, nil
+	}
+
+	children := &v1alpha1.SubnetList{}
+	if err := r.List(ctx, children, client.MatchingFields{CFinishedChildSubnetIndexKey: key}); err != nil {
+		return false, errors.Wrap(err, "unable to list child subnets")
+	}
+	for i := range children.Items {
+		if _, ok := current.subnets[children.Items[i].Name]; !ok || children.Items[i].Namespace != subnet.Namespace {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// networkInUse checks whether Subnets not controlled by the plan belong to the network.
+func (r *AddressPlanReconciler) networkInUse(ctx context.Context, namespace, name string, current *addressPlanObjects) (bool, error) {
+	subnets := &v1alpha1.SubnetList{}
+	if err := r.List(ctx, subnets, client.InNamespace(namespace)); err != nil {
+		return false, errors.Wrap(err, "unable to list subnets")
+	}
+	for i := range subnets.Items {
+		if _, ok := current.subnets[subnets.Items[i].Name]; !ok && subnets.Items[i].Spec.Network.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// apply takes the next steps of syncing objects with the plan, and returns a message describing the step
+// it waits for, or an empty message once all the changes, which are not blocked, are applied.
+// Deletions come first, children before their parents, so the space they occupy is vacant for new Subnets.
+// Subnets are created one by one in the plan order, each once the previous one is reserved,
+// so their CIDRs are allocated deterministically.
+func (r *AddressPlanReconciler) apply(ctx context.Context, network *v1alpha1.Network, subnets []v1alpha1.Subnet,
+	current *addressPlanObjects, changes []v1alpha1.AddressPlanChange) (string, error) {
+	removed := make(map[string]bool)
+	deleting := false
+	for _, change := range changes {
+		if change.Action != v1alpha1.DeleteAddressPlanChangeAction && change.Action != v1alpha1.RecreateAddressPlanChangeAction {
+			continue
+		}
+		removed[change.Name] = true
+		if change.Blocked {
+			continue
+		}
+
+		deleting = true
+		var object client.Object
+		switch change.Kind {
+		case "Subnet":
+			subnet := current.subnets[change.Name]
+			if subnet.GetDeletionTimestamp() != nil || current.hasChildren(subnet) {
+				continue
+			}
+			object = subnet
+		case "Network":
+			networkSubnets := false
+			for _, subnet := range current.subnets {
+				networkSubnets = networkSubnets || subnet.Spec.Network.Name == change.Name
+			}
+			if current.networks[change.Name].GetDeletionTimestamp() != nil || networkSubnets {
+				continue
+			}
+			object = current.networks[change.Name]
+		}
+		if err := r.Delete(ctx, object); client.IgnoreNotFound(err) != nil {
+			return "", errors.Wrapf(err, "unable to delete %s %s", change.Kind, change.Name)
+		}
+	}
+	if deleting {
+		return "waiting for deletion of objects removed from the plan", nil
+	}
+
+	currentNetwork, ok := current.networks[network.Name]
+	if !ok {
+		if err := r.Create(ctx, network); err != nil {
+			return "", errors.Wrapf(err, "unable to create network %s", network.Name)
+		}
+		return fmt.Sprintf("waiting for network %s", network.Name), nil
+	}
+	if !removed[network.Name] &&
+		currentNetwork.Annotations[v1alpha1.AddressPlanHashAnnotation] != network.Annotations[v1alpha1.AddressPlanHashAnnotation] {
+		currentNetwork.Labels = network.Labels
+		currentNetwork.Spec = network.Spec
+		metav1.SetMetaDataAnnotation(&currentNetwork.ObjectMeta, v1alpha1.AddressPlanHashAnnotation, network.Annotations[v1alpha1.AddressPlanHashAnnotation])
+		if err := r.Update(ctx, currentNetwork); err != nil {
+			return "", errors.Wrapf(err, "unable to update network %s", network.Name)
+		}
+	}
+	switch currentNetwork.Status.State {
+	case v1alpha1.CFinishedNetworkState:
+	case v1alpha1.CFailedNetworkState:
+		return "", errors.Errorf("network %s failed: %s", network.Name, currentNetwork.Status.Message)
+	default:
+		return fmt.Sprintf("waiting for network %s", network.Name), nil
+	}
+
+	for i := range subnets {
+		subnet := &subnets[i]
+		currentSubnet, ok := current.subnets[subnet.Name]
+		if !ok {
+			if err := r.Create(ctx, subnet); err != nil {
+				return "", errors.Wrapf(err, "unable to create subnet %s", subnet.Name)
+			}
+			return fmt.Sprintf("waiting for subnet %s to be reserved", subnet.Name), nil
+		}
+		if !removed[subnet.Name] &&
+			currentSubnet.Annotations[v1alpha1.AddressPlanHashAnnotation] != subnet.Annotations[v1alpha1.AddressPlanHashAnnotation] {
+			currentSubnet.Labels = subnet.Labels
+			metav1.SetMetaDataAnnotation(&currentSubnet.ObjectMeta, v1alpha1.AddressPlanHashAnnotation, subnet.Annotations[v1alpha1.AddressPlanHashAnnotation])
+			if err := r.Update(ctx, currentSubnet); err != nil {
+				return "", errors.Wrapf(err, "unable to update subnet %s", subnet.Name)
+			}
+		}
+		switch currentSubnet.Status.State {
+		case v1alpha1.FinishedSubnetState:
+		case v1alpha1.FailedSubnetState:
+			return "", errors.Errorf("subnet %s failed: %s", subnet.Name, currentSubnet.Status.Message)
+		default:
+			return fmt.Sprintf("waiting for subnet %s to be reserved", subnet.Name), nil
+		}
+	}
+
+	return "", nil
+}
+
+// hasChildren checks whether Subnets controlled by the plan refer the subnet as their parent.
+func (in *addressPlanObjects) hasChildren(subnet *v1alpha1.Subnet) bool {
+	for _, child := range in.subnets {
+		if in.parent(child) == subnet.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *AddressPlanReconciler) updateStatus(ctx context.Context, plan *v1alpha1.AddressPlan, status *v1alpha1.AddressPlanStatus) error {
+	if equality.Semantic.DeepEqual(plan.Status, *status) {
+		return nil
+	}
+	plan.Status = *status
+	if err := r.Status().Update(ctx, plan); err != nil {
+		r.Log.Error(err, "unable to update address plan status", "name", client.ObjectKeyFromObject(plan))
+		return err
+	}
+	return nil
+}
+
+// sameSubnetRequest checks whether fields of the subnets, which may not be changed, are equal.
+func sameSubnetRequest(current, desired *v1alpha1.Subnet) bool {
+	sameRegions := len(current.Spec.Regions) == 0 && len(desired.Spec.Regions) == 0 ||
+		equality.Semantic.DeepEqual(current.Spec.Regions, desired.Spec.Regions)
+	return sameRegions &&
+		equality.Semantic.DeepEqual(current.Spec.CIDR, desired.Spec.CIDR) &&
+		equality.Semantic.DeepEqual(current.Spec.PrefixBits, desired.Spec.PrefixBits) &&
+		equality.Semantic.DeepEqual(current.Spec.Capacity, desired.Spec.Capacity) &&
+		current.Spec.ParentSubnet == desired.Spec.ParentSubnet &&
+		current.Spec.Network == desired.Spec.Network
+}
+
+// sameNetworkRequest checks whether fields of the networks, which may not be changed once assigned, are equal.
+func sameNetworkRequest(current, desired *v1alpha1.Network) bool {
+	if current.Spec.Type != "" && current.Spec.Type != desired.Spec.Type {
+		return false
+	}
+	if current.Spec.ID != nil {
+		return desired.Spec.ID != nil && current.Spec.ID.Cmp(&desired.Spec.ID.Int) == 0
+	}
+	return current.Spec.Type == "" || desired.Spec.ID == nil
+}
+
+func describeNetworkRequest(network *v1alpha1.Network) string {
+	if network.Spec.ID == nil {
+		return fmt.Sprintf("type %q", network.Spec.Type)
+	}
+	return fmt.Sprintf("type %q id %s", network.Spec.Type, network.Spec.ID.String())
+}
+
+func describeSubnetRequest(subnet *v1alpha1.Subnet) string {
+	switch {
+	case subnet.Spec.CIDR != nil:
+		return subnet.Spec.CIDR.String()
+	case subnet.Spec.PrefixBits != nil:
+		return fmt.Sprintf("/%d in %s", *subnet.Spec.PrefixBits, subnet.Spec.ParentSubnet.Name)
+	case subnet.Spec.Capacity != nil:
+		return fmt.Sprintf("capacity %s in %s", subnet.Spec.Capacity.String(), subnet.Spec.ParentSubnet.Name)
+	}
+	return ""
+}
+
+// addressPlanObjectHash hashes labels and spec of a rendered Network or Subnet.
+func addressPlanObjectHash(object client.Object) string {
+	switch typed := object.(type) {
+	case *v1alpha1.Network:
+		return addressPlanHash(typed.Labels, typed.Spec)
+	case *v1alpha1.Subnet:
+		return addressPlanHash(typed.Labels, typed.Spec)
+	}
+	return ""
+}
+
+func addressPlanHash(values ...any) string {
+	hash := sha256.New()
+	for _, value := range values {
+		// maps are marshalled with sorted keys, so the hash is stable
+		data, _ := json.Marshal(value)
+		hash.Write(data)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:10]
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AddressPlanReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.EventRecorder = newMetricsEventRecorder(mgr.GetEventRecorder("address-plan-controller"))
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.AddressPlan{}).
+		Owns(&v1alpha1.Network{}).
+		Owns(&v1alpha1.Subnet{}).
+		Complete(r)
+}
@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Address plan controller", func() {
+	ns := SetupTest()
+
+	subnet := func(name string) *v1alpha1.Subnet {
+		return &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns.Name},
+		}
+	}
+
+	It("Should plan, apply and keep in sync the network and subnets of the plan", func(ctx SpecContext) {
+		plan := &v1alpha1.AddressPlan{
+			ObjectMeta: metav1.ObjectMeta{Name: "eu", Namespace: ns.Name},
+			Spec: v1alpha1.AddressPlanSpec{
+				Subnets: []v1alpha1.AddressPlanSubnet{
+					{
+						Name: "dc",
+						CIDR: v1alpha1.CidrMustParse("10.0.0.0/22"),
+						Regions: []v1alpha1.Region{
+							{Name: "euw", AvailabilityZones: []string{"a"}},
+							{Name: "eun", AvailabilityZones: []string{"a"}},
+						},
+					},
+					{
+						Name:       "rack",
+						Parent:     "dc",
+						PrefixBits: ptr.To[byte](24),
+						FanOut:     v1alpha1.RegionAddressPlanFanOut,
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, plan)).To(Succeed())
+
+		By("Planning the changes")
+		Eventually(Object(plan)).Should(SatisfyAll(
+			HaveField("Status.State", v1alpha1.PlannedAddressPlanState),
+			HaveField("Status.Revision", Not(BeEmpty())),
+			HaveField("Status.Changes", ConsistOf(
+				v1alpha1.AddressPlanChange{Action: v1alpha1.CreateAddressPlanChangeAction, Kind: "Network", Name: "eu"},
+				v1alpha1.AddressPlanChange{Action: v1alpha1.CreateAddressPlanChangeAction, Kind: "Subnet", Name: "eu-dc", Details: "10.0.0.0/22"},
+				v1alpha1.AddressPlanChange{Action: v1alpha1.CreateAddressPlanChangeAction, Kind: "Subnet", Name: "eu-dc-rack-euw", Details: "/24 in eu-dc"},
+				v1alpha1.AddressPlanChange{Action: v1alpha1.CreateAddressPlanChangeAction, Kind: "Subnet", Name: "eu-dc-rack-eun", Details: "/24 in eu-dc"},
+			)),
+		))
+		Consistently(Get(subnet("eu-dc"))).Should(Satisfy(apierrors.IsNotFound))
+
+		By("Approving the revision")
+		Eventually(Update(plan, func() {
+			plan.Spec.ApprovedRevision = plan.Status.Revision
+		})).Should(Succeed())
+		Eventually(Object(plan)).Should(SatisfyAll(
+			HaveField("Status.State", v1alpha1.SyncedAddressPlanState),
+			HaveField("Status.AppliedRevision", plan.Spec.ApprovedRevision),
+			HaveField("Status.Changes", BeEmpty()),
+		))
+		Expect(Object(subnet("eu-dc-rack-euw"))()).To(SatisfyAll(
+			HaveField("Status.Reserved.String()", "10.0.0.0/24"),
+			HaveField("OwnerReferences", ContainElement(HaveField("Name", plan.Name))),
+		))
+		Expect(Object(subnet("eu-dc-rack-eun"))()).To(HaveField("Status.Reserved.String()", "10.0.1.0/24"))
+
+		By("Updating labels with automatic approval")
+		Eventually(Update(plan, func() {
+			plan.Spec.Approval = v1alpha1.AutomaticAddressPlanApproval
+			plan.Spec.Subnets[1].Labels = map[string]string{"role": "rack"}
+		})).Should(Succeed())
+		Eventually(Object(subnet("eu-dc-rack-euw"))).Should(HaveField("Labels", HaveKeyWithValue("role", "rack")))
+		Eventually(Object(plan)).Should(HaveField("Status.State", v1alpha1.SyncedAddressPlanState))
+
+		By("Removing subnets, one of them in use")
+		ip := &v1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{Name: "host", Namespace: ns.Name},
+			Spec: v1alpha1.IPSpec{
+				Subnet: v1alpha1.SubnetReference{Name: "eu-dc-rack-eun"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, ip)).To(Succeed())
+		Eventually(Object(ip)).Should(HaveField("Status.State", v1alpha1.FinishedIPState))
+
+		Eventually(Update(plan, func() {
+			plan.Spec.Subnets = plan.Spec.Subnets[:1]
+		})).Should(Succeed())
+		Eventually(Get(subnet("eu-dc-rack-euw"))).Should(Satisfy(apierrors.IsNotFound))
+		Eventually(Object(plan)).Should(SatisfyAll(
+			HaveField("Status.State", v1alpha1.BlockedAddressPlanState),
+			HaveField("Status.Changes", ConsistOf(v1alpha1.AddressPlanChange{
+				Action:  v1alpha1.DeleteAddressPlanChangeAction,
+				Kind:    "Subnet",
+				Name:    "eu-dc-rack-eun",
+				Details: "/24 in eu-dc",
+				Blocked: true,
+			})),
+		))
+		Consistently(Get(subnet("eu-dc-rack-eun"))).Should(Succeed())
+	})
+
+	It("Should recreate the network once its immutable fields change", func(ctx SpecContext) {
+		plan := &v1alpha1.AddressPlan{
+			ObjectMeta: metav1.ObjectMeta{Name: "us", Namespace: ns.Name},
+			Spec: v1alpha1.AddressPlanSpec{
+				Network: v1alpha1.AddressPlanNetwork{
+					Spec: v1alpha1.NetworkSpec{Type: v1alpha1.VXLANNetworkType, ID: v1alpha1.NetworkIDFromInt64(1000)},
+				},
+				Subnets: []v1alpha1.AddressPlanSubnet{
+					{Name: "dc", CIDR: v1alpha1.CidrMustParse("10.1.0.0/22")},
+				},
+				Approval: v1alpha1.AutomaticAddressPlanApproval,
+			},
+		}
+		Expect(k8sClient.Create(ctx, plan)).To(Succeed())
+		Eventually(Object(plan)).Should(HaveField("Status.State", v1alpha1.SyncedAddressPlanState))
+
+		network := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "us", Namespace: ns.Name},
+		}
+		Expect(Object(network)()).To(HaveField("Status.Reserved.String()", "1000"))
+
+		Eventually(Update(plan, func() {
+			plan.Spec.Network.Spec.ID = v1alpha1.NetworkIDFromInt64(2000)
+		})).Should(Succeed())
+		Eventually(Object(network)).Should(HaveField("Status.Reserved.String()", "2000"))
+		Eventually(Object(plan)).Should(SatisfyAll(
+			HaveField("Status.State", v1alpha1.SyncedAddressPlanState),
+			HaveField("Status.ObservedGeneration", plan.Generation),
+		))
+		Expect(Object(subnet("us-dc"))()).To(HaveField("Status.State", v1alpha1.FinishedSubnetState))
+	})
+})
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// newLabeledConfigMapCache creates a cache of ConfigMaps having the label and adds it to the manager,
+// so controllers writing particular ConfigMaps do not make the manager cache every ConfigMap of the cluster.
+func newLabeledConfigMapCache(mgr ctrl.Manager, label string) (cache.Cache, error) {
+	labeled, err := labels.NewRequirement(label, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	configMaps, err := cache.New(mgr.GetConfig(), cache.Options{
+		HTTPClient: mgr.GetHTTPClient(),
+		Scheme:     mgr.GetScheme(),
+		Mapper:     mgr.GetRESTMapper(),
+		ByObject: map[client.Object]cache.ByObject{
+			&v1.ConfigMap{}: {Label: labels.NewSelector().Add(*labeled)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := mgr.Add(configMaps); err != nil {
+		return nil, err
+	}
+	return configMaps, nil
+}
@@ -0,0 +1,305 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+const (
+	CConsumerIPCreationFailureReason = "ConsumerIPCreationFailure"
+	CConsumerIPPoolExhaustedReason   = "ConsumerIPPoolExhausted"
+	CConsumerIPConflictReason        = "ConsumerIPConflict"
+	CConsumerIPReservationReason     = "ConsumerIPReservation"
+	CConsumerIPReleaseReason         = "ConsumerIPRelease"
+)
+
+// ConsumerIPReconciler allocates IPs for resources of a particular kind,
+// that are annotated with v1alpha1.SubnetAnnotation or v1alpha1.PoolAnnotation.
+// IPs are owned by the annotated resource, so they are garbage collected with it,
+// and the reserved address is written back to v1alpha1.IPAnnotation.
+type ConsumerIPReconciler struct {
+	client.Client
+	Log           logr.Logger
+	Scheme        *runtime.Scheme
+	EventRecorder events.EventRecorder
+	// GVK is the kind of annotated resources
+	GVK schema.GroupVersionKind
+}
+
+// +kubebuilder:rbac:groups="",resources=pods;services,verbs=get;list;watch;update;patch
+
+// Reconcile creates, replaces or releases the IP of the annotated resource.
+func (r *ConsumerIPReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues(strings.ToLower(r.GVK.Kind), req.NamespacedName)
+
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(r.GVK)
+	err := r.Get(ctx, req.NamespacedName, obj)
+	if apierrors.IsNotFound(err) {
+		log.Info("Resource not found, it might have been deleted.")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if err != nil {
+		log.Error(err, "unable to get consumer resource", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	// IP is garbage collected once the owner is deleted.
+	if obj.GetDeletionTimestamp() != nil || v1alpha1.IsReconcilePaused(obj.Annotations) {
+		return ctrl.Result{}, nil
+	}
+
+	ipNamespacedName := types.NamespacedName{Namespace: obj.Namespace, Name: consumerIPName(r.GVK, obj.Name)}
+	var ip *v1alpha1.IP
+	existingIP := &v1alpha1.IP{}
+	err = r.Get(ctx, ipNamespacedName, existingIP)
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Error(err, "unable to get ip", "name", req.NamespacedName, "ip name", ipNamespacedName)
+		return ctrl.Result{}, err
+	}
+	if err == nil {
+		ip = existingIP
+	}
+
+	if ip != nil && !metav1.IsControlledBy(ip, obj) {
+		r.EventRecorder.Eventf(obj, nil, v1.EventTypeWarning, CConsumerIPConflictReason, "ConsumerIPReservation",
+			"IP %s already exists and is not owned by the resource", ipNamespacedName.Name)
+		return ctrl.Result{}, nil
+	}
+
+	// If released IP is still being deleted, then a new one is created once the deletion is observed.
+	if ip != nil && ip.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, nil
+	}
+
+	// If IP is not requested anymore, or requested from another subnet or pool, then it should be released.
+	if ip != nil && !sameIPRequest(ip.Annotations, obj.Annotations) {
+		if err := releaseConsumerIP(ctx, r.Client, ip); err != nil {
+			log.Error(err, "unable to release ip", "name", req.NamespacedName, "ip name", ipNamespacedName)
+			return ctrl.Result{}, err
+		}
+		r.EventRecorder.Eventf(obj, nil, v1.EventTypeNormal, CConsumerIPReleaseReason, "ConsumerIPRelease", "IP %s released", ipNamespacedName.Name)
+		return ctrl.Result{}, r.setIPAnnotation(ctx, obj, "")
+	}
+
+	if !isIPRequested(obj.Annotations) {
+		return ctrl.Result{}, r.setIPAnnotation(ctx, obj, "")
+	}
+
+	if ip == nil {
+		ref, err := r.requestedSubnet(ctx, obj)
+		if err != nil {
+			log.Error(err, "unable to find subnet for ip", "name", req.NamespacedName)
+			r.EventRecorder.Eventf(obj, nil, v1.EventTypeWarning, CConsumerIPPoolExhaustedReason, "ConsumerIPReservation", err.Error())
+			return ctrl.Result{}, err
+		}
+		ip = newConsumerIP(obj, r.GVK, ipNamespacedName.Name, ref, nil)
+		if err := r.Create(ctx, ip); err != nil {
+			log.Error(err, "unable to create ip", "name", req.NamespacedName, "ip name", ipNamespacedName)
+			r.EventRecorder.Eventf(obj, nil, v1.EventTypeWarning, CConsumerIPCreationFailureReason, "ConsumerIPReservation", err.Error())
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if ip.Status.State != v1alpha1.FinishedIPState || ip.Status.Reserved == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if obj.Annotations[v1alpha1.IPAnnotation] == ip.Status.Reserved.String() {
+		return ctrl.Result{}, nil
+	}
+	if err := r.setIPAnnotation(ctx, obj, ip.Status.Reserved.String()); err != nil {
+		log.Error(err, "unable to set ip annotation", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+	r.EventRecorder.Eventf(obj, nil, v1.EventTypeNormal, CConsumerIPReservationReason, "ConsumerIPReservation", "IP %s reserved", ip.Status.Reserved.String())
+
+	return ctrl.Result{}, nil
+}
+
+// requestedSubnet resolves the subnet the IP should be allocated from.
+// Subnets of a pool are tried in name order, the first one with free capacity is picked.
+func (r *ConsumerIPReconciler) requestedSubnet(ctx context.Context, obj client.Object) (v1alpha1.SubnetReference, error) {
+	annotations := obj.GetAnnotations()
+	if subnet, ok := annotations[v1alpha1.SubnetAnnotation]; ok {
+		return v1alpha1.SubnetReferenceFromAnnotation(subnet), nil
+	}
+
+	return poolSubnet(ctx, r.Client, obj.GetNamespace(), annotations[v1alpha1.PoolAnnotation], "", nil)
+}
+
+// poolSubnet picks a subnet of the pool to allocate an IP from.
+// Subnets are tried in name order, the first one of the family with free capacity, or containing
+// the explicitly requested address, is picked. Subnets of any family are considered if family is empty.
+func poolSubnet(ctx context.Context, c client.Client, namespace, pool string,
+	family v1alpha1.SubnetAddressType, addr *v1alpha1.IPAddr) (v1alpha1.SubnetReference, error) {
+	subnets := &v1alpha1.SubnetList{}
+	if err := c.List(ctx, subnets, client.InNamespace(namespace), client.MatchingLabels{v1alpha1.PoolLabel: pool}); err != nil {
+		return v1alpha1.SubnetReference{}, errors.Wrap(err, "unable to list pool subnets")
+	}
+	slices.SortFunc(subnets.Items, func(a, b v1alpha1.Subnet) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, subnet := range subnets.Items {
+		if subnet.Status.State != v1alpha1.FinishedSubnetState || (family != "" && subnet.Status.Type != family) {
+			continue
+		}
+		if (addr != nil && subnet.Status.Reserved.CanReserve(addr.AsCidr())) ||
+			(addr == nil && subnet.Status.CapacityLeft.Sign() > 0) {
+			return v1alpha1.SubnetReference{Name: subnet.Name}, nil
+		}
+	}
+	if addr != nil {
+		return v1alpha1.SubnetReference{}, errors.Errorf("no subnet of pool %s contains address %s", pool, addr.String())
+	}
+	return v1alpha1.SubnetReference{}, errors.Errorf("no subnet of pool %s has free capacity", pool)
+}
+
+// newConsumerIP builds an IP owned by the annotated resource.
+// Request annotations are copied to the IP, so a changed request may be detected.
+func newConsumerIP(obj client.Object, gvk schema.GroupVersionKind, name string, ref v1alpha1.SubnetReference, addr *v1alpha1.IPAddr) *v1alpha1.IP {
+	annotations := map[string]string{}
+	for _, key := range []string{v1alpha1.SubnetAnnotation, v1alpha1.PoolAnnotation} {
+		if value, ok := obj.GetAnnotations()[key]; ok {
+			annotations[key] = value
+		}
+	}
+	return &v1alpha1.IP{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       obj.GetNamespace(),
+			Annotations:     annotations,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(obj, gvk)},
+		},
+		Spec: v1alpha1.IPSpec{
+			Subnet: ref,
+			Consumer: &v1alpha1.ResourceReference{
+				APIVersion: gvk.GroupVersion().String(),
+				Kind:       gvk.Kind,
+				Name:       obj.GetName(),
+			},
+			IP: addr,
+		},
+	}
+}
+
+// releaseConsumerIP deletes the IP of a still existing resource.
+// Consumer is removed first, since the webhook prevents deletion of IPs with existing consumers.
+func releaseConsumerIP(ctx context.Context, c client.Client, ip *v1alpha1.IP) error {
+	if ip.Spec.Consumer != nil {
+		ip.Spec.Consumer = nil
+		if err := c.Update(ctx, ip); err != nil {
+			return err
+		}
+	}
+	return client.IgnoreNotFound(c.Delete(ctx, ip))
+}
+
+// setIPAnnotation writes the reserved address to the resource, or removes the annotation if the address is empty.
+func (r *ConsumerIPReconciler) setIPAnnotation(ctx context.Context, obj *metav1.PartialObjectMetadata, address string) error {
+	current, ok := obj.Annotations[v1alpha1.IPAnnotation]
+	if (address == "" && !ok) || (address != "" && current == address) {
+		return nil
+	}
+	patch := client.MergeFrom(obj.DeepCopy())
+	if address == "" {
+		delete(obj.Annotations, v1alpha1.IPAnnotation)
+	} else {
+		if obj.Annotations == nil {
+			obj.Annotations = map[string]string{}
+		}
+		obj.Annotations[v1alpha1.IPAnnotation] = address
+	}
+	return r.Patch(ctx, obj, patch)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ConsumerIPReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(r.GVK)
+
+	r.EventRecorder = newMetricsEventRecorder(mgr.GetEventRecorder("consumer-ip-controller"))
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("consumer-ip-" + strings.ToLower(strings.ReplaceAll(r.GVK.GroupKind().String(), ".", "-"))).
+		For(obj).
+		Owns(&v1alpha1.IP{}).
+		Complete(r)
+}
+
+// ParseConsumerKinds parses a comma separated list of kinds in apiVersion/Kind form, e.g. "v1/Pod,v1/Service".
+func ParseConsumerKinds(value string) ([]schema.GroupVersionKind, error) {
+	var kinds []schema.GroupVersionKind
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		idx := strings.LastIndex(item, "/")
+		if idx < 0 || idx == len(item)-1 {
+			return nil, errors.Errorf("kind %s should be set in apiVersion/Kind form", item)
+		}
+		gv, err := schema.ParseGroupVersion(item[:idx])
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse api version of kind %s", item)
+		}
+		kinds = append(kinds, gv.WithKind(item[idx+1:]))
+	}
+	return kinds, nil
+}
+
+// consumerIPName names the IP after the kind and the name of the resource. The suffix hashes group, kind and name,
+// so the name does not collide with IPs of other kinds, or with IPs of LoadBalancer Services named service-<name>-<family>.
+// The kind and the name are truncated, so the IP name does not exceed the object name length limit.
+func consumerIPName(gvk schema.GroupVersionKind, name string) string {
+	sum := sha256.Sum256([]byte(gvk.GroupKind().String() + "/" + name))
+	suffix := "-" + hex.EncodeToString(sum[:])[:8]
+	return truncateName(strings.ToLower(gvk.Kind)+"-"+name, validation.DNS1123SubdomainMaxLength-len(suffix)) + suffix
+}
+
+// truncateName shortens the name to the length, trailing separators are trimmed, so the name may be suffixed.
+func truncateName(name string, length int) string {
+	if len(name) <= length {
+		return name
+	}
+	return strings.TrimRight(name[:length], "-.")
+}
+
+func isIPRequested(annotations map[string]string) bool {
+	_, subnet := annotations[v1alpha1.SubnetAnnotation]
+	_, pool := annotations[v1alpha1.PoolAnnotation]
+	return subnet || pool
+}
+
+// sameIPRequest compares request annotations copied to the IP with the ones of the resource.
+func sameIPRequest(ipAnnotations, annotations map[string]string) bool {
+	for _, key := range []string{v1alpha1.SubnetAnnotation, v1alpha1.PoolAnnotation} {
+		ipValue, ipOk := ipAnnotations[key]
+		value, ok := annotations[key]
+		if ipOk != ok || ipValue != value {
+			return false
+		}
+	}
+	return true
+}
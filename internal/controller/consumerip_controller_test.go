@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Consumer IP controller", func() {
+	ns := SetupTest()
+	podGVK := corev1.SchemeGroupVersion.WithKind("Pod")
+
+	newSubnet := func(ctx SpecContext, name, cidr string, labels map[string]string) *v1alpha1.Subnet {
+		subnet := &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns.Name, Labels: labels},
+			Spec: v1alpha1.SubnetSpec{
+				CIDR:    v1alpha1.CidrMustParse(cidr),
+				Network: corev1.LocalObjectReference{Name: "network"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, subnet)).To(Succeed())
+		Eventually(Object(subnet)).Should(HaveField("Status.State", v1alpha1.FinishedSubnetState))
+		return subnet
+	}
+	newPod := func(ctx SpecContext, name string, annotations map[string]string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns.Name, Annotations: annotations},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "main", Image: "busybox"}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+		return pod
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		network := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: ns.Name},
+		}
+		Expect(k8sClient.Create(ctx, network)).To(Succeed())
+		Eventually(Object(network)).Should(HaveField("Status.State", v1alpha1.CFinishedNetworkState))
+	})
+
+	It("Should parse consumer kinds", func() {
+		kinds, err := ParseConsumerKinds("v1/Pod, v1/Service,metal.ironcore.dev/v1alpha1/Machine")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kinds).To(Equal([]schema.GroupVersionKind{
+			{Version: "v1", Kind: "Pod"},
+			{Version: "v1", Kind: "Service"},
+			{Group: "metal.ironcore.dev", Version: "v1alpha1", Kind: "Machine"},
+		}))
+
+		_, err = ParseConsumerKinds("Pod")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should bound IP names of consumers with long names", func() {
+		long := strings.Repeat("a", validation.DNS1123SubdomainMaxLength)
+		name := consumerIPName(podGVK, long)
+		Expect(validation.IsDNS1123Subdomain(name)).To(BeEmpty())
+		Expect(name).NotTo(Equal(consumerIPName(podGVK, long[:len(long)-1])))
+		Expect(consumerIPName(podGVK, "pod")).To(HavePrefix("pod-pod-"))
+	})
+
+	It("Should allocate an owned IP for the annotated pod and release it once the annotation is removed", func(ctx SpecContext) {
+		subnet := newSubnet(ctx, "subnet", "10.0.0.0/24", nil)
+		pod := newPod(ctx, "pod", map[string]string{v1alpha1.SubnetAnnotation: subnet.Name})
+
+		ip := &v1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{Name: consumerIPName(podGVK, pod.Name), Namespace: ns.Name},
+		}
+		Eventually(Object(ip)).Should(SatisfyAll(
+			HaveField("Spec.Subnet.Name", subnet.Name),
+			HaveField("Spec.Consumer", Equal(&v1alpha1.ResourceReference{APIVersion: "v1", Kind: "Pod", Name: pod.Name})),
+			HaveField("OwnerReferences", ContainElement(HaveField("UID", pod.UID))),
+			HaveField("Status.State", v1alpha1.FinishedIPState),
+		))
+		Eventually(Object(pod)).Should(HaveField("Annotations", HaveKeyWithValue(v1alpha1.IPAnnotation, "10.0.0.0")))
+
+		Eventually(Update(pod, func() {
+			delete(pod.Annotations, v1alpha1.SubnetAnnotation)
+		})).Should(Succeed())
+		Eventually(Get(ip)).Should(Satisfy(apierrors.IsNotFound))
+		Eventually(Object(pod)).Should(HaveField("Annotations", Not(HaveKey(v1alpha1.IPAnnotation))))
+	})
+
+	It("Should not adopt an existing IP owned by someone else", func(ctx SpecContext) {
+		subnet := newSubnet(ctx, "subnet", "10.0.0.0/24", nil)
+		ip := &v1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{Name: consumerIPName(podGVK, "pod"), Namespace: ns.Name},
+			Spec:       v1alpha1.IPSpec{Subnet: v1alpha1.SubnetReference{Name: subnet.Name}},
+		}
+		Expect(k8sClient.Create(ctx, ip)).To(Succeed())
+
+		pod := newPod(ctx, "pod", map[string]string{v1alpha1.SubnetAnnotation: subnet.Name})
+		Consistently(Object(ip)).Should(HaveField("OwnerReferences", BeEmpty()))
+		Expect(Object(pod)()).To(HaveField("Annotations", Not(HaveKey(v1alpha1.IPAnnotation))))
+	})
+
+	It("Should allocate the IP from a pool subnet with free capacity", func(ctx SpecContext) {
+		pool := map[string]string{v1alpha1.PoolLabel: "pool"}
+		full := newSubnet(ctx, "a-full", "10.0.0.0/32", pool)
+		Expect(k8sClient.Create(ctx, &v1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{Name: "taken", Namespace: ns.Name},
+			Spec:       v1alpha1.IPSpec{Subnet: v1alpha1.SubnetReference{Name: full.Name}},
+		})).To(Succeed())
+		Eventually(Object(full)).Should(HaveField("Status.CapacityLeft.Value()", int64(0)))
+		newSubnet(ctx, "b-free", "10.0.1.0/24", pool)
+
+		pod := newPod(ctx, "pod", map[string]string{v1alpha1.PoolAnnotation: "pool"})
+		Eventually(Object(pod)).Should(HaveField("Annotations", HaveKeyWithValue(v1alpha1.IPAnnotation, "10.0.1.0")))
+	})
+})
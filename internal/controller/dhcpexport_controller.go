@@ -0,0 +1,222 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+	"github.com/ironcore-dev/ipam/dhcp"
+)
+
+const (
+	CDHCPExportFailureReason = "DHCPExportFailure"
+
+	// CDHCPConsumerSyncTimeout bounds the wait for the cache of a consumer kind on lookups of hardware addresses
+	CDHCPConsumerSyncTimeout = 5 * time.Second
+
+	// CKeaDHCPExport is a value of v1alpha1.DHCPExportLabel selecting Kea subnet definitions
+	CKeaDHCPExport = "kea"
+	// CDnsmasqDHCPExport is a value of v1alpha1.DHCPExportLabel selecting dnsmasq configuration
+	CDnsmasqDHCPExport = "dnsmasq"
+)
+
+// dhcpExportKeys maps values of v1alpha1.DHCPExportLabel to ConfigMap keys and formats of the exported configuration.
+var dhcpExportKeys = map[string]map[string]string{
+	CKeaDHCPExport: {
+		"kea-dhcp4.json": dhcp.KeaDHCP4Format,
+		"kea-dhcp6.json": dhcp.KeaDHCP6Format,
+	},
+	CDnsmasqDHCPExport: {
+		"dnsmasq.conf": dhcp.DnsmasqFormat,
+	},
+}
+
+// DHCPExportReconciler writes DHCP server configuration of Subnets and IPs to ConfigMaps labeled with
+// v1alpha1.DHCPExportLabel. Subnets of the ConfigMap namespace are exported, optionally restricted by
+// v1alpha1.DHCPSubnetSelectorAnnotation, with IPs of any namespace referring them. The configuration is
+// regenerated once Subnets or IPs change.
+type DHCPExportReconciler struct {
+	client.Client
+	Log           logr.Logger
+	Scheme        *runtime.Scheme
+	EventRecorder events.EventRecorder
+
+	// configMaps caches only ConfigMaps labeled with v1alpha1.DHCPExportLabel
+	configMaps cache.Cache
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;update
+
+// Reconcile regenerates DHCP configuration stored in the ConfigMap.
+func (r *DHCPExportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("configmap", req.NamespacedName)
+
+	configMap := &v1.ConfigMap{}
+	err := r.configMaps.Get(ctx, req.NamespacedName, configMap)
+	if apierrors.IsNotFound(err) {
+		log.Info("ConfigMap not found, it might have been deleted.")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if err != nil {
+		log.Error(err, "unable to get configmap resource", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	if configMap.GetDeletionTimestamp() != nil || v1alpha1.IsReconcilePaused(configMap.Annotations) {
+		return ctrl.Result{}, nil
+	}
+
+	exportKeys, ok := dhcpExportKeys[configMap.Labels[v1alpha1.DHCPExportLabel]]
+	if !ok {
+		err := errors.Errorf("unknown dhcp export format %s, expected %s or %s",
+			configMap.Labels[v1alpha1.DHCPExportLabel], CKeaDHCPExport, CDnsmasqDHCPExport)
+		r.EventRecorder.Eventf(configMap, nil, v1.EventTypeWarning, CDHCPExportFailureReason, "DHCPExport", err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	selector, err := labels.Parse(configMap.Annotations[v1alpha1.DHCPSubnetSelectorAnnotation])
+	if err != nil {
+		r.EventRecorder.Eventf(configMap, nil, v1.EventTypeWarning, CDHCPExportFailureReason, "DHCPExport",
+			"invalid subnet selector: %s", err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	export, err := dhcp.Collect(ctx, r.Client, configMap.Namespace, selector, r.subnetIPs, r.consumerMAC)
+	if err != nil {
+		log.Error(err, "unable to collect subnets for dhcp export", "name", req.NamespacedName)
+		r.EventRecorder.Eventf(configMap, nil, v1.EventTypeWarning, CDHCPExportFailureReason, "DHCPExport", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	data := map[string]string{}
+	for key, format := range exportKeys {
+		out := &bytes.Buffer{}
+		if err := dhcp.Render(out, export, format); err != nil {
+			log.Error(err, "unable to render dhcp export", "name", req.NamespacedName, "format", format)
+			return ctrl.Result{}, err
+		}
+		data[key] = out.String()
+	}
+
+	// Keys not managed by the export are kept as they are
+	updated := maps.Clone(configMap.Data)
+	if updated == nil {
+		updated = map[string]string{}
+	}
+	maps.Copy(updated, data)
+	if maps.Equal(updated, configMap.Data) {
+		return ctrl.Result{}, nil
+	}
+	configMap.Data = updated
+	if err := r.Update(ctx, configMap); err != nil {
+		log.Error(err, "unable to update configmap", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// subnetIPs lists finished IPs of all namespaces referring the Subnet.
+func (r *DHCPExportReconciler) subnetIPs(ctx context.Context, subnet types.NamespacedName) ([]v1alpha1.IP, error) {
+	ips := &v1alpha1.IPList{}
+	if err := r.List(ctx, ips, client.MatchingFields{CSubnetIPIndexKey: subnet.String()}); err != nil {
+		return nil, err
+	}
+	return ips.Items, nil
+}
+
+// consumerMAC resolves hardware addresses of consumers through the metadata cache of the manager, so exports do not
+// read consumers from the API server on every change. The cache of a consumer kind is started on the first lookup,
+// consumers of kinds the manager may not list and watch are considered to have no address.
+func (r *DHCPExportReconciler) consumerMAC(ctx context.Context, ip *v1alpha1.IP) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, CDHCPConsumerSyncTimeout)
+	defer cancel()
+	mac, err := dhcp.ConsumerMAC(r.Client)(ctx, ip)
+	if apierrors.IsTimeout(err) {
+		return "", nil
+	}
+	return mac, err
+}
+
+// exports lists export ConfigMaps of the Subnet namespace, which select the Subnet.
+func (r *DHCPExportReconciler) exports(ctx context.Context, subnet *v1alpha1.Subnet) []reconcile.Request {
+	configMaps := &v1.ConfigMapList{}
+	if err := r.configMaps.List(ctx, configMaps, client.InNamespace(subnet.Namespace)); err != nil {
+		r.Log.Error(err, "unable to list dhcp export configmaps", "namespace", subnet.Namespace)
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, configMap := range configMaps.Items {
+		// Invalid selectors are reported once the ConfigMap itself is reconciled
+		selector, err := labels.Parse(configMap.Annotations[v1alpha1.DHCPSubnetSelectorAnnotation])
+		if err != nil || !selector.Matches(labels.Set(subnet.Labels)) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&configMap)})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DHCPExportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.EventRecorder = newMetricsEventRecorder(mgr.GetEventRecorder("dhcp-export-controller"))
+
+	// Export ConfigMaps are cached separately, so the manager cache does not hold every ConfigMap of the cluster
+	configMaps, err := newLabeledConfigMapCache(mgr, v1alpha1.DHCPExportLabel)
+	if err != nil {
+		return err
+	}
+	r.configMaps = configMaps
+
+	// Any change of a Subnet may change exports selecting it, label changes are mapped for both old and new labels
+	subnetExports := func(ctx context.Context, object client.Object) []reconcile.Request {
+		subnet, ok := object.(*v1alpha1.Subnet)
+		if !ok {
+			return nil
+		}
+		return r.exports(ctx, subnet)
+	}
+
+	// IPs may refer Subnets of other namespaces, their changes are propagated to exports selecting the Subnet
+	ipExports := func(ctx context.Context, object client.Object) []reconcile.Request {
+		ip, ok := object.(*v1alpha1.IP)
+		if !ok {
+			return nil
+		}
+		subnet := &v1alpha1.Subnet{}
+		if err := r.Get(ctx, ip.Spec.Subnet.NamespacedName(ip.Namespace), subnet); err != nil {
+			if !apierrors.IsNotFound(err) {
+				r.Log.Error(err, "unable to get subnet of ip", "name", client.ObjectKeyFromObject(ip))
+			}
+			return nil
+		}
+		return r.exports(ctx, subnet)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("dhcp-export").
+		WatchesRawSource(source.Kind(configMaps, &v1.ConfigMap{}, &handler.TypedEnqueueRequestForObject[*v1.ConfigMap]{})).
+		Watches(&v1alpha1.Subnet{}, handler.EnqueueRequestsFromMapFunc(subnetExports)).
+		Watches(&v1alpha1.IP{}, handler.EnqueueRequestsFromMapFunc(ipExports)).
+		Complete(r)
+}
@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DHCP export controller", func() {
+	ns := SetupTest()
+
+	It("Should export selected subnets and reservations of IPs with hardware addresses", func(ctx SpecContext) {
+		network := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: ns.Name},
+		}
+		Expect(k8sClient.Create(ctx, network)).To(Succeed())
+		Eventually(Object(network)).Should(HaveField("Status.State", v1alpha1.CFinishedNetworkState))
+
+		for name, prefix := range map[string]string{"exported": "10.0.0", "other": "10.1.0"} {
+			subnet := &v1alpha1.Subnet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: ns.Name,
+					Labels:    map[string]string{"dhcp": name},
+				},
+				Spec: v1alpha1.SubnetSpec{
+					CIDR:    v1alpha1.CidrMustParse(prefix + ".0/24"),
+					Network: corev1.LocalObjectReference{Name: network.Name},
+					Gateway: v1alpha1.IPMustParse(prefix + ".1"),
+				},
+			}
+			Expect(k8sClient.Create(ctx, subnet)).To(Succeed())
+			Eventually(Object(subnet)).Should(HaveField("Status.State", v1alpha1.FinishedSubnetState))
+		}
+
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "dhcp",
+				Namespace:   ns.Name,
+				Labels:      map[string]string{v1alpha1.DHCPExportLabel: CDnsmasqDHCPExport},
+				Annotations: map[string]string{v1alpha1.DHCPSubnetSelectorAnnotation: "dhcp=exported"},
+			},
+			Data: map[string]string{"custom.conf": "log-dhcp\n"},
+		}
+		Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
+		Eventually(Object(configMap)).Should(SatisfyAll(
+			HaveField("Data", HaveKeyWithValue("custom.conf", "log-dhcp\n")),
+			HaveField("Data", HaveKeyWithValue("dnsmasq.conf", SatisfyAll(
+				ContainSubstring("dhcp-range=set:"+ns.Name+"_exported,10.0.0.2,10.0.0.254,255.255.255.0"),
+				ContainSubstring("dhcp-option=tag:"+ns.Name+"_exported,option:router,10.0.0.1"),
+				Not(ContainSubstring("10.1.0.")),
+			))),
+		))
+
+		By("Reserving an IP with a hardware address")
+		ip := &v1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "host",
+				Namespace:   ns.Name,
+				Annotations: map[string]string{v1alpha1.MACAddressAnnotation: "aa:bb:cc:dd:ee:ff"},
+			},
+			Spec: v1alpha1.IPSpec{
+				Subnet: v1alpha1.SubnetReference{Name: "exported"},
+				IP:     v1alpha1.IPMustParse("10.0.0.10"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, ip)).To(Succeed())
+		Eventually(Object(configMap)).Should(HaveField("Data", HaveKeyWithValue("dnsmasq.conf", SatisfyAll(
+			ContainSubstring("dhcp-host=aa:bb:cc:dd:ee:ff,set:"+ns.Name+"_exported,10.0.0.10"),
+			ContainSubstring("dhcp-range=set:"+ns.Name+"_exported,10.0.0.11,10.0.0.254,255.255.255.0"),
+		))))
+
+		By("Reserving an IP of a consumer with a hardware address")
+		consumer := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "consumer",
+				Namespace:   ns.Name,
+				Annotations: map[string]string{v1alpha1.MACAddressAnnotation: "aa:bb:cc:dd:ee:02"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, consumer)).To(Succeed())
+		consumerIP := &v1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{Name: "consumer", Namespace: ns.Name},
+			Spec: v1alpha1.IPSpec{
+				Subnet:   v1alpha1.SubnetReference{Name: "exported"},
+				IP:       v1alpha1.IPMustParse("10.0.0.30"),
+				Consumer: &v1alpha1.ResourceReference{APIVersion: "v1", Kind: "Secret", Name: consumer.Name},
+			},
+		}
+		Expect(k8sClient.Create(ctx, consumerIP)).To(Succeed())
+		Eventually(Object(configMap)).Should(HaveField("Data", HaveKeyWithValue("dnsmasq.conf",
+			ContainSubstring("dhcp-host=aa:bb:cc:dd:ee:02,set:"+ns.Name+"_exported,10.0.0.30"),
+		)))
+
+		By("Reserving an IP with a hardware address from another namespace")
+		tenant := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{GenerateName: "tenant-"},
+		}
+		Expect(k8sClient.Create(ctx, tenant)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, tenant)
+
+		grant := &v1alpha1.IPAMReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: "grant", Namespace: ns.Name},
+			Spec: v1alpha1.IPAMReferenceGrantSpec{
+				From: []v1alpha1.ReferenceGrantFrom{{Kind: "IP", Namespace: tenant.Name}},
+				To:   []v1alpha1.ReferenceGrantTo{{Name: "exported"}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, grant)).To(Succeed())
+
+		tenantIP := &v1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "host",
+				Namespace:   tenant.Name,
+				Annotations: map[string]string{v1alpha1.MACAddressAnnotation: "aa:bb:cc:dd:ee:01"},
+			},
+			Spec: v1alpha1.IPSpec{
+				Subnet: v1alpha1.SubnetReference{Name: "exported", Namespace: ns.Name},
+				IP:     v1alpha1.IPMustParse("10.0.0.20"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, tenantIP)).To(Succeed())
+		Eventually(Object(configMap)).Should(HaveField("Data", HaveKeyWithValue("dnsmasq.conf",
+			ContainSubstring("dhcp-host=aa:bb:cc:dd:ee:01,set:"+ns.Name+"_exported,10.0.0.20"),
+		)))
+	})
+})
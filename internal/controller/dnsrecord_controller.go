@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"maps"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+	"github.com/ironcore-dev/ipam/dns"
+)
+
+const (
+	CDNSRecordFailureReason = "DNSRecordFailure"
+
+	// CDNSEndpointOutput writes records of a Subnet to an external-dns DNSEndpoint
+	CDNSEndpointOutput = "dnsendpoint"
+	// CZoneFileOutput writes records of a Subnet as zone files to a ConfigMap
+	CZoneFileOutput = "zonefile"
+)
+
+// DNSRecordReconciler writes forward and reverse DNS records of finished IPs of Subnets with DNS configuration
+// to an object named <subnet>-dns owned by the Subnet, either an external-dns DNSEndpoint or a ConfigMap of zone files.
+type DNSRecordReconciler struct {
+	client.Client
+	Log           logr.Logger
+	Scheme        *runtime.Scheme
+	EventRecorder events.EventRecorder
+	// Output is the kind of object records are written to, CDNSEndpointOutput or CZoneFileOutput
+	Output string
+
+	// outputs reads objects records are written to, ConfigMaps of zone files are cached only if they are labeled
+	// with v1alpha1.DNSZoneFilesLabel
+	outputs client.Reader
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=externaldns.k8s.io,resources=dnsendpoints,verbs=get;list;watch;create;update;delete
+
+// Reconcile regenerates DNS records of the Subnet.
+func (r *DNSRecordReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("subnet", req.NamespacedName)
+
+	subnet := &v1alpha1.Subnet{}
+	err := r.Get(ctx, req.NamespacedName, subnet)
+	if apierrors.IsNotFound(err) {
+		log.Info("Subnet not found, it might have been deleted.")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if err != nil {
+		log.Error(err, "unable to get subnet resource", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	if subnet.GetDeletionTimestamp() != nil || v1alpha1.IsReconcilePaused(subnet.Annotations) {
+		return ctrl.Result{}, nil
+	}
+
+	current := r.newOutput()
+	err = r.outputs.Get(ctx, client.ObjectKey{Namespace: subnet.Namespace, Name: dnsRecordsName(subnet)}, current)
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Error(err, "unable to get dns records", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+	exists := err == nil
+	if exists && !metav1.IsControlledBy(current, subnet) {
+		r.EventRecorder.Eventf(subnet, nil, v1.EventTypeWarning, CDNSRecordFailureReason, "DNSRecord",
+			"%s %s is not controlled by the subnet", current.GetObjectKind().GroupVersionKind().Kind, current.GetName())
+		return ctrl.Result{}, nil
+	}
+
+	if subnet.Spec.DNS == nil {
+		if !exists {
+			return ctrl.Result{}, nil
+		}
+		if err := r.Delete(ctx, current); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "unable to delete dns records", "name", req.NamespacedName)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if subnet.Status.State != v1alpha1.FinishedSubnetState || subnet.Status.Reserved == nil {
+		return ctrl.Result{}, nil
+	}
+
+	ips := &v1alpha1.IPList{}
+	if err := r.List(ctx, ips, client.MatchingFields{CSubnetIPIndexKey: req.NamespacedName.String()}); err != nil {
+		log.Error(err, "unable to list subnet ips", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+	records, errs := dns.Records(subnet, ips.Items)
+	if len(errs) > 0 {
+		r.EventRecorder.Eventf(subnet, nil, v1.EventTypeWarning, CDNSRecordFailureReason, "DNSRecord",
+			"%d ips skipped, first error: %s", len(errs), errs[0].Error())
+	}
+
+	desired, err := r.buildOutput(subnet, records, current, exists)
+	if err != nil {
+		log.Error(err, "unable to build dns records", "name", req.NamespacedName)
+		r.EventRecorder.Eventf(subnet, nil, v1.EventTypeWarning, CDNSRecordFailureReason, "DNSRecord", err.Error())
+		return ctrl.Result{}, err
+	}
+	if desired == nil {
+		return ctrl.Result{}, nil
+	}
+	if err := controllerutil.SetControllerReference(subnet, desired, r.Scheme); err != nil {
+		log.Error(err, "unable to set owner reference", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	if !exists {
+		if err := r.Create(ctx, desired); err != nil {
+			log.Error(err, "unable to create dns records", "name", req.NamespacedName)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	desired.SetResourceVersion(current.GetResourceVersion())
+	if err := r.Update(ctx, desired); err != nil {
+		log.Error(err, "unable to update dns records", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// buildOutput returns the object holding the records, or nil if the current object is up to date.
+func (r *DNSRecordReconciler) buildOutput(subnet *v1alpha1.Subnet, records []dns.Record, current client.Object, exists bool) (client.Object, error) {
+	name := dnsRecordsName(subnet)
+
+	if r.Output == CDNSEndpointOutput {
+		desired := dns.NewDNSEndpoint(subnet.Namespace, name, records, subnet.Spec.DNS.TTL)
+		if exists && equality.Semantic.DeepEqual(current.(*unstructured.Unstructured).Object["spec"], desired.Object["spec"]) {
+			return nil, nil
+		}
+		return desired, nil
+	}
+
+	// The serial of reverse zones is increased once the zone files change, so secondaries pick up the change
+	var serial uint32 = 1
+	var currentData map[string]string
+	if exists {
+		configMap := current.(*v1.ConfigMap)
+		currentData = configMap.Data
+		if value, err := strconv.ParseUint(configMap.Annotations[v1alpha1.DNSZoneSerialAnnotation], 10, 32); err == nil {
+			serial = uint32(value)
+		}
+	}
+	data, err := dns.ZoneFiles(subnet.Spec.DNS, subnet.Status.Reserved.Net, records, serial)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		if maps.Equal(data, currentData) {
+			return nil, nil
+		}
+		serial++
+		if data, err = dns.ZoneFiles(subnet.Spec.DNS, subnet.Status.Reserved.Net, records, serial); err != nil {
+			return nil, err
+		}
+	}
+
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   subnet.Namespace,
+			Labels:      map[string]string{v1alpha1.DNSZoneFilesLabel: subnet.Name},
+			Annotations: map[string]string{v1alpha1.DNSZoneSerialAnnotation: strconv.FormatUint(uint64(serial), 10)},
+		},
+		Data: data,
+	}, nil
+}
+
+func (r *DNSRecordReconciler) newOutput() client.Object {
+	if r.Output == CDNSEndpointOutput {
+		endpoint := &unstructured.Unstructured{}
+		endpoint.SetGroupVersionKind(dns.DNSEndpointGVK)
+		return endpoint
+	}
+	return &v1.ConfigMap{}
+}
+
+func dnsRecordsName(subnet *v1alpha1.Subnet) string {
+	return subnet.Name + "-dns"
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DNSRecordReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Output != CDNSEndpointOutput && r.Output != CZoneFileOutput {
+		return errors.Errorf("unknown dns record output %s, expected %s or %s", r.Output, CDNSEndpointOutput, CZoneFileOutput)
+	}
+	r.EventRecorder = newMetricsEventRecorder(mgr.GetEventRecorder("dns-record-controller"))
+
+	// Finished IPs are indexed by their subnet by the IP controller
+	ipSubnet := func(ctx context.Context, object client.Object) []reconcile.Request {
+		ip := object.(*v1alpha1.IP)
+		return []reconcile.Request{{NamespacedName: ip.Spec.Subnet.NamespacedName(ip.Namespace)}}
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named("dns-record").
+		For(&v1alpha1.Subnet{}).
+		Watches(&v1alpha1.IP{}, handler.EnqueueRequestsFromMapFunc(ipSubnet))
+
+	if r.Output == CDNSEndpointOutput {
+		r.outputs = r.Client
+		return b.Owns(r.newOutput()).Complete(r)
+	}
+
+	// ConfigMaps of zone files are cached separately, so the manager cache does not hold every ConfigMap of the cluster
+	configMaps, err := newLabeledConfigMapCache(mgr, v1alpha1.DNSZoneFilesLabel)
+	if err != nil {
+		return err
+	}
+	r.outputs = configMaps
+	return b.WatchesRawSource(source.Kind(configMaps, &v1.ConfigMap{},
+		handler.TypedEnqueueRequestForOwner[*v1.ConfigMap](mgr.GetScheme(), mgr.GetRESTMapper(), &v1alpha1.Subnet{}, handler.OnlyControllerOwner()))).
+		Complete(r)
+}
@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DNS record controller", func() {
+	ns := SetupTest()
+
+	It("Should write forward and reverse records of subnet ips as zone files", func(ctx SpecContext) {
+		network := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: ns.Name},
+		}
+		Expect(k8sClient.Create(ctx, network)).To(Succeed())
+		Eventually(Object(network)).Should(HaveField("Status.State", v1alpha1.CFinishedNetworkState))
+
+		subnet := &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: "rack", Namespace: ns.Name},
+			Spec: v1alpha1.SubnetSpec{
+				CIDR:    v1alpha1.CidrMustParse("10.0.0.0/24"),
+				Network: corev1.LocalObjectReference{Name: network.Name},
+				DNS: &v1alpha1.SubnetDNS{
+					Zone:        "example.net",
+					Nameservers: []string{"ns1.example.net"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, subnet)).To(Succeed())
+		Eventually(Object(subnet)).Should(HaveField("Status.State", v1alpha1.FinishedSubnetState))
+
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "rack-dns", Namespace: ns.Name},
+		}
+		Eventually(Object(configMap)).Should(SatisfyAll(
+			HaveField("OwnerReferences", ContainElement(HaveField("Name", subnet.Name))),
+			HaveField("Labels", HaveKeyWithValue(v1alpha1.DNSZoneFilesLabel, subnet.Name)),
+			HaveField("Annotations", HaveKeyWithValue(v1alpha1.DNSZoneSerialAnnotation, "1")),
+			HaveField("Data", HaveKeyWithValue("0.0.10.in-addr.arpa.zone", ContainSubstring("IN\tSOA\tns1.example.net."))),
+		))
+
+		By("Reserving an IP")
+		ip := &v1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{Name: "host", Namespace: ns.Name},
+			Spec: v1alpha1.IPSpec{
+				Subnet: v1alpha1.SubnetReference{Name: subnet.Name},
+				IP:     v1alpha1.IPMustParse("10.0.0.10"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, ip)).To(Succeed())
+		Eventually(Object(configMap)).Should(SatisfyAll(
+			HaveField("Annotations", HaveKeyWithValue(v1alpha1.DNSZoneSerialAnnotation, "2")),
+			HaveField("Data", HaveKeyWithValue("example.net.zone", ContainSubstring("host\tIN\tA\t10.0.0.10"))),
+			HaveField("Data", HaveKeyWithValue("0.0.10.in-addr.arpa.zone", ContainSubstring("10\tIN\tPTR\thost.example.net."))),
+		))
+
+		By("Releasing the IP")
+		Expect(k8sClient.Delete(ctx, ip)).To(Succeed())
+		Eventually(Object(configMap)).Should(SatisfyAll(
+			HaveField("Annotations", HaveKeyWithValue(v1alpha1.DNSZoneSerialAnnotation, "3")),
+			HaveField("Data", HaveKeyWithValue("example.net.zone", Not(ContainSubstring("10.0.0.10")))),
+			HaveField("Data", HaveKeyWithValue("0.0.10.in-addr.arpa.zone", Not(ContainSubstring("PTR")))),
+		))
+	})
+})
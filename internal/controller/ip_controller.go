@@ -7,9 +7,9 @@ import (
 	"context"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -18,7 +18,10 @@ import (
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
@@ -26,10 +29,15 @@ const (
 
 	CIPReservationFailureReason = "IPReservationFailure"
 	CIPProposalFailureReason    = "IPProposalFailure"
+	CIPReferenceFailureReason   = "IPReferenceFailure"
+	CIPAccessFailureReason      = "IPAccessFailure"
 	CIPReservationSuccessReason = "IPReservationSuccess"
 	CIPReleaseSuccessReason     = "IPReleaseSuccess"
 
 	IPFamilyLabelKey = "ip.ipam.metal.ironcore.dev/ip-family"
+
+	// CSubnetIPIndexKey indexes finished IPs by their subnet, so subnet network configuration changes are propagated
+	CSubnetIPIndexKey = "subnetIP"
 )
 
 // IPReconciler reconciles a Ip object
@@ -81,6 +89,11 @@ func (r *IPReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 		return ctrl.Result{}, nil
 	}
 
+	if v1alpha1.IsReconcilePaused(ip.Annotations) {
+		log.Info("reconciliation is paused", "name", req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
 	if !controllerutil.ContainsFinalizer(ip, CIPFinalizer) {
 		controllerutil.AddFinalizer(ip, CIPFinalizer)
 		err = r.Update(ctx, ip)
@@ -93,10 +106,7 @@ func (r *IPReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 
 	if _, ok := ip.Labels[IPFamilyLabelKey]; !ok {
 		subnet := &v1alpha1.Subnet{}
-		if err := r.Get(ctx, types.NamespacedName{
-			Namespace: ip.Namespace,
-			Name:      ip.Spec.Subnet.Name,
-		}, subnet); err != nil {
+		if err := r.Get(ctx, ip.Spec.Subnet.NamespacedName(ip.Namespace), subnet); err != nil {
 			log.Error(err, "unable to get subnet resource", "name", req.NamespacedName)
 			return ctrl.Result{}, err
 		}
@@ -111,6 +121,12 @@ func (r *IPReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 
 	if ip.Status.State == v1alpha1.FinishedIPState ||
 		ip.Status.State == v1alpha1.FailedIPState {
+		if ip.Status.State == v1alpha1.FinishedIPState {
+			if err := r.updateNetworkConfig(ctx, ip); err != nil {
+				log.Error(err, "unable to update ip network config", "name", req.NamespacedName)
+				return ctrl.Result{}, err
+			}
+		}
 		return ctrl.Result{}, nil
 	}
 
@@ -124,16 +140,43 @@ func (r *IPReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 		return ctrl.Result{}, nil
 	}
 
-	subnetNamespacedName := types.NamespacedName{
-		Namespace: ip.Namespace,
-		Name:      ip.Spec.Subnet.Name,
+	// Subnet may reside in another namespace if the reference is granted.
+	subnetNamespacedName := ip.Spec.Subnet.NamespacedName(ip.Namespace)
+
+	permitted, err := referencePermitted(ctx, r.Client, "IP", ip.Namespace, ip.Spec.Subnet)
+	if err != nil {
+		log.Error(err, "unable to check subnet reference", "name", req.NamespacedName, "subnet name", subnetNamespacedName)
+		return ctrl.Result{}, err
 	}
+	if !permitted {
+		err := referenceNotPermittedError(ip.Namespace, ip.Spec.Subnet)
+		ip.Status.State = v1alpha1.FailedIPState
+		ip.Status.Message = err.Error()
+		if err := r.Status().Update(ctx, ip); err != nil {
+			log.Error(err, "unable to update ip status", "name", req.NamespacedName)
+			return ctrl.Result{}, err
+		}
+		r.EventRecorder.Eventf(ip, nil, v1.EventTypeWarning, CIPReferenceFailureReason, "IPReference", ip.Status.Message)
+		return ctrl.Result{}, err
+	}
+
 	subnet := v1alpha1.Subnet{}
 	if err = r.Get(ctx, subnetNamespacedName, &subnet); err != nil {
 		log.Error(err, "unable to get subnet resource", "name", req.NamespacedName, "subnet name", subnetNamespacedName)
 		return ctrl.Result{}, err
 	}
 
+	if err := accessNotPermittedError(&subnet, ip.Spec.Consumer, ip.Spec.IP != nil, ip.CreationTimestamp); err != nil {
+		ip.Status.State = v1alpha1.FailedIPState
+		ip.Status.Message = err.Error()
+		if err := r.Status().Update(ctx, ip); err != nil {
+			log.Error(err, "unable to update ip status", "name", req.NamespacedName)
+			return ctrl.Result{}, err
+		}
+		r.EventRecorder.Eventf(ip, nil, v1.EventTypeWarning, CIPAccessFailureReason, "IPAccess", ip.Status.Message)
+		return ctrl.Result{}, nil
+	}
+
 	var ipCidrToReserve *v1alpha1.CIDR
 	if ip.Spec.IP != nil {
 		ipCidrToReserve = ip.Spec.IP.AsCidr()
@@ -163,6 +206,7 @@ func (r *IPReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 	}
 
 	if err := r.Status().Update(ctx, &subnet); err != nil {
+		countParentConflict(&subnet, err)
 		log.Error(err, "unable to update subnet status after ip reservation", "name", req.NamespacedName, "subnet name", subnetNamespacedName)
 		return ctrl.Result{}, err
 	}
@@ -170,10 +214,12 @@ func (r *IPReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 	ip.Status.State = v1alpha1.FinishedIPState
 	ip.Status.Message = ""
 	ip.Status.Reserved = ipCidrToReserve.AsIPAddr()
+	ip.Status.NetworkConfig = subnet.Status.NetworkConfig.DeepCopy()
 	if err := r.Status().Update(ctx, ip); err != nil {
 		log.Error(err, "unable to update ip status after ip reservation", "name", req.NamespacedName, "subnet name", subnetNamespacedName)
 		return ctrl.Result{}, err
 	}
+	observeAllocation(ip)
 	r.EventRecorder.Eventf(ip, nil, v1.EventTypeNormal, CIPReservationSuccessReason, "IPReservation", "IP %s reserved", ipCidrToReserve.String())
 
 	return ctrl.Result{}, nil
@@ -185,10 +231,7 @@ func (r *IPReconciler) finalizeIP(ctx context.Context, log logr.Logger, ip *v1al
 		return nil
 	}
 
-	subnetNamespacedName := types.NamespacedName{
-		Namespace: ip.Namespace,
-		Name:      ip.Spec.Subnet.Name,
-	}
+	subnetNamespacedName := ip.Spec.Subnet.NamespacedName(ip.Namespace)
 	subnet := v1alpha1.Subnet{}
 	err := r.Get(ctx, subnetNamespacedName, &subnet)
 	if apierrors.IsNotFound(err) {
@@ -213,6 +256,7 @@ func (r *IPReconciler) finalizeIP(ctx context.Context, log logr.Logger, ip *v1al
 	}
 
 	if err := r.Status().Update(ctx, &subnet); err != nil {
+		countParentConflict(&subnet, err)
 		log.Error(err, "unexpected error while updating subnet", "subnet name", subnetNamespacedName)
 		return err
 	}
@@ -222,10 +266,54 @@ func (r *IPReconciler) finalizeIP(ctx context.Context, log logr.Logger, ip *v1al
 	return nil
 }
 
+// updateNetworkConfig keeps network configuration of the reserved address in sync with the subnet.
+func (r *IPReconciler) updateNetworkConfig(ctx context.Context, ip *v1alpha1.IP) error {
+	subnet := &v1alpha1.Subnet{}
+	if err := r.Get(ctx, ip.Spec.Subnet.NamespacedName(ip.Namespace), subnet); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if equality.Semantic.DeepEqual(ip.Status.NetworkConfig, subnet.Status.NetworkConfig) {
+		return nil
+	}
+	ip.Status.NetworkConfig = subnet.Status.NetworkConfig.DeepCopy()
+	return r.Status().Update(ctx, ip)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *IPReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	r.EventRecorder = mgr.GetEventRecorder("ip-controller")
+	createSubnetIPIndexValue := func(object client.Object) []string {
+		ip, ok := object.(*v1alpha1.IP)
+		if !ok || ip.Status.State != v1alpha1.FinishedIPState {
+			return nil
+		}
+		return []string{subnetIndexValue(ip.Namespace, ip.Spec.Subnet)}
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(), &v1alpha1.IP{}, CSubnetIPIndexKey, createSubnetIPIndexValue); err != nil {
+		return err
+	}
+
+	// IPs of a subnet are requeued once subnet network configuration changes.
+	subnetIPs := func(ctx context.Context, object client.Object) []reconcile.Request {
+		ips := &v1alpha1.IPList{}
+		if err := r.List(ctx, ips, client.MatchingFields{CSubnetIPIndexKey: client.ObjectKeyFromObject(object).String()}); err != nil {
+			r.Log.Error(err, "unable to list subnet ips", "subnet", client.ObjectKeyFromObject(object))
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(ips.Items))
+		for i := range ips.Items {
+			if !equality.Semantic.DeepEqual(ips.Items[i].Status.NetworkConfig, object.(*v1alpha1.Subnet).Status.NetworkConfig) {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&ips.Items[i])})
+			}
+		}
+		return requests
+	}
+
+	r.EventRecorder = newMetricsEventRecorder(mgr.GetEventRecorder("ip-controller"))
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.IP{}).
+		Watches(&v1alpha1.Subnet{}, handler.EnqueueRequestsFromMapFunc(subnetIPs),
+			builder.WithPredicates(networkConfigChanged)).
 		Complete(r)
 }
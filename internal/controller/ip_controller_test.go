@@ -9,6 +9,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
 
 	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
 
@@ -167,7 +168,7 @@ var _ = Describe("IP controller", func() {
 					Namespace: ns.Name,
 				},
 				Spec: v1alpha1.IPSpec{
-					Subnet: corev1.LocalObjectReference{
+					Subnet: v1alpha1.SubnetReference{
 						Name: SubnetName,
 					},
 					IP: testIP,
@@ -270,4 +271,34 @@ var _ = Describe("IP controller", func() {
 			}).Should(BeTrue())
 		})
 	})
+
+	It("Should fail IPs created before the subnet, if its access policy does not allow them", func(ctx SpecContext) {
+		ip := &v1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{Name: "early", Namespace: ns.Name},
+			Spec: v1alpha1.IPSpec{
+				Subnet:   v1alpha1.SubnetReference{Name: "restricted"},
+				Consumer: &v1alpha1.ResourceReference{Kind: "Pod", Name: "pod"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, ip)).To(Succeed())
+
+		network := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "restricted", Namespace: ns.Name},
+		}
+		Expect(k8sClient.Create(ctx, network)).To(Succeed())
+		subnet := &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: "restricted", Namespace: ns.Name},
+			Spec: v1alpha1.SubnetSpec{
+				CIDR:         v1alpha1.CidrMustParse("10.0.0.0/24"),
+				Network:      corev1.LocalObjectReference{Name: network.Name},
+				AccessPolicy: &v1alpha1.SubnetAccessPolicy{Consumers: []v1alpha1.ConsumerKind{{Kind: "Machine"}}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, subnet)).To(Succeed())
+
+		Eventually(Object(ip)).Should(SatisfyAll(
+			HaveField("Status.State", v1alpha1.FailedIPState),
+			HaveField("Status.Message", ContainSubstring("access policy of subnet restricted")),
+		))
+	})
 })
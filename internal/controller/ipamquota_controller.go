@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+// IPAMQuotaReconciler reports usage of IPAM resources in IPAMQuota status.
+// Limits are enforced by validating webhooks of the limited resources.
+type IPAMQuotaReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=ipam.metal.ironcore.dev,resources=ipamquotas,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ipam.metal.ironcore.dev,resources=ipamquotas/status,verbs=get;update;patch
+
+// Reconcile calculates current usage of resources accounted by the quota.
+func (r *IPAMQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("ipamquota", req.NamespacedName)
+
+	quota := &v1alpha1.IPAMQuota{}
+	err := r.Get(ctx, req.NamespacedName, quota)
+	if apierrors.IsNotFound(err) {
+		log.Info("Resource not found, it might have been deleted.")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if err != nil {
+		log.Error(err, "unable to get ipam quota resource", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	if quota.GetDeletionTimestamp() != nil || v1alpha1.IsReconcilePaused(quota.Annotations) {
+		return ctrl.Result{}, nil
+	}
+
+	networks := &v1alpha1.NetworkList{}
+	if err := r.List(ctx, networks, client.InNamespace(req.Namespace)); err != nil {
+		log.Error(err, "unable to list networks", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+	subnets := &v1alpha1.SubnetList{}
+	if err := r.List(ctx, subnets, client.InNamespace(req.Namespace)); err != nil {
+		log.Error(err, "unable to list subnets", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+	ips := &v1alpha1.IPList{}
+	if err := r.List(ctx, ips, client.InNamespace(req.Namespace)); err != nil {
+		log.Error(err, "unable to list ips", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	status := v1alpha1.IPAMQuotaStatus{Hard: *quota.Spec.Hard.DeepCopy()}
+	used, err := quota.Usage(networks.Items, subnets.Items, ips.Items)
+	if err != nil {
+		log.Error(err, "unable to calculate quota usage", "name", req.NamespacedName)
+		status.Message = err.Error()
+	} else {
+		status.Used = used
+	}
+
+	if equality.Semantic.DeepEqual(quota.Status, status) {
+		return ctrl.Result{}, nil
+	}
+	quota.Status = status
+	if err := r.Status().Update(ctx, quota); err != nil {
+		log.Error(err, "unable to update ipam quota status", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// namespaceQuotas enqueues all quotas of the resource namespace, since any of them may select the resource.
+func (r *IPAMQuotaReconciler) namespaceQuotas(ctx context.Context, object client.Object) []reconcile.Request {
+	quotas := &v1alpha1.IPAMQuotaList{}
+	if err := r.List(ctx, quotas, client.InNamespace(object.GetNamespace())); err != nil {
+		r.Log.Error(err, "unable to list ipam quotas", "namespace", object.GetNamespace())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(quotas.Items))
+	for _, quota := range quotas.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: quota.Namespace, Name: quota.Name},
+		})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IPAMQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.IPAMQuota{}).
+		Watches(&v1alpha1.Network{}, handler.EnqueueRequestsFromMapFunc(r.namespaceQuotas)).
+		Watches(&v1alpha1.Subnet{}, handler.EnqueueRequestsFromMapFunc(r.namespaceQuotas)).
+		Watches(&v1alpha1.IP{}, handler.EnqueueRequestsFromMapFunc(r.namespaceQuotas)).
+		Complete(r)
+}
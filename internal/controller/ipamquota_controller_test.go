@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IPAMQuota controller", func() {
+	ns := SetupTest()
+
+	It("Should resolve capacity requested by subnets", func() {
+		requested := func(subnet, parent *v1alpha1.Subnet) int64 {
+			capacity := subnet.RequestedCapacity(parent)
+			return capacity.Value()
+		}
+		parent := &v1alpha1.Subnet{}
+		parent.FillStatusFromCidr(v1alpha1.CidrMustParse("fd00::/64"))
+
+		subnet := &v1alpha1.Subnet{Spec: v1alpha1.SubnetSpec{CIDR: v1alpha1.CidrMustParse("10.0.0.0/30")}}
+		Expect(requested(subnet, nil)).To(Equal(int64(4)))
+
+		subnet = &v1alpha1.Subnet{Spec: v1alpha1.SubnetSpec{PrefixBits: ptr.To[byte](120)}}
+		Expect(requested(subnet, parent)).To(Equal(int64(256)))
+		Expect(requested(subnet, nil)).To(BeZero())
+
+		subnet = &v1alpha1.Subnet{Spec: v1alpha1.SubnetSpec{Capacity: ptr.To(resource.MustParse("100"))}}
+		Expect(requested(subnet, nil)).To(Equal(int64(128)))
+	})
+
+	It("Should report usage of selected resources", func(ctx SpecContext) {
+		quota := &v1alpha1.IPAMQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: ns.Name},
+			Spec: v1alpha1.IPAMQuotaSpec{
+				Hard: v1alpha1.IPAMQuotaResources{
+					IPs:            ptr.To[int64](10),
+					SubnetCapacity: ptr.To(resource.MustParse("128")),
+					Networks:       ptr.To[int64](2),
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, quota)).To(Succeed())
+
+		network := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: ns.Name},
+		}
+		Expect(k8sClient.Create(ctx, network)).To(Succeed())
+
+		parent := &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: ns.Name},
+			Spec: v1alpha1.SubnetSpec{
+				CIDR:    v1alpha1.CidrMustParse("10.0.0.0/24"),
+				Network: corev1.LocalObjectReference{Name: network.Name},
+			},
+		}
+		Expect(k8sClient.Create(ctx, parent)).To(Succeed())
+
+		child := &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: ns.Name},
+			Spec: v1alpha1.SubnetSpec{
+				PrefixBits:   ptr.To[byte](26),
+				ParentSubnet: v1alpha1.SubnetReference{Name: parent.Name},
+				Network:      corev1.LocalObjectReference{Name: network.Name},
+			},
+		}
+		Expect(k8sClient.Create(ctx, child)).To(Succeed())
+
+		ip := &v1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{Name: "ip", Namespace: ns.Name},
+			Spec: v1alpha1.IPSpec{
+				Subnet: v1alpha1.SubnetReference{Name: child.Name},
+			},
+		}
+		Expect(k8sClient.Create(ctx, ip)).To(Succeed())
+
+		Eventually(Object(quota)).Should(SatisfyAll(
+			HaveField("Status.Hard.IPs", Equal(ptr.To[int64](10))),
+			HaveField("Status.Used.IPs", Equal(ptr.To[int64](1))),
+			HaveField("Status.Used.Networks", Equal(ptr.To[int64](1))),
+			HaveField("Status.Used.SubnetCapacity", WithTransform(func(q *resource.Quantity) int64 {
+				if q == nil {
+					return -1
+				}
+				return q.Value()
+			}, Equal(int64(64)))),
+		))
+	})
+})
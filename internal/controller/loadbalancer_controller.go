@@ -0,0 +1,266 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+const (
+	CLoadBalancerRequestFailureReason     = "LoadBalancerRequestFailure"
+	CLoadBalancerIPCreationFailureReason  = "LoadBalancerIPCreationFailure"
+	CLoadBalancerIPReservationReason      = "LoadBalancerIPReservation"
+	CLoadBalancerIPReleaseReason          = "LoadBalancerIPRelease"
+	CLoadBalancerIPOwnershipFailureReason = "LoadBalancerIPOwnershipFailure"
+)
+
+// LoadBalancerReconciler allocates ingress IPs of LoadBalancer Services of a particular load balancer class.
+// IPs are requested from Subnets or a pool, the Service is annotated with, one per each IP family of the Service.
+// IPs are owned by the Service, so they are garbage collected with it.
+type LoadBalancerReconciler struct {
+	client.Client
+	Log           logr.Logger
+	Scheme        *runtime.Scheme
+	EventRecorder events.EventRecorder
+	// LoadBalancerClass is the class of Services served by the controller
+	LoadBalancerClass string
+}
+
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=services/status,verbs=get;update;patch
+
+// Reconcile allocates IPs of the Service and publishes them in the Service load balancer status.
+func (r *LoadBalancerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("service", req.NamespacedName)
+
+	svc := &v1.Service{}
+	err := r.Get(ctx, req.NamespacedName, svc)
+	if apierrors.IsNotFound(err) {
+		log.Info("Resource not found, it might have been deleted.")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if err != nil {
+		log.Error(err, "unable to get service resource", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	// IPs are garbage collected once the Service is deleted.
+	if svc.GetDeletionTimestamp() != nil || v1alpha1.IsReconcilePaused(svc.Annotations) {
+		return ctrl.Result{}, nil
+	}
+
+	var families []v1.IPFamily
+	if svc.Spec.Type == v1.ServiceTypeLoadBalancer {
+		families = svc.Spec.IPFamilies
+	}
+
+	explicit, err := explicitLoadBalancerIPs(svc)
+	if err != nil {
+		log.Error(err, "unable to parse requested load balancer ips", "name", req.NamespacedName)
+		r.EventRecorder.Eventf(svc, nil, v1.EventTypeWarning, CLoadBalancerRequestFailureReason, "LoadBalancerIPReservation", err.Error())
+		return ctrl.Result{}, nil
+	}
+	if len(families) > 0 && !isIPRequested(svc.Annotations) {
+		r.EventRecorder.Eventf(svc, nil, v1.EventTypeWarning, CLoadBalancerRequestFailureReason, "LoadBalancerIPReservation",
+			"service should be annotated with %s or %s", v1alpha1.SubnetAnnotation, v1alpha1.PoolAnnotation)
+		families = nil
+	}
+
+	reserved := make(map[v1.IPFamily]string)
+	for _, family := range []v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol} {
+		ipNamespacedName := types.NamespacedName{Namespace: svc.Namespace, Name: loadBalancerIPName(svc.Name, family)}
+		var ip *v1alpha1.IP
+		existingIP := &v1alpha1.IP{}
+		err := r.Get(ctx, ipNamespacedName, existingIP)
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, "unable to get ip", "name", req.NamespacedName, "ip name", ipNamespacedName)
+			return ctrl.Result{}, err
+		}
+		if err == nil {
+			ip = existingIP
+		}
+
+		if ip != nil && !metav1.IsControlledBy(ip, svc) {
+			r.EventRecorder.Eventf(svc, nil, v1.EventTypeWarning, CLoadBalancerIPOwnershipFailureReason, "LoadBalancerIPReservation",
+				"IP %s already exists and is not owned by the service", ipNamespacedName.Name)
+			continue
+		}
+
+		// If released IP is still being deleted, then a new one is created once the deletion is observed.
+		if ip != nil && ip.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		requested := false
+		for i := range families {
+			requested = requested || families[i] == family
+		}
+		addr := explicit[family]
+
+		// If IP is not requested anymore, or requested from another subnet, pool or address, then it should be released.
+		if ip != nil && (!requested || !sameIPRequest(ip.Annotations, svc.Annotations) || !sameIPAddr(ip.Spec.IP, addr)) {
+			if err := releaseConsumerIP(ctx, r.Client, ip); err != nil {
+				log.Error(err, "unable to release ip", "name", req.NamespacedName, "ip name", ipNamespacedName)
+				return ctrl.Result{}, err
+			}
+			r.EventRecorder.Eventf(svc, nil, v1.EventTypeNormal, CLoadBalancerIPReleaseReason, "LoadBalancerIPRelease", "IP %s released", ipNamespacedName.Name)
+			continue
+		}
+
+		if !requested {
+			continue
+		}
+
+		if ip == nil {
+			ref, err := r.requestedSubnet(ctx, svc, family, addr)
+			if err != nil {
+				log.Error(err, "unable to find subnet for ip", "name", req.NamespacedName, "family", family)
+				r.EventRecorder.Eventf(svc, nil, v1.EventTypeWarning, CLoadBalancerRequestFailureReason, "LoadBalancerIPReservation", err.Error())
+				return ctrl.Result{}, err
+			}
+			ip = newConsumerIP(svc, v1.SchemeGroupVersion.WithKind("Service"), ipNamespacedName.Name, ref, addr)
+			if err := r.Create(ctx, ip); err != nil {
+				log.Error(err, "unable to create ip", "name", req.NamespacedName, "ip name", ipNamespacedName)
+				r.EventRecorder.Eventf(svc, nil, v1.EventTypeWarning, CLoadBalancerIPCreationFailureReason, "LoadBalancerIPReservation", err.Error())
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+
+		if ip.Status.State == v1alpha1.FinishedIPState && ip.Status.Reserved != nil {
+			reserved[family] = ip.Status.Reserved.String()
+		}
+	}
+
+	// Ingress follows the order of Service IP families, reserved addresses are published as soon as they are available.
+	// Services which are not load balancers anymore have no families, so their ingress is cleared.
+	ingress := make([]v1.LoadBalancerIngress, 0, len(reserved))
+	for _, family := range families {
+		if address, ok := reserved[family]; ok {
+			ingress = append(ingress, v1.LoadBalancerIngress{IP: address, IPMode: ptr.To(v1.LoadBalancerIPModeVIP)})
+		}
+	}
+	if equality.Semantic.DeepEqual(svc.Status.LoadBalancer.Ingress, ingress) ||
+		(len(svc.Status.LoadBalancer.Ingress) == 0 && len(ingress) == 0) {
+		return ctrl.Result{}, nil
+	}
+	svc.Status.LoadBalancer.Ingress = ingress
+	if err := r.Status().Update(ctx, svc); err != nil {
+		log.Error(err, "unable to update service status", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+	for _, item := range ingress {
+		r.EventRecorder.Eventf(svc, nil, v1.EventTypeNormal, CLoadBalancerIPReservationReason, "LoadBalancerIPReservation", "IP %s assigned", item.IP)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// requestedSubnet resolves the subnet of the family the IP should be allocated from.
+// Subnet annotation of a Service may list subnets of both families separated by comma.
+func (r *LoadBalancerReconciler) requestedSubnet(ctx context.Context, svc *v1.Service,
+	family v1.IPFamily, addr *v1alpha1.IPAddr) (v1alpha1.SubnetReference, error) {
+	subnets, ok := svc.Annotations[v1alpha1.SubnetAnnotation]
+	if !ok {
+		return poolSubnet(ctx, r.Client, svc.Namespace, svc.Annotations[v1alpha1.PoolAnnotation], v1alpha1.SubnetAddressType(family), addr)
+	}
+
+	for _, value := range strings.Split(subnets, ",") {
+		ref := v1alpha1.SubnetReferenceFromAnnotation(strings.TrimSpace(value))
+		subnet := &v1alpha1.Subnet{}
+		if err := r.Get(ctx, ref.NamespacedName(svc.Namespace), subnet); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return v1alpha1.SubnetReference{}, errors.Wrapf(err, "unable to get subnet %s", value)
+		}
+		if subnet.Status.Type == v1alpha1.SubnetAddressType(family) {
+			return ref, nil
+		}
+	}
+	return v1alpha1.SubnetReference{}, errors.Errorf("none of subnets %s is an existing %s subnet", subnets, family)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *LoadBalancerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	isServed := func(object client.Object) bool {
+		svc, ok := object.(*v1.Service)
+		return ok && ptr.Deref(svc.Spec.LoadBalancerClass, "") == r.LoadBalancerClass
+	}
+	// Load balancer class is cleared once the Service type is changed from LoadBalancer,
+	// so updates of Services having the class before or after the update are served to release their IPs.
+	hasClass := predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return isServed(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return isServed(e.ObjectOld) || isServed(e.ObjectNew) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return isServed(e.Object) },
+		GenericFunc: func(e event.GenericEvent) bool { return isServed(e.Object) },
+	}
+
+	r.EventRecorder = newMetricsEventRecorder(mgr.GetEventRecorder("load-balancer-controller"))
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1.Service{}, builder.WithPredicates(hasClass)).
+		Owns(&v1alpha1.IP{}).
+		Complete(r)
+}
+
+// explicitLoadBalancerIPs returns addresses requested by v1alpha1.LoadBalancerIPsAnnotation,
+// or by the deprecated spec.loadBalancerIP field, mapped by their family.
+func explicitLoadBalancerIPs(svc *v1.Service) (map[v1.IPFamily]*v1alpha1.IPAddr, error) {
+	value, ok := svc.Annotations[v1alpha1.LoadBalancerIPsAnnotation]
+	if !ok {
+		value = svc.Spec.LoadBalancerIP
+	}
+
+	addrs := make(map[v1.IPFamily]*v1alpha1.IPAddr)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		addr, err := v1alpha1.IPAddrFromString(item)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse load balancer ip %s", item)
+		}
+		family := v1.IPv4Protocol
+		if addr.Net.Is6() {
+			family = v1.IPv6Protocol
+		}
+		if _, ok := addrs[family]; ok {
+			return nil, errors.Errorf("more than one %s load balancer ip is requested", family)
+		}
+		addrs[family] = addr
+	}
+	return addrs, nil
+}
+
+func loadBalancerIPName(name string, family v1.IPFamily) string {
+	return fmt.Sprintf("service-%s-%s", name, strings.ToLower(string(family)))
+}
+
+func sameIPAddr(a, b *v1alpha1.IPAddr) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(b)
+}
@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const testLoadBalancerClass = "ipam.metal.ironcore.dev/test"
+
+var _ = Describe("LoadBalancer controller", func() {
+	ns := SetupTest()
+
+	newSubnet := func(ctx SpecContext, name, cidr string) *v1alpha1.Subnet {
+		subnet := &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns.Name},
+			Spec: v1alpha1.SubnetSpec{
+				CIDR:    v1alpha1.CidrMustParse(cidr),
+				Network: corev1.LocalObjectReference{Name: "network"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, subnet)).To(Succeed())
+		Eventually(Object(subnet)).Should(HaveField("Status.State", v1alpha1.FinishedSubnetState))
+		return subnet
+	}
+	newService := func(ctx SpecContext, annotations map[string]string, families ...corev1.IPFamily) *corev1.Service {
+		policy := corev1.IPFamilyPolicySingleStack
+		if len(families) > 1 {
+			policy = corev1.IPFamilyPolicyRequireDualStack
+		}
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "service", Namespace: ns.Name, Annotations: annotations},
+			Spec: corev1.ServiceSpec{
+				Type:              corev1.ServiceTypeLoadBalancer,
+				LoadBalancerClass: ptr.To(testLoadBalancerClass),
+				IPFamilies:        families,
+				IPFamilyPolicy:    &policy,
+				Ports:             []corev1.ServicePort{{Name: "http", Port: 80}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, svc)).To(Succeed())
+		return svc
+	}
+	ingressIPs := func(svc *corev1.Service) []string {
+		var ips []string
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			ips = append(ips, ingress.IP)
+		}
+		return ips
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		network := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: ns.Name},
+		}
+		Expect(k8sClient.Create(ctx, network)).To(Succeed())
+		Eventually(Object(network)).Should(HaveField("Status.State", v1alpha1.CFinishedNetworkState))
+	})
+
+	It("Should publish an IPv4 ingress and release it once the service is not a load balancer", func(ctx SpecContext) {
+		subnet := newSubnet(ctx, "v4", "10.0.0.0/24")
+		svc := newService(ctx, map[string]string{v1alpha1.SubnetAnnotation: subnet.Name})
+
+		ip := &v1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-service-ipv4", Namespace: ns.Name},
+		}
+		Eventually(Object(ip)).Should(SatisfyAll(
+			HaveField("Spec.Consumer", Equal(&v1alpha1.ResourceReference{APIVersion: "v1", Kind: "Service", Name: svc.Name})),
+			HaveField("OwnerReferences", ContainElement(HaveField("UID", svc.UID))),
+		))
+		Eventually(Object(svc)).Should(WithTransform(ingressIPs, Equal([]string{"10.0.0.0"})))
+
+		Eventually(Update(svc, func() {
+			svc.Spec.Type = corev1.ServiceTypeClusterIP
+			svc.Spec.LoadBalancerClass = nil
+		})).Should(Succeed())
+		Eventually(Get(ip)).Should(Satisfy(apierrors.IsNotFound))
+	})
+
+	It("Should release the ingress once only the service type is changed", func(ctx SpecContext) {
+		subnet := newSubnet(ctx, "v4", "10.0.0.0/24")
+		svc := newService(ctx, map[string]string{v1alpha1.SubnetAnnotation: subnet.Name})
+		Eventually(Object(svc)).Should(WithTransform(ingressIPs, Equal([]string{"10.0.0.0"})))
+
+		By("Changing the service type, while the load balancer class is cleared by the API server")
+		Eventually(Update(svc, func() {
+			svc.Spec.Type = corev1.ServiceTypeClusterIP
+		})).Should(Succeed())
+		Expect(svc.Spec.LoadBalancerClass).To(BeNil())
+
+		ip := &v1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-service-ipv4", Namespace: ns.Name},
+		}
+		Eventually(Get(ip)).Should(Satisfy(apierrors.IsNotFound))
+		Eventually(Object(svc)).Should(HaveField("Status.LoadBalancer.Ingress", BeEmpty()))
+	})
+
+	It("Should allocate dual stack ingress with an explicitly requested address", func(ctx SpecContext) {
+		v4 := newSubnet(ctx, "v4", "10.0.0.0/24")
+		v6 := newSubnet(ctx, "v6", "fd00::/120")
+		svc := newService(ctx, map[string]string{
+			v1alpha1.SubnetAnnotation:          v4.Name + "," + v6.Name,
+			v1alpha1.LoadBalancerIPsAnnotation: "fd00::10",
+		}, corev1.IPv6Protocol, corev1.IPv4Protocol)
+
+		Eventually(Object(svc)).Should(WithTransform(ingressIPs, Equal([]string{"fd00::10", "10.0.0.0"})))
+	})
+
+	It("Should ignore services of other load balancer classes", func(ctx SpecContext) {
+		subnet := newSubnet(ctx, "v4", "10.0.0.0/24")
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "other",
+				Namespace:   ns.Name,
+				Annotations: map[string]string{v1alpha1.SubnetAnnotation: subnet.Name},
+			},
+			Spec: corev1.ServiceSpec{
+				Type:              corev1.ServiceTypeLoadBalancer,
+				LoadBalancerClass: ptr.To("example.com/other"),
+				Ports:             []corev1.ServicePort{{Name: "http", Port: 80}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, svc)).To(Succeed())
+		Consistently(Object(subnet)).Should(HaveField("Status.CapacityLeft.Value()", int64(256)))
+	})
+})
@@ -0,0 +1,250 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+const (
+	CMetricsNamespace = "ipam"
+
+	// CCollectTimeout limits the time of listing resources on a metrics scrape
+	CCollectTimeout = 10 * time.Second
+)
+
+var (
+	subnetCapacityDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(CMetricsNamespace, "subnet", "capacity"),
+		"Total address capacity of the subnet.",
+		[]string{"namespace", "name", "network", "family", "region"}, nil,
+	)
+	subnetCapacityLeftDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(CMetricsNamespace, "subnet", "capacity_left"),
+		"Address capacity of the subnet, that is not reserved by child subnets and IPs.",
+		[]string{"namespace", "name", "network", "family", "region"}, nil,
+	)
+	subnetUtilizationDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(CMetricsNamespace, "subnet", "utilization_ratio"),
+		"Ratio of the subnet address capacity reserved by child subnets and IPs.",
+		[]string{"namespace", "name", "network", "family", "region"}, nil,
+	)
+	networkCapacityDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(CMetricsNamespace, "network", "capacity"),
+		"Total address capacity of the network ranges booked by top level subnets.",
+		[]string{"namespace", "name", "family"}, nil,
+	)
+	subnetVacantRangesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(CMetricsNamespace, "subnet", "vacant_ranges"),
+		"Number of vacant CIDR ranges of the subnet, that shows how fragmented its address space is.",
+		[]string{"namespace", "name", "network", "family", "region"}, nil,
+	)
+	networkCounterFreeIDsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(CMetricsNamespace, "networkcounter", "free_ids"),
+		"Amount of network IDs, that are not assigned yet. Unlimited amount is reported as +Inf.",
+		[]string{"namespace", "name", "type"}, nil,
+	)
+
+	reservationFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: CMetricsNamespace,
+			Name:      "reservation_failures_total",
+			Help:      "Number of failures reported by the controllers with warning events, by resource kind and event reason.",
+		},
+		[]string{"kind", "reason"},
+	)
+	allocationDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: CMetricsNamespace,
+			Name:      "allocation_duration_seconds",
+			Help:      "Time from creation of a resource until its processing has been finished, by resource kind.",
+			Buckets:   prometheus.ExponentialBuckets(0.05, 2, 14),
+		},
+		[]string{"kind"},
+	)
+	parentStatusConflictsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: CMetricsNamespace,
+			Name:      "parent_update_conflicts_total",
+			Help:      "Number of optimistic concurrency conflicts on updates of parent resources, by parent kind.",
+		},
+		[]string{"kind"},
+	)
+	failedChildRequeuesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: CMetricsNamespace,
+			Name:      "failed_child_requeues_total",
+			Help:      "Number of failed child resources requeued for processing, by child kind.",
+		},
+		[]string{"kind"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		reservationFailuresTotal,
+		allocationDurationSeconds,
+		parentStatusConflictsTotal,
+		failedChildRequeuesTotal,
+	)
+}
+
+// observeAllocation records the time since the resource creation, once its processing has been finished.
+func observeAllocation(obj client.Object) {
+	allocationDurationSeconds.WithLabelValues(objectKind(obj)).
+		Observe(time.Since(obj.GetCreationTimestamp().Time).Seconds())
+}
+
+// countParentConflict counts the parent update error, if it is caused by a concurrent update of the parent.
+func countParentConflict(parent client.Object, err error) {
+	if apierrors.IsConflict(err) {
+		parentStatusConflictsTotal.WithLabelValues(objectKind(parent)).Inc()
+	}
+}
+
+// CapacityCollector exposes capacity and utilization of Networks, Subnets and NetworkCounters.
+// Resources are listed on every scrape, so the reader should be backed by the manager cache.
+type CapacityCollector struct {
+	client.Reader
+	Log logr.Logger
+}
+
+// Describe implements prometheus.Collector
+func (c *CapacityCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- subnetCapacityDesc
+	ch <- subnetCapacityLeftDesc
+	ch <- subnetUtilizationDesc
+	ch <- subnetVacantRangesDesc
+	ch <- networkCapacityDesc
+	ch <- networkCounterFreeIDsDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *CapacityCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), CCollectTimeout)
+	defer cancel()
+
+	subnets := &v1alpha1.SubnetList{}
+	if err := c.List(ctx, subnets); err != nil {
+		c.Log.Error(err, "unable to list subnets for metrics")
+	}
+	for _, subnet := range subnets.Items {
+		if subnet.Status.State != v1alpha1.FinishedSubnetState {
+			continue
+		}
+		labels := []string{subnet.Namespace, subnet.Name, subnet.Spec.Network.Name, string(subnet.Status.Type), subnetRegions(&subnet)}
+		capacity := subnet.Status.Capacity.AsApproximateFloat64()
+		capacityLeft := subnet.Status.CapacityLeft.AsApproximateFloat64()
+		ch <- prometheus.MustNewConstMetric(subnetCapacityDesc, prometheus.GaugeValue, capacity, labels...)
+		ch <- prometheus.MustNewConstMetric(subnetCapacityLeftDesc, prometheus.GaugeValue, capacityLeft, labels...)
+		if capacity > 0 {
+			ch <- prometheus.MustNewConstMetric(subnetUtilizationDesc, prometheus.GaugeValue, (capacity-capacityLeft)/capacity, labels...)
+		}
+		ch <- prometheus.MustNewConstMetric(subnetVacantRangesDesc, prometheus.GaugeValue, float64(len(subnet.Status.Vacant)), labels...)
+	}
+
+	networks := &v1alpha1.NetworkList{}
+	if err := c.List(ctx, networks); err != nil {
+		c.Log.Error(err, "unable to list networks for metrics")
+	}
+	for _, network := range networks.Items {
+		for family, capacity := range map[v1alpha1.SubnetAddressType]resource.Quantity{
+			v1alpha1.IPv4SubnetType: network.Status.IPv4Capacity,
+			v1alpha1.IPv6SubnetType: network.Status.IPv6Capacity,
+		} {
+			ch <- prometheus.MustNewConstMetric(networkCapacityDesc, prometheus.GaugeValue, capacity.AsApproximateFloat64(),
+				network.Namespace, network.Name, string(family))
+		}
+	}
+
+	counters := &v1alpha1.NetworkCounterList{}
+	if err := c.List(ctx, counters); err != nil {
+		c.Log.Error(err, "unable to list network counters for metrics")
+	}
+	for _, counter := range counters.Items {
+		netType, err := counterNameToType(counter.Name)
+		if err != nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(networkCounterFreeIDsDesc, prometheus.GaugeValue, freeIDs(counter.Spec.Vacant),
+			counter.Namespace, counter.Name, string(netType))
+	}
+}
+
+// subnetRegions joins names of subnet regions, so a subnet is exported once even if it spans multiple regions.
+func subnetRegions(subnet *v1alpha1.Subnet) string {
+	regions := make([]string, 0, len(subnet.Spec.Regions))
+	for _, region := range subnet.Spec.Regions {
+		regions = append(regions, region.Name)
+	}
+	slices.Sort(regions)
+	return strings.Join(regions, ",")
+}
+
+// freeIDs counts IDs in vacant intervals. An interval without an end is unlimited.
+func freeIDs(vacant []v1alpha1.NetworkIDInterval) float64 {
+	total := big.NewInt(0)
+	for _, interval := range vacant {
+		switch {
+		case interval.Exact != nil:
+			total.Add(total, v1alpha1.Increment)
+		case interval.Begin != nil && interval.End != nil:
+			total.Add(total, new(big.Int).Sub(&interval.End.Int, &interval.Begin.Int))
+			total.Add(total, v1alpha1.Increment)
+		case interval.Begin != nil:
+			return math.Inf(1)
+		}
+	}
+	free, _ := new(big.Float).SetInt(total).Float64()
+	return free
+}
+
+// metricsEventRecorder counts warning events, since controllers emit them on every failure.
+// Low capacity warnings are not failures, and are exported by the capacity collector instead.
+type metricsEventRecorder struct {
+	events.EventRecorder
+}
+
+func newMetricsEventRecorder(recorder events.EventRecorder) events.EventRecorder {
+	return &metricsEventRecorder{EventRecorder: recorder}
+}
+
+func (r *metricsEventRecorder) Eventf(regarding runtime.Object, related runtime.Object, eventtype, reason, action, note string, args ...interface{}) {
+	if eventtype == v1.EventTypeWarning && reason != CCapacityLowReason {
+		reservationFailuresTotal.WithLabelValues(objectKind(regarding), reason).Inc()
+	}
+	r.EventRecorder.Eventf(regarding, related, eventtype, reason, action, note, args...)
+}
+
+func objectKind(obj runtime.Object) string {
+	switch obj.(type) {
+	case *v1alpha1.Network:
+		return "Network"
+	case *v1alpha1.Subnet:
+		return "Subnet"
+	case *v1alpha1.IP:
+		return "IP"
+	case *v1alpha1.NetworkCounter:
+		return "NetworkCounter"
+	default:
+		return obj.GetObjectKind().GroupVersionKind().Kind
+	}
+}
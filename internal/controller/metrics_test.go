@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"math"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metrics", func() {
+	ns := SetupTest()
+
+	// gaugeValue returns a value of the gauge collected for the namespace, and whether it was collected.
+	gaugeValue := func(collector prometheus.Collector, name, namespace string, labels map[string]string) (float64, bool) {
+		registry := prometheus.NewPedanticRegistry()
+		Expect(registry.Register(collector)).To(Succeed())
+		families, err := registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+		for _, family := range families {
+			if family.GetName() != name {
+				continue
+			}
+		metrics:
+			for _, metric := range family.GetMetric() {
+				found := map[string]string{}
+				for _, label := range metric.GetLabel() {
+					found[label.GetName()] = label.GetValue()
+				}
+				if found["namespace"] != namespace {
+					continue
+				}
+				for key, value := range labels {
+					if found[key] != value {
+						continue metrics
+					}
+				}
+				return metric.GetGauge().GetValue(), true
+			}
+		}
+		return 0, false
+	}
+
+	// allocations returns a number of finished allocations of the kind observed by the latency histogram.
+	allocations := func(kind string) uint64 {
+		metric := &dto.Metric{}
+		Expect(allocationDurationSeconds.WithLabelValues(kind).(prometheus.Histogram).Write(metric)).To(Succeed())
+		return metric.GetHistogram().GetSampleCount()
+	}
+
+	It("Should count free network IDs", func() {
+		Expect(freeIDs(nil)).To(BeZero())
+		Expect(freeIDs([]v1alpha1.NetworkIDInterval{
+			{Exact: v1alpha1.NetworkIDFromInt64(5)},
+			{Begin: v1alpha1.NetworkIDFromInt64(10), End: v1alpha1.NetworkIDFromInt64(19)},
+		})).To(Equal(float64(11)))
+		Expect(freeIDs(v1alpha1.NewNetworkCounterSpec(v1alpha1.VXLANNetworkType).Vacant)).To(Equal(float64(16777116)))
+		Expect(math.IsInf(freeIDs(v1alpha1.NewNetworkCounterSpec(v1alpha1.MPLSNetworkType).Vacant), 1)).To(BeTrue())
+	})
+
+	It("Should export allocation latency, capacity and utilization of subnets and networks", func(ctx SpecContext) {
+		network := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: ns.Name},
+		}
+		Expect(k8sClient.Create(ctx, network)).To(Succeed())
+		Eventually(Object(network)).Should(HaveField("Status.State", v1alpha1.CFinishedNetworkState))
+
+		subnet := &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: "subnet", Namespace: ns.Name},
+			Spec: v1alpha1.SubnetSpec{
+				CIDR:    v1alpha1.CidrMustParse("10.0.0.0/24"),
+				Network: corev1.LocalObjectReference{Name: network.Name},
+				Regions: []v1alpha1.Region{
+					{Name: "euw", AvailabilityZones: []string{"a"}},
+					{Name: "eun", AvailabilityZones: []string{"a"}},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, subnet)).To(Succeed())
+		Eventually(Object(subnet)).Should(HaveField("Status.State", v1alpha1.FinishedSubnetState))
+
+		ipAllocations := allocations("IP")
+		ip := &v1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{Name: "ip", Namespace: ns.Name},
+			Spec: v1alpha1.IPSpec{
+				Subnet: v1alpha1.SubnetReference{Name: subnet.Name},
+				IP:     v1alpha1.IPMustParse("10.0.0.1"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, ip)).To(Succeed())
+		Eventually(Object(ip)).Should(HaveField("Status.State", v1alpha1.FinishedIPState))
+
+		Expect(allocations("IP")).To(BeNumerically(">", ipAllocations))
+
+		collector := &CapacityCollector{Reader: k8sClient}
+		subnetLabels := map[string]string{"name": "subnet", "network": "network", "family": "IPv4", "region": "eun,euw"}
+		Eventually(func(g Gomega) {
+			value, ok := gaugeValue(collector, "ipam_subnet_capacity_left", ns.Name, subnetLabels)
+			g.Expect(ok).To(BeTrue())
+			g.Expect(value).To(Equal(float64(255)))
+		}).Should(Succeed())
+
+		value, ok := gaugeValue(collector, "ipam_subnet_capacity", ns.Name, subnetLabels)
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal(float64(256)))
+
+		value, ok = gaugeValue(collector, "ipam_subnet_utilization_ratio", ns.Name, subnetLabels)
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal(1.0 / 256))
+
+		value, ok = gaugeValue(collector, "ipam_subnet_vacant_ranges", ns.Name, subnetLabels)
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal(float64(8)))
+
+		value, ok = gaugeValue(collector, "ipam_network_capacity", ns.Name, map[string]string{"name": "network", "family": "IPv4"})
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal(float64(256)))
+	})
+
+	It("Should count reservation failures by event reason", func(ctx SpecContext) {
+		failures := reservationFailuresTotal.WithLabelValues("Subnet", CTopSubnetReservationFailureReason)
+		before := testutil.ToFloat64(failures)
+
+		network := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: ns.Name},
+		}
+		Expect(k8sClient.Create(ctx, network)).To(Succeed())
+		Eventually(Object(network)).Should(HaveField("Status.State", v1alpha1.CFinishedNetworkState))
+
+		for _, name := range []string{"subnet", "overlapping-subnet"} {
+			subnet := &v1alpha1.Subnet{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns.Name},
+				Spec: v1alpha1.SubnetSpec{
+					CIDR:    v1alpha1.CidrMustParse("10.0.0.0/24"),
+					Network: corev1.LocalObjectReference{Name: network.Name},
+				},
+			}
+			Expect(k8sClient.Create(ctx, subnet)).To(Succeed())
+			Eventually(Object(subnet)).Should(HaveField("Status.State", Not(BeEmpty())))
+		}
+
+		Eventually(func() float64 {
+			return testutil.ToFloat64(failures)
+		}).Should(BeNumerically(">", before))
+	})
+})
@@ -5,17 +5,27 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	machinev1alpha1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
 )
@@ -27,8 +37,12 @@ const (
 	CNetworkIDReservationFailureReason = "NetworkIDReservationFailure"
 	CNetworkIDReservationSuccessReason = "NetworkIDReservationSuccess"
 	CNetworkIDReleaseSuccessReason     = "NetworkIDReleaseSuccess"
+	CRangeReservationFailureReason     = "RangeReservationFailure"
+	CRangeReservationSuccessReason     = "RangeReservationSuccess"
+	CRangeReleaseSuccessReason         = "RangeReleaseSuccess"
 
 	CFailedTopLevelSubnetIndexKey = "failedTopLevelSubnet"
+	CTopLevelSubnetIndexKey       = "topLevelSubnet"
 )
 
 // NetworkReconciler reconciles a Network object
@@ -80,6 +94,11 @@ func (r *NetworkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, nil
 	}
 
+	if machinev1alpha1.IsReconcilePaused(network.Annotations) {
+		log.Info("reconciliation is paused", "name", req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
 	if !controllerutil.ContainsFinalizer(network, CNetworkFinalizer) {
 		controllerutil.AddFinalizer(network, CNetworkFinalizer)
 		err = r.Update(ctx, network)
@@ -102,12 +121,29 @@ func (r *NetworkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, nil
 	}
 
+	if network.Status.State == machinev1alpha1.CFinishedNetworkState {
+		if err := r.bookReservedRanges(ctx, network); err != nil {
+			log.Error(err, "unable to book reserved ranges", "name", req.NamespacedName)
+			return ctrl.Result{}, err
+		}
+		if err := r.updateRangeSummary(ctx, network); err != nil {
+			log.Error(err, "unable to update range summary", "name", req.NamespacedName)
+			return ctrl.Result{}, err
+		}
+	}
+
 	if network.Status.State == machinev1alpha1.CFinishedNetworkState ||
 		network.Status.State == machinev1alpha1.CFailedNetworkState {
 		if err := r.requeueFailedSubnets(ctx, log, network); err != nil {
 			log.Error(err, "unable to requeue top level subnets", "name", req.NamespacedName)
 			return ctrl.Result{}, err
 		}
+		if network.Status.State == machinev1alpha1.CFinishedNetworkState {
+			if err := r.updateCapacityLowCondition(ctx, network); err != nil {
+				log.Error(err, "unable to update network capacity condition", "name", req.NamespacedName)
+				return ctrl.Result{}, err
+			}
+		}
 		return ctrl.Result{}, nil
 	}
 
@@ -129,6 +165,7 @@ func (r *NetworkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			log.Error(err, "unable to update network status", "name", req.NamespacedName)
 			return ctrl.Result{}, err
 		}
+		observeAllocation(network)
 		return ctrl.Result{}, nil
 	}
 
@@ -187,6 +224,7 @@ func (r *NetworkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	if err := r.Update(ctx, &counter); err != nil {
+		countParentConflict(&counter, err)
 		log.Error(err, "unable to update counter state", "name", req.NamespacedName, "counter name", counterNamespacedName)
 		return ctrl.Result{}, err
 	}
@@ -199,6 +237,7 @@ func (r *NetworkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		log.Error(err, "unable to update network status", "name", req.NamespacedName)
 		return ctrl.Result{}, err
 	}
+	observeAllocation(network)
 
 	return ctrl.Result{}, nil
 }
@@ -221,11 +260,144 @@ func (r *NetworkReconciler) requeueFailedSubnets(ctx context.Context, log logr.L
 			log.Error(err, "unable to update top level subnet", "name", types.NamespacedName{Namespace: network.Namespace, Name: network.Name}, "subnet", subnet.Name)
 			return err
 		}
+		failedChildRequeuesTotal.WithLabelValues("Subnet").Inc()
 	}
 
 	return nil
 }
 
+// bookReservedRanges books reserved ranges of the network spec into network ranges, and releases ones removed from
+// the spec. Ranges overlapping ones booked by top level subnets are not booked, and retried once the network changes.
+func (r *NetworkReconciler) bookReservedRanges(ctx context.Context, network *machinev1alpha1.Network) error {
+	desired := make(map[string]machinev1alpha1.NetworkReservedRange, len(network.Spec.ReservedRanges))
+	for _, reservedRange := range network.Spec.ReservedRanges {
+		desired[reservedRange.CIDR.String()] = reservedRange
+	}
+
+	changed := false
+	booked := make([]machinev1alpha1.NetworkReservedRange, 0, len(network.Spec.ReservedRanges))
+	for _, reservedRange := range network.Status.ReservedRanges {
+		if _, ok := desired[reservedRange.CIDR.String()]; ok {
+			booked = append(booked, reservedRange)
+			continue
+		}
+		if err := network.Release(&reservedRange.CIDR); err != nil && !network.CanReserve(&reservedRange.CIDR) {
+			return err
+		}
+		changed = true
+		r.EventRecorder.Eventf(network, nil, v1.EventTypeNormal, CRangeReleaseSuccessReason, "RangeRelease", "Reserved range %s released successfully", reservedRange.CIDR.String())
+	}
+
+	for _, reservedRange := range network.Spec.ReservedRanges {
+		i := slices.IndexFunc(booked, func(bookedRange machinev1alpha1.NetworkReservedRange) bool {
+			return bookedRange.CIDR.Equal(&reservedRange.CIDR)
+		})
+		if i >= 0 {
+			if booked[i].Reason != reservedRange.Reason {
+				booked[i].Reason = reservedRange.Reason
+				changed = true
+			}
+			continue
+		}
+		if err := network.Reserve(reservedRange.CIDR.DeepCopy()); err != nil {
+			r.EventRecorder.Eventf(network, nil, v1.EventTypeWarning, CRangeReservationFailureReason, "RangeReservation", "Reserved range %s overlaps booked ranges: %s", reservedRange.CIDR.String(), err.Error())
+			continue
+		}
+		booked = append(booked, reservedRange)
+		changed = true
+		r.EventRecorder.Eventf(network, nil, v1.EventTypeNormal, CRangeReservationSuccessReason, "RangeReservation", "Reserved range %s booked successfully", reservedRange.CIDR.String())
+	}
+
+	if !changed {
+		return nil
+	}
+	if len(booked) == 0 {
+		booked = nil
+	}
+	network.Status.ReservedRanges = booked
+	return r.Status().Update(ctx, network)
+}
+
+// updateRangeSummary reports aggregates of top level subnets and utilization of allowed ranges of the network.
+func (r *NetworkReconciler) updateRangeSummary(ctx context.Context, network *machinev1alpha1.Network) error {
+	ipv4Aggregates, ipv6Aggregates := network.Aggregates()
+	utilization := network.AllowedRangeUtilization()
+	if equality.Semantic.DeepEqual(ipv4Aggregates, network.Status.IPv4Aggregates) &&
+		equality.Semantic.DeepEqual(ipv6Aggregates, network.Status.IPv6Aggregates) &&
+		equality.Semantic.DeepEqual(utilization, network.Status.AllowedRanges) {
+		return nil
+	}
+	network.Status.IPv4Aggregates = ipv4Aggregates
+	network.Status.IPv6Aggregates = ipv6Aggregates
+	network.Status.AllowedRanges = utilization
+	return r.Status().Update(ctx, network)
+}
+
+// updateCapacityLowCondition evaluates utilization of top level subnets in every address family
+// against network thresholds, and emits a warning event if the utilization level has been raised.
+func (r *NetworkReconciler) updateCapacityLowCondition(ctx context.Context, network *machinev1alpha1.Network) error {
+	var condition *metav1.Condition
+	if network.Spec.UtilizationWarning != nil || network.Spec.UtilizationCritical != nil {
+		subnets := &machinev1alpha1.SubnetList{}
+		if err := r.List(ctx, subnets, client.InNamespace(network.Namespace), client.MatchingFields{CTopLevelSubnetIndexKey: network.Name}); err != nil {
+			return err
+		}
+
+		capacity := make(map[machinev1alpha1.SubnetAddressType]*resource.Quantity)
+		capacityLeft := make(map[machinev1alpha1.SubnetAddressType]*resource.Quantity)
+		for _, subnet := range subnets.Items {
+			if subnet.Status.State != machinev1alpha1.FinishedSubnetState {
+				continue
+			}
+			if _, ok := capacity[subnet.Status.Type]; !ok {
+				capacity[subnet.Status.Type] = &resource.Quantity{}
+				capacityLeft[subnet.Status.Type] = &resource.Quantity{}
+			}
+			capacity[subnet.Status.Type].Add(subnet.Status.Capacity)
+			capacityLeft[subnet.Status.Type].Add(subnet.Status.CapacityLeft)
+		}
+
+		// The most utilized address family determines the condition.
+		maxUtilization := int32(-1)
+		for _, family := range []machinev1alpha1.SubnetAddressType{machinev1alpha1.IPv4SubnetType, machinev1alpha1.IPv6SubnetType} {
+			if _, ok := capacity[family]; !ok {
+				continue
+			}
+			utilization := machinev1alpha1.UtilizationPercentage(*capacity[family], *capacityLeft[family])
+			if utilization <= maxUtilization {
+				continue
+			}
+			maxUtilization = utilization
+			condition = machinev1alpha1.UtilizationCondition(utilization, network.Spec.UtilizationWarning, network.Spec.UtilizationCritical, network.Generation)
+			condition.Message = fmt.Sprintf("%s: %s", family, condition.Message)
+		}
+		if condition == nil {
+			condition = machinev1alpha1.UtilizationCondition(0, network.Spec.UtilizationWarning, network.Spec.UtilizationCritical, network.Generation)
+		}
+	}
+
+	changed, raised := setCapacityLowCondition(&network.Status.Conditions, condition)
+	if !changed {
+		return nil
+	}
+	if err := r.Status().Update(ctx, network); err != nil {
+		return err
+	}
+	if raised {
+		r.EventRecorder.Eventf(network, nil, v1.EventTypeWarning, CCapacityLowReason, "CapacityCheck", condition.Message)
+	}
+	return nil
+}
+
+// topLevelSubnetToNetwork enqueues the network of a top level subnet, since its utilization depends on subnet capacity.
+func (r *NetworkReconciler) topLevelSubnetToNetwork(_ context.Context, object client.Object) []reconcile.Request {
+	subnet, ok := object.(*machinev1alpha1.Subnet)
+	if !ok || subnet.Spec.ParentSubnet.Name != "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: subnet.Namespace, Name: subnet.Spec.Network.Name}}}
+}
+
 func (r *NetworkReconciler) finalizeNetwork(ctx context.Context, log logr.Logger, network *machinev1alpha1.Network) error {
 	if network.Spec.Type == "" {
 		return nil
@@ -269,6 +441,7 @@ func (r *NetworkReconciler) finalizeNetwork(ctx context.Context, log logr.Logger
 	}
 
 	if err := r.Update(ctx, &counter); err != nil {
+		countParentConflict(&counter, err)
 		log.Error(err, "unexpected error while updating counter", "counter name", counterNamespacedName)
 		return err
 	}
@@ -317,8 +490,50 @@ func (r *NetworkReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
-	r.EventRecorder = mgr.GetEventRecorder("network-controller")
+	createTopLevelSubnetIndexValue := func(object client.Object) []string {
+		subnet, ok := object.(*machinev1alpha1.Subnet)
+		if !ok || subnet.Spec.ParentSubnet.Name != "" {
+			return nil
+		}
+		return []string{subnet.Spec.Network.Name}
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(), &machinev1alpha1.Subnet{}, CTopLevelSubnetIndexKey, createTopLevelSubnetIndexValue); err != nil {
+		return err
+	}
+
+	r.EventRecorder = newMetricsEventRecorder(mgr.GetEventRecorder("network-controller"))
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&machinev1alpha1.Network{}).
+		Watches(&machinev1alpha1.Subnet{}, handler.EnqueueRequestsFromMapFunc(r.topLevelSubnetToNetwork),
+			builder.WithPredicates(subnetCapacityChanged)).
 		Complete(r)
 }
+
+// subnetCapacityChanged passes deletion of subnets, which releases network capacity, and updates of finished subnets
+// changing their state, reserved CIDR or capacity. Subnets which have not been finished do not book network capacity,
+// so their creation and transitions, e.g. failed subnets requeued by the network, are not passed.
+var subnetCapacityChanged = predicate.Funcs{
+	CreateFunc: func(event.CreateEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldSubnet, ok := e.ObjectOld.(*machinev1alpha1.Subnet)
+		if !ok {
+			return false
+		}
+		newSubnet, ok := e.ObjectNew.(*machinev1alpha1.Subnet)
+		if !ok {
+			return false
+		}
+		if oldSubnet.Status.State != machinev1alpha1.FinishedSubnetState &&
+			newSubnet.Status.State != machinev1alpha1.FinishedSubnetState {
+			return false
+		}
+		return oldSubnet.Status.State != newSubnet.Status.State ||
+			!equality.Semantic.DeepEqual(oldSubnet.Status.Reserved, newSubnet.Status.Reserved) ||
+			!oldSubnet.Status.Capacity.Equal(newSubnet.Status.Capacity) ||
+			!oldSubnet.Status.CapacityLeft.Equal(newSubnet.Status.CapacityLeft)
+	},
+}
@@ -8,11 +8,16 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
 )
@@ -209,4 +214,162 @@ var _ = Describe("Network controller", func() {
 			Expect(counter.Spec.CanReserve(oldNetworkID)).Should(BeTrue())
 		}
 	})
+
+	It("Should book reserved ranges and reject overlapping top level subnets", func(ctx SpecContext) {
+		network := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "reserved-network", Namespace: ns.Name},
+			Spec: v1alpha1.NetworkSpec{
+				ReservedRanges: []v1alpha1.NetworkReservedRange{
+					{CIDR: *v1alpha1.CidrMustParse("10.0.0.0/16"), Reason: "legacy datacenter"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, network)).To(Succeed())
+		Eventually(Object(network)).Should(SatisfyAll(
+			HaveField("Status.ReservedRanges", ConsistOf(HaveField("Reason", "legacy datacenter"))),
+			HaveField("Status.IPv4Ranges", ConsistOf(*v1alpha1.CidrMustParse("10.0.0.0/16"))),
+			HaveField("Status.IPv4Capacity.Value()", BeEquivalentTo(65536)),
+		))
+
+		By("Creating an overlapping top level subnet")
+		subnet := &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: "overlapping", Namespace: ns.Name},
+			Spec: v1alpha1.SubnetSpec{
+				CIDR:    v1alpha1.CidrMustParse("10.0.1.0/24"),
+				Network: corev1.LocalObjectReference{Name: network.Name},
+			},
+		}
+		Expect(k8sClient.Create(ctx, subnet)).To(Succeed())
+		Eventually(Object(subnet)).Should(SatisfyAll(
+			HaveField("Status.State", v1alpha1.FailedSubnetState),
+			HaveField("Status.Message", ContainSubstring("legacy datacenter")),
+		))
+
+		By("Removing the reserved range")
+		Eventually(Update(network, func() {
+			network.Spec.ReservedRanges = nil
+		})).Should(Succeed())
+		Eventually(Object(network)).Should(HaveField("Status.ReservedRanges", BeEmpty()))
+		Eventually(Object(subnet)).Should(HaveField("Status.State", v1alpha1.FinishedSubnetState))
+	})
+
+	It("Should not requeue failed top level subnets on their own transitions", func(ctx SpecContext) {
+		failed := &v1alpha1.Subnet{Status: v1alpha1.SubnetStatus{State: v1alpha1.FailedSubnetState}}
+		processing := &v1alpha1.Subnet{Status: v1alpha1.SubnetStatus{State: v1alpha1.ProcessingSubnetState}}
+		finished := &v1alpha1.Subnet{Status: v1alpha1.SubnetStatus{
+			State:    v1alpha1.FinishedSubnetState,
+			Reserved: v1alpha1.CidrMustParse("10.0.0.0/24"),
+			Capacity: resource.MustParse("256"),
+		}}
+		Expect(subnetCapacityChanged.Create(event.CreateEvent{Object: processing})).To(BeFalse())
+		Expect(subnetCapacityChanged.Update(event.UpdateEvent{ObjectOld: failed, ObjectNew: processing})).To(BeFalse())
+		Expect(subnetCapacityChanged.Update(event.UpdateEvent{ObjectOld: processing, ObjectNew: failed})).To(BeFalse())
+		Expect(subnetCapacityChanged.Update(event.UpdateEvent{ObjectOld: processing, ObjectNew: finished})).To(BeTrue())
+		Expect(subnetCapacityChanged.Delete(event.DeleteEvent{Object: finished})).To(BeTrue())
+
+		By("Keeping a permanently failing top level subnet failed")
+		reservedNetwork := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "failing-network", Namespace: ns.Name},
+			Spec: v1alpha1.NetworkSpec{
+				ReservedRanges: []v1alpha1.NetworkReservedRange{{CIDR: *v1alpha1.CidrMustParse("10.0.0.0/16")}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, reservedNetwork)).To(Succeed())
+		Eventually(Object(reservedNetwork)).Should(HaveField("Status.ReservedRanges", HaveLen(1)))
+
+		subnet := &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: "failing", Namespace: ns.Name},
+			Spec: v1alpha1.SubnetSpec{
+				CIDR:    v1alpha1.CidrMustParse("10.0.1.0/24"),
+				Network: corev1.LocalObjectReference{Name: reservedNetwork.Name},
+			},
+		}
+		Expect(k8sClient.Create(ctx, subnet)).To(Succeed())
+		Eventually(Object(subnet)).Should(HaveField("Status.State", v1alpha1.FailedSubnetState))
+		Consistently(Object(subnet)).Should(HaveField("ResourceVersion", subnet.ResourceVersion))
+	})
+
+	It("Should restrict top level subnets to allowed ranges and report their utilization", func(ctx SpecContext) {
+		network := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "allowed-network", Namespace: ns.Name},
+			Spec: v1alpha1.NetworkSpec{
+				AllowedRanges: []v1alpha1.CIDR{*v1alpha1.CidrMustParse("10.0.0.0/16")},
+			},
+		}
+		Expect(k8sClient.Create(ctx, network)).To(Succeed())
+		Eventually(Object(network)).Should(HaveField("Status.AllowedRanges", ConsistOf(SatisfyAll(
+			HaveField("CIDR", *v1alpha1.CidrMustParse("10.0.0.0/16")),
+			HaveField("Capacity.Value()", BeEquivalentTo(65536)),
+			HaveField("Utilization", BeEquivalentTo(0)),
+		))))
+
+		newSubnet := func(name, cidr string) *v1alpha1.Subnet {
+			return &v1alpha1.Subnet{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns.Name},
+				Spec: v1alpha1.SubnetSpec{
+					CIDR:    v1alpha1.CidrMustParse(cidr),
+					Network: corev1.LocalObjectReference{Name: network.Name},
+				},
+			}
+		}
+
+		By("Creating a top level subnet within the allowed range")
+		allowed := newSubnet("allowed", "10.0.0.0/17")
+		Expect(k8sClient.Create(ctx, allowed)).To(Succeed())
+		Eventually(Object(allowed)).Should(HaveField("Status.State", v1alpha1.FinishedSubnetState))
+		Eventually(Object(network)).Should(HaveField("Status.AllowedRanges", ConsistOf(SatisfyAll(
+			HaveField("CapacityLeft.Value()", BeEquivalentTo(32768)),
+			HaveField("Utilization", BeEquivalentTo(50)),
+		))))
+
+		By("Creating a top level subnet out of the allowed range")
+		outside := newSubnet("outside", "192.168.0.0/24")
+		Expect(k8sClient.Create(ctx, outside)).To(Succeed())
+		Eventually(Object(outside)).Should(SatisfyAll(
+			HaveField("Status.State", v1alpha1.FailedSubnetState),
+			HaveField("Status.Message", ContainSubstring("out of ranges allowed")),
+		))
+
+		By("Creating an IPv6 top level subnet, which family is not restricted")
+		ipv6 := newSubnet("ipv6", "fd00::/64")
+		Expect(k8sClient.Create(ctx, ipv6)).To(Succeed())
+		Eventually(Object(ipv6)).Should(HaveField("Status.State", v1alpha1.FinishedSubnetState))
+	})
+
+	It("Should summarize top level subnets into aggregates", func(ctx SpecContext) {
+		network := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "aggregated-network", Namespace: ns.Name},
+			Spec: v1alpha1.NetworkSpec{
+				ReservedRanges: []v1alpha1.NetworkReservedRange{
+					{CIDR: *v1alpha1.CidrMustParse("10.0.2.0/24"), Reason: "upstream provider"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, network)).To(Succeed())
+		Eventually(Object(network)).Should(HaveField("Status.ReservedRanges", HaveLen(1)))
+
+		for i, cidr := range []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.4.0/24"} {
+			subnet := &v1alpha1.Subnet{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("subnet-%d", i), Namespace: ns.Name},
+				Spec: v1alpha1.SubnetSpec{
+					CIDR:    v1alpha1.CidrMustParse(cidr),
+					Network: corev1.LocalObjectReference{Name: network.Name},
+				},
+			}
+			Expect(k8sClient.Create(ctx, subnet)).To(Succeed())
+			Eventually(Object(subnet)).Should(HaveField("Status.State", v1alpha1.FinishedSubnetState))
+		}
+		Eventually(Object(network)).Should(HaveField("Status.IPv4Aggregates", Equal([]v1alpha1.CIDR{
+			*v1alpha1.CidrMustParse("10.0.0.0/23"),
+			*v1alpha1.CidrMustParse("10.0.4.0/24"),
+		})))
+
+		By("Limiting the number of aggregates")
+		Eventually(Update(network, func() {
+			network.Spec.MaxAggregates = ptr.To[int32](1)
+		})).Should(Succeed())
+		Eventually(Object(network)).Should(HaveField("Status.IPv4Aggregates", Equal([]v1alpha1.CIDR{
+			*v1alpha1.CidrMustParse("10.0.0.0/21"),
+		})))
+	})
 })
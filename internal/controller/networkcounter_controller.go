@@ -47,11 +47,11 @@ func (r *NetworkCounterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, err
 	}
 
-	if nc.GetDeletionTimestamp() != nil {
+	if nc.GetDeletionTimestamp() != nil || v1alpha1.IsReconcilePaused(nc.Annotations) {
 		return ctrl.Result{}, nil
 	}
 
-	netType, err := r.counterNameToType(nc.Name)
+	netType, err := counterNameToType(nc.Name)
 	if err != nil {
 		log.Error(err, "unknown network counter", "name", req.NamespacedName)
 		return ctrl.Result{}, err
@@ -102,13 +102,13 @@ func (r *NetworkCounterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
-	r.EventRecorder = mgr.GetEventRecorder("networkcounter-controller")
+	r.EventRecorder = newMetricsEventRecorder(mgr.GetEventRecorder("networkcounter-controller"))
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.NetworkCounter{}).
 		Complete(r)
 }
 
-func (r *NetworkCounterReconciler) counterNameToType(name string) (v1alpha1.NetworkType, error) {
+func counterNameToType(name string) (v1alpha1.NetworkType, error) {
 	var counterType v1alpha1.NetworkType
 	switch name {
 	case CVXLANCounterName:
@@ -0,0 +1,269 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+const (
+	CNodeCIDRPoolExhaustedReason   = "NodeCIDRPoolExhausted"
+	CNodeCIDRCreationFailureReason = "NodeCIDRCreationFailure"
+	CNodeCIDRFailureReason         = "NodeCIDRFailure"
+	CNodeCIDRAssignedReason        = "NodeCIDRAssigned"
+)
+
+// NodeIPAMReconciler allocates pod CIDRs of Nodes as child Subnets of the pool Subnets.
+// Pool Subnets are labeled with v1alpha1.PoolLabel and may be restricted to a set of Nodes
+// by v1alpha1.NodeSelectorAnnotation. Once Subnets of all enabled families are reserved,
+// their CIDRs are written to the Node spec.podCIDRs, IPv4 first.
+type NodeIPAMReconciler struct {
+	client.Client
+	Log           logr.Logger
+	Scheme        *runtime.Scheme
+	EventRecorder events.EventRecorder
+	// Pool is the value of v1alpha1.PoolLabel of the Subnets pod CIDRs are allocated from
+	Pool string
+	// IPv4PrefixBits is the prefix length of IPv4 pod CIDRs, IPv4 pod CIDRs are not allocated if zero
+	IPv4PrefixBits byte
+	// IPv6PrefixBits is the prefix length of IPv6 pod CIDRs, IPv6 pod CIDRs are not allocated if zero
+	IPv6PrefixBits byte
+}
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;patch
+
+// Reconcile allocates the pod CIDRs of the Node, or releases them once the Node is deleted.
+func (r *NodeIPAMReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("node", req.Name)
+
+	node := &v1.Node{}
+	err := r.Get(ctx, req.NamespacedName, node)
+	if apierrors.IsNotFound(err) {
+		log.Info("Node not found, releasing its pod CIDRs.")
+		return ctrl.Result{}, r.releaseNodeSubnets(ctx, req.Name)
+	}
+	if err != nil {
+		log.Error(err, "unable to get node resource", "name", req.Name)
+		return ctrl.Result{}, err
+	}
+
+	if node.GetDeletionTimestamp() != nil || v1alpha1.IsReconcilePaused(node.Annotations) {
+		return ctrl.Result{}, nil
+	}
+
+	// Pod CIDRs may be set only once, so a Node with pod CIDRs assigned by someone else is left alone.
+	if len(node.Spec.PodCIDRs) > 0 {
+		return ctrl.Result{}, nil
+	}
+
+	subnets, err := r.nodeSubnets(ctx, node.Name)
+	if err != nil {
+		log.Error(err, "unable to list node subnets", "name", req.Name)
+		return ctrl.Result{}, err
+	}
+
+	var podCIDRs []string
+	for _, family := range []v1alpha1.SubnetAddressType{v1alpha1.IPv4SubnetType, v1alpha1.IPv6SubnetType} {
+		prefixBits := r.prefixBits(family)
+		if prefixBits == 0 {
+			continue
+		}
+
+		name := nodeSubnetName(node.Name, family)
+		subnet, ok := subnets[name]
+		if !ok {
+			parent, err := r.nodeParentSubnet(ctx, node, family, prefixBits)
+			if err != nil {
+				log.Error(err, "unable to find parent subnet", "name", req.Name, "family", family)
+				r.EventRecorder.Eventf(node, nil, v1.EventTypeWarning, CNodeCIDRPoolExhaustedReason, "NodeCIDRReservation", err.Error())
+				return ctrl.Result{}, err
+			}
+			subnet = newNodeSubnet(node, parent, name, prefixBits)
+			if err := r.Create(ctx, subnet); err != nil {
+				log.Error(err, "unable to create node subnet", "name", req.Name, "subnet", name)
+				r.EventRecorder.Eventf(node, nil, v1.EventTypeWarning, CNodeCIDRCreationFailureReason, "NodeCIDRReservation", err.Error())
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+
+		switch subnet.Status.State {
+		case v1alpha1.FinishedSubnetState:
+			podCIDRs = append(podCIDRs, subnet.Status.Reserved.String())
+		case v1alpha1.FailedSubnetState:
+			r.EventRecorder.Eventf(node, nil, v1.EventTypeWarning, CNodeCIDRFailureReason, "NodeCIDRReservation",
+				"Subnet %s/%s failed: %s", subnet.Namespace, subnet.Name, subnet.Status.Message)
+		}
+	}
+
+	// Pod CIDRs are assigned at once, when all the enabled families are reserved.
+	enabled := 0
+	for _, bits := range []byte{r.IPv4PrefixBits, r.IPv6PrefixBits} {
+		if bits != 0 {
+			enabled++
+		}
+	}
+	if len(podCIDRs) == 0 || len(podCIDRs) != enabled {
+		return ctrl.Result{}, nil
+	}
+
+	patch := client.MergeFrom(node.DeepCopy())
+	node.Spec.PodCIDR = podCIDRs[0]
+	node.Spec.PodCIDRs = podCIDRs
+	if err := r.Patch(ctx, node, patch); err != nil {
+		log.Error(err, "unable to patch node pod cidrs", "name", req.Name)
+		return ctrl.Result{}, err
+	}
+	r.EventRecorder.Eventf(node, nil, v1.EventTypeNormal, CNodeCIDRAssignedReason, "NodeCIDRReservation",
+		"Pod CIDRs %s assigned", strings.Join(podCIDRs, ","))
+
+	return ctrl.Result{}, nil
+}
+
+// nodeSubnets returns Subnets allocated for the Node in any namespace, mapped by name.
+func (r *NodeIPAMReconciler) nodeSubnets(ctx context.Context, nodeName string) (map[string]*v1alpha1.Subnet, error) {
+	subnetList := &v1alpha1.SubnetList{}
+	if err := r.List(ctx, subnetList, client.MatchingLabels{v1alpha1.NodeLabel: nodeLabelValue(nodeName)}); err != nil {
+		return nil, err
+	}
+	subnets := make(map[string]*v1alpha1.Subnet, len(subnetList.Items))
+	for i := range subnetList.Items {
+		subnets[subnetList.Items[i].Name] = &subnetList.Items[i]
+	}
+	return subnets, nil
+}
+
+// releaseNodeSubnets deletes Subnets allocated for the deleted Node.
+func (r *NodeIPAMReconciler) releaseNodeSubnets(ctx context.Context, nodeName string) error {
+	subnets, err := r.nodeSubnets(ctx, nodeName)
+	if err != nil {
+		return errors.Wrap(err, "unable to list node subnets")
+	}
+	for _, subnet := range subnets {
+		if err := client.IgnoreNotFound(r.Delete(ctx, subnet)); err != nil {
+			return errors.Wrapf(err, "unable to delete node subnet %s/%s", subnet.Namespace, subnet.Name)
+		}
+	}
+	return nil
+}
+
+// nodeParentSubnet picks a pool subnet of the family to allocate the pod CIDR from.
+// Subnets are tried in namespace and name order, the first one selecting the Node and having
+// a vacant range large enough for the prefix is picked.
+func (r *NodeIPAMReconciler) nodeParentSubnet(ctx context.Context, node *v1.Node,
+	family v1alpha1.SubnetAddressType, prefixBits byte) (*v1alpha1.Subnet, error) {
+	subnets := &v1alpha1.SubnetList{}
+	if err := r.List(ctx, subnets, client.MatchingLabels{v1alpha1.PoolLabel: r.Pool}); err != nil {
+		return nil, errors.Wrap(err, "unable to list pool subnets")
+	}
+	slices.SortFunc(subnets.Items, func(a, b v1alpha1.Subnet) int {
+		return strings.Compare(client.ObjectKeyFromObject(&a).String(), client.ObjectKeyFromObject(&b).String())
+	})
+	for i := range subnets.Items {
+		subnet := &subnets.Items[i]
+		if subnet.Status.State != v1alpha1.FinishedSubnetState || subnet.Status.Type != family {
+			continue
+		}
+		if selector, ok := subnet.Annotations[v1alpha1.NodeSelectorAnnotation]; ok {
+			parsed, err := labels.Parse(selector)
+			if err != nil {
+				r.Log.Error(err, "unable to parse node selector", "subnet", client.ObjectKeyFromObject(subnet))
+				continue
+			}
+			if !parsed.Matches(labels.Set(node.Labels)) {
+				continue
+			}
+		}
+		for _, vacant := range subnet.Status.Vacant {
+			if vacant.MaskOnes() <= prefixBits {
+				return subnet, nil
+			}
+		}
+	}
+	return nil, errors.Errorf("no %s subnet of pool %s has a vacant /%d range for the node", family, r.Pool, prefixBits)
+}
+
+func (r *NodeIPAMReconciler) prefixBits(family v1alpha1.SubnetAddressType) byte {
+	if family == v1alpha1.IPv6SubnetType {
+		return r.IPv6PrefixBits
+	}
+	return r.IPv4PrefixBits
+}
+
+// newNodeSubnet builds the pod CIDR Subnet of the Node in the namespace of the parent.
+// Subnet is owned by the Node, so it is also garbage collected with it.
+func newNodeSubnet(node *v1.Node, parent *v1alpha1.Subnet, name string, prefixBits byte) *v1alpha1.Subnet {
+	return &v1alpha1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       parent.Namespace,
+			Labels:          map[string]string{v1alpha1.NodeLabel: nodeLabelValue(node.Name)},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(node, v1.SchemeGroupVersion.WithKind("Node"))},
+		},
+		Spec: v1alpha1.SubnetSpec{
+			PrefixBits:   &prefixBits,
+			ParentSubnet: v1alpha1.SubnetReference{Name: parent.Name},
+			Network:      parent.Spec.Network,
+			Consumer: &v1alpha1.ResourceReference{
+				APIVersion: "v1",
+				Kind:       "Node",
+				Name:       node.Name,
+			},
+		},
+	}
+}
+
+// nodeSubnetName names the Subnet after the Node and the family. Names exceeding the object name length limit are
+// truncated and suffixed with a hash of the Node name, so they stay unique.
+func nodeSubnetName(nodeName string, family v1alpha1.SubnetAddressType) string {
+	name := fmt.Sprintf("node-%s-%s", nodeName, strings.ToLower(string(family)))
+	if len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+	suffix := fmt.Sprintf("-%s-%s", nodeNameHash(nodeName), strings.ToLower(string(family)))
+	return truncateName("node-"+nodeName, validation.DNS1123SubdomainMaxLength-len(suffix)) + suffix
+}
+
+// nodeLabelValue returns the value of v1alpha1.NodeLabel of Subnets of the Node. Node names exceeding the label value
+// length limit are truncated and suffixed with a hash of the Node name.
+func nodeLabelValue(nodeName string) string {
+	if len(nodeName) <= validation.LabelValueMaxLength {
+		return nodeName
+	}
+	suffix := "-" + nodeNameHash(nodeName)
+	return truncateName(nodeName, validation.LabelValueMaxLength-len(suffix)) + suffix
+}
+
+func nodeNameHash(nodeName string) string {
+	sum := sha256.Sum256([]byte(nodeName))
+	return hex.EncodeToString(sum[:])[:8]
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NodeIPAMReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.EventRecorder = newMetricsEventRecorder(mgr.GetEventRecorder("node-ipam-controller"))
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1.Node{}).
+		Owns(&v1alpha1.Subnet{}).
+		Complete(r)
+}
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const testNodeCIDRPool = "node-cidr-test"
+
+var _ = Describe("Node IPAM controller", func() {
+	ns := SetupTest()
+
+	newPoolSubnet := func(ctx SpecContext, name, cidr, selector string) *v1alpha1.Subnet {
+		subnet := &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns.Name,
+				Labels:    map[string]string{v1alpha1.PoolLabel: testNodeCIDRPool},
+			},
+			Spec: v1alpha1.SubnetSpec{
+				CIDR:    v1alpha1.CidrMustParse(cidr),
+				Network: corev1.LocalObjectReference{Name: "network"},
+			},
+		}
+		if selector != "" {
+			subnet.Annotations = map[string]string{v1alpha1.NodeSelectorAnnotation: selector}
+		}
+		Expect(k8sClient.Create(ctx, subnet)).To(Succeed())
+		Eventually(Object(subnet)).Should(HaveField("Status.State", v1alpha1.FinishedSubnetState))
+		DeferCleanup(k8sClient.Delete, subnet)
+		return subnet
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		network := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: ns.Name},
+		}
+		Expect(k8sClient.Create(ctx, network)).To(Succeed())
+		Eventually(Object(network)).Should(HaveField("Status.State", v1alpha1.CFinishedNetworkState))
+	})
+
+	It("Should bound subnet names and labels of nodes with long names", func() {
+		long := strings.Repeat("node.", 50) + "abc"
+		v4, v6 := nodeSubnetName(long, v1alpha1.IPv4SubnetType), nodeSubnetName(long, v1alpha1.IPv6SubnetType)
+		Expect(validation.IsDNS1123Subdomain(v4)).To(BeEmpty())
+		Expect(validation.IsDNS1123Subdomain(v6)).To(BeEmpty())
+		Expect(v4).NotTo(Equal(v6))
+		Expect(validation.IsValidLabelValue(nodeLabelValue(long))).To(BeEmpty())
+		Expect(nodeSubnetName("node", v1alpha1.IPv4SubnetType)).To(Equal("node-node-ipv4"))
+		Expect(nodeLabelValue("node")).To(Equal("node"))
+	})
+
+	It("Should assign dual stack pod CIDRs from pool subnets selecting the node and release them", func(ctx SpecContext) {
+		newPoolSubnet(ctx, "zone-a", "10.0.0.0/16", "topology.kubernetes.io/zone=a")
+		zoneB := newPoolSubnet(ctx, "zone-b", "10.1.0.0/16", "topology.kubernetes.io/zone=b")
+		newPoolSubnet(ctx, "v6", "fd00::/56", "")
+
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "node-",
+				Labels:       map[string]string{"topology.kubernetes.io/zone": "b"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, node)).To(Succeed())
+		Eventually(Object(node)).Should(SatisfyAll(
+			HaveField("Spec.PodCIDRs", Equal([]string{"10.1.0.0/24", "fd00::/64"})),
+			HaveField("Spec.PodCIDR", "10.1.0.0/24"),
+		))
+
+		ipv4 := &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-" + node.Name + "-ipv4", Namespace: ns.Name},
+		}
+		Expect(Object(ipv4)()).To(SatisfyAll(
+			HaveField("Spec.ParentSubnet.Name", zoneB.Name),
+			HaveField("Labels", HaveKeyWithValue(v1alpha1.NodeLabel, node.Name)),
+			HaveField("OwnerReferences", ContainElement(HaveField("UID", node.UID))),
+		))
+
+		Expect(k8sClient.Delete(ctx, node)).To(Succeed())
+		Eventually(Get(ipv4)).Should(Satisfy(apierrors.IsNotFound))
+		Eventually(Object(zoneB)).Should(HaveField("Status.CapacityLeft.Value()", int64(65536)))
+	})
+})
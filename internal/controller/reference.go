@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=ipam.metal.ironcore.dev,resources=ipamreferencegrants,verbs=get;list;watch
+
+// referencePermitted checks whether the resource of the kind from the namespace
+// is permitted to refer the subnet by grants of the subnet namespace.
+// Grants are checked by webhooks on creation, though they may be revoked before the address is reserved.
+func referencePermitted(ctx context.Context, c client.Client, kind, namespace string, ref v1alpha1.SubnetReference) (bool, error) {
+	if !ref.IsCrossNamespace(namespace) {
+		return true, nil
+	}
+	grants := &v1alpha1.IPAMReferenceGrantList{}
+	if err := c.List(ctx, grants, client.InNamespace(ref.Namespace)); err != nil {
+		return false, errors.Wrap(err, "unable to list ipam reference grants")
+	}
+	return v1alpha1.ReferencePermitted(grants.Items, kind, namespace, ref), nil
+}
+
+// referenceNotPermittedError describes a cross namespace reference without a grant.
+func referenceNotPermittedError(namespace string, ref v1alpha1.SubnetReference) error {
+	return errors.Errorf("reference to subnet %s is not permitted by any IPAMReferenceGrant", ref.NamespacedName(namespace))
+}
+
+// subnetIndexValue returns the value used to index resources by the referred subnet.
+func subnetIndexValue(namespace string, ref v1alpha1.SubnetReference) string {
+	return ref.NamespacedName(namespace).String()
+}
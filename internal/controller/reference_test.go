@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cross namespace references", func() {
+	ns := SetupTest()
+
+	var (
+		tenant *corev1.Namespace
+		parent *v1alpha1.Subnet
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		tenant = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{GenerateName: "tenant-"},
+		}
+		Expect(k8sClient.Create(ctx, tenant)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, tenant)
+
+		network := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: ns.Name},
+		}
+		Expect(k8sClient.Create(ctx, network)).To(Succeed())
+		Eventually(Object(network)).Should(HaveField("Status.State", v1alpha1.CFinishedNetworkState))
+
+		parent = &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: ns.Name},
+			Spec: v1alpha1.SubnetSpec{
+				CIDR:    v1alpha1.CidrMustParse("10.0.0.0/24"),
+				Network: corev1.LocalObjectReference{Name: network.Name},
+			},
+		}
+		Expect(k8sClient.Create(ctx, parent)).To(Succeed())
+		Eventually(Object(parent)).Should(HaveField("Status.State", v1alpha1.FinishedSubnetState))
+	})
+
+	It("Should fail to reserve an IP without a reference grant", func(ctx SpecContext) {
+		ip := &v1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{Name: "ip", Namespace: tenant.Name},
+			Spec: v1alpha1.IPSpec{
+				Subnet: v1alpha1.SubnetReference{Name: parent.Name, Namespace: ns.Name},
+			},
+		}
+		Expect(k8sClient.Create(ctx, ip)).To(Succeed())
+		Eventually(Object(ip)).Should(SatisfyAll(
+			HaveField("Status.State", v1alpha1.FailedIPState),
+			HaveField("Status.Message", ContainSubstring("not permitted by any IPAMReferenceGrant")),
+		))
+		Consistently(Object(parent)).Should(HaveField("Status.CapacityLeft.Value()", int64(256)))
+	})
+
+	It("Should reserve and release IPs and Subnets in the parent from another namespace", func(ctx SpecContext) {
+		grant := &v1alpha1.IPAMReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: "grant", Namespace: ns.Name},
+			Spec: v1alpha1.IPAMReferenceGrantSpec{
+				From: []v1alpha1.ReferenceGrantFrom{
+					{Kind: "IP", Namespace: tenant.Name},
+					{Kind: "Subnet", Namespace: tenant.Name},
+				},
+				To: []v1alpha1.ReferenceGrantTo{{Name: parent.Name}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, grant)).To(Succeed())
+
+		ip := &v1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{Name: "ip", Namespace: tenant.Name},
+			Spec: v1alpha1.IPSpec{
+				Subnet: v1alpha1.SubnetReference{Name: parent.Name, Namespace: ns.Name},
+			},
+		}
+		Expect(k8sClient.Create(ctx, ip)).To(Succeed())
+		Eventually(Object(ip)).Should(HaveField("Status.State", v1alpha1.FinishedIPState))
+
+		child := &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: tenant.Name},
+			Spec: v1alpha1.SubnetSpec{
+				PrefixBits:   ptr.To[byte](28),
+				Network:      corev1.LocalObjectReference{Name: "network"},
+				ParentSubnet: v1alpha1.SubnetReference{Name: parent.Name, Namespace: ns.Name},
+			},
+		}
+		Expect(k8sClient.Create(ctx, child)).To(Succeed())
+		Eventually(Object(child)).Should(HaveField("Status.State", v1alpha1.FinishedSubnetState))
+		Eventually(Object(parent)).Should(HaveField("Status.CapacityLeft.Value()", int64(239)))
+
+		Expect(k8sClient.Delete(ctx, ip)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, child)).To(Succeed())
+		Eventually(Object(parent)).Should(HaveField("Status.CapacityLeft.Value()", int64(256)))
+	})
+})
@@ -9,13 +9,19 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
 )
@@ -33,11 +39,20 @@ const (
 	CChildSubnetRegionScopeFailureReason  = "ChildSubnetRegionScopeFailure"
 	CChildSubnetCIDRProposalFailureReason = "ChildSubnetCIDRProposalFailure"
 	CChildSubnetReservationFailureReason  = "ChildSubnetReservationFailure"
+	CChildSubnetReferenceFailureReason    = "ChildSubnetReferenceFailure"
+	CChildSubnetAccessFailureReason       = "ChildSubnetAccessFailure"
 	CChildSubnetReservationSuccessReason  = "ChildSubnetReservationSuccess"
 	CChildSubnetReleaseSuccessReason      = "ChildSubnetReleaseSuccess"
 
+	CSubnetGatewayFailureReason = "SubnetGatewayFailure"
+
+	// CFailedChildSubnetIndexKey and CFailedIPIndexKey index failed resources by
+	// namespaced name of the parent subnet, since it may reside in another namespace
 	CFailedChildSubnetIndexKey = "failedChildSubnet"
 	CFailedIPIndexKey          = "failedIP"
+	// CFinishedChildSubnetIndexKey indexes finished subnets by namespaced name of the parent subnet,
+	// so parent network configuration changes are propagated
+	CFinishedChildSubnetIndexKey = "finishedChildSubnet"
 )
 
 // SubnetReconciler reconciles a Subnet object
@@ -94,6 +109,13 @@ func (r *SubnetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, nil
 	}
 
+	// If reconciliation is paused, e.g. while resources are being moved
+	// between clusters, then status should be left untouched.
+	if v1alpha1.IsReconcilePaused(subnet.Annotations) {
+		log.Info("reconciliation is paused", "name", req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
 	// If finalizer is not set, then resource should be updated with finalizer.
 	if !controllerutil.ContainsFinalizer(subnet, CSubnetFinalizer) {
 		controllerutil.AddFinalizer(subnet, CSubnetFinalizer)
@@ -128,6 +150,16 @@ func (r *SubnetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			log.Error(err, "unable to requeue child ips", "name", req.NamespacedName)
 			return ctrl.Result{}, err
 		}
+		if subnet.Status.State == v1alpha1.FinishedSubnetState {
+			if err := r.updateNetworkConfig(ctx, subnet); err != nil {
+				log.Error(err, "unable to update subnet network config", "name", req.NamespacedName)
+				return ctrl.Result{}, err
+			}
+			if err := r.updateCapacityLowCondition(ctx, subnet); err != nil {
+				log.Error(err, "unable to update subnet capacity condition", "name", req.NamespacedName)
+				return ctrl.Result{}, err
+			}
+		}
 		return ctrl.Result{}, nil
 	}
 
@@ -146,10 +178,31 @@ func (r *SubnetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			return ctrl.Result{}, err
 		}
 
+		if err := checkGateway(subnet, subnet.Spec.CIDR); err != nil {
+			return r.failGateway(ctx, log, req.NamespacedName, subnet, err)
+		}
+
+		if !network.Allows(subnet.Spec.CIDR) {
+			err := errors.Errorf("cidr %s is out of ranges allowed in network %s", subnet.Spec.CIDR.String(), network.Name)
+			log.Error(err, "unable to reserve subnet in network", "name", req.NamespacedName, "network name", networkNamespacedName)
+			subnet.Status.State = v1alpha1.FailedSubnetState
+			subnet.Status.Message = err.Error()
+			if err := r.Status().Update(ctx, subnet); err != nil {
+				log.Error(err, "unable to update subnet status", "name", req.NamespacedName)
+				return ctrl.Result{}, err
+			}
+			r.EventRecorder.Eventf(subnet, nil, v1.EventTypeWarning, CTopSubnetReservationFailureReason, "TopSubnetReservation", subnet.Status.Message)
+			return ctrl.Result{}, nil
+		}
+
 		// If it is not possible to reserve subnet's CIDR in network,
 		// then CIDR (or its part) is already reserved,
 		// and CIDR allocation has failed.
 		if err := network.Reserve(subnet.Spec.CIDR); err != nil {
+			if reservedRange := network.BookedReservedRange(subnet.Spec.CIDR); reservedRange != nil {
+				err = errors.Errorf("cidr %s overlaps range %s reserved in network %s: %s",
+					subnet.Spec.CIDR.String(), reservedRange.CIDR.String(), network.Name, reservedRange.Reason)
+			}
 			log.Error(err, "unable to reserve subnet in network", "name", req.NamespacedName, "network name", networkNamespacedName)
 			subnet.Status.State = v1alpha1.FailedSubnetState
 			subnet.Status.Message = err.Error()
@@ -162,15 +215,18 @@ func (r *SubnetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		}
 
 		if err := r.Status().Update(ctx, network); err != nil {
+			countParentConflict(network, err)
 			log.Error(err, "unable to update network", "name", req.NamespacedName, "network name", networkNamespacedName)
 			return ctrl.Result{}, err
 		}
 
 		subnet.FillStatusFromCidr(subnet.Spec.CIDR)
+		fillNetworkConfig(subnet, nil)
 		if err := r.Status().Update(ctx, subnet); err != nil {
 			log.Error(err, "unable to update subnet status", "name", req.NamespacedName)
 			return ctrl.Result{}, err
 		}
+		observeAllocation(subnet)
 		r.EventRecorder.Eventf(subnet, nil, v1.EventTypeNormal, CTopSubnetReservationSuccessReason, "TopSubnetReservation", "CIDR %s in network %s reserved successfully", subnet.Status.Reserved.String(), network.Name)
 
 		return ctrl.Result{}, nil
@@ -178,9 +234,25 @@ func (r *SubnetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 	// If parent subnet is set, then current subnet's CIDR
 	// should be registered in parent subnet.
-	parentSubnetNamespacedName := types.NamespacedName{
-		Namespace: subnet.Namespace,
-		Name:      subnet.Spec.ParentSubnet.Name,
+	// Parent subnet may reside in another namespace if the reference is granted.
+	parentSubnetNamespacedName := subnet.Spec.ParentSubnet.NamespacedName(subnet.Namespace)
+
+	permitted, err := referencePermitted(ctx, r.Client, "Subnet", subnet.Namespace, subnet.Spec.ParentSubnet)
+	if err != nil {
+		log.Error(err, "unable to check parent subnet reference", "name", req.NamespacedName, "parent name", parentSubnetNamespacedName)
+		return ctrl.Result{}, err
+	}
+	if !permitted {
+		err := referenceNotPermittedError(subnet.Namespace, subnet.Spec.ParentSubnet)
+		log.Error(err, "unable to refer parent subnet", "name", req.NamespacedName, "parent name", parentSubnetNamespacedName)
+		subnet.Status.State = v1alpha1.FailedSubnetState
+		subnet.Status.Message = err.Error()
+		if err := r.Status().Update(ctx, subnet); err != nil {
+			log.Error(err, "unable to update subnet status", "name", req.NamespacedName)
+			return ctrl.Result{}, err
+		}
+		r.EventRecorder.Eventf(subnet, nil, v1.EventTypeWarning, CChildSubnetReferenceFailureReason, "ChildSubnetReference", subnet.Status.Message)
+		return ctrl.Result{}, err
 	}
 
 	parentSubnet := &v1alpha1.Subnet{}
@@ -190,6 +262,18 @@ func (r *SubnetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, err
 	}
 
+	if err := accessNotPermittedError(parentSubnet, subnet.Spec.Consumer, subnet.Spec.CIDR != nil, subnet.CreationTimestamp); err != nil {
+		log.Error(err, "unable to allocate from parent subnet", "name", req.NamespacedName, "parent name", parentSubnetNamespacedName)
+		subnet.Status.State = v1alpha1.FailedSubnetState
+		subnet.Status.Message = err.Error()
+		if err := r.Status().Update(ctx, subnet); err != nil {
+			log.Error(err, "unable to update subnet status", "name", req.NamespacedName)
+			return ctrl.Result{}, err
+		}
+		r.EventRecorder.Eventf(subnet, nil, v1.EventTypeWarning, CChildSubnetAccessFailureReason, "ChildSubnetAccess", subnet.Status.Message)
+		return ctrl.Result{}, nil
+	}
+
 	if err := regionSubset(parentSubnet.Spec.Regions, subnet.Spec.Regions); err != nil {
 		err := errors.Wrap(err, "subnet's region set is not a part of parent region set")
 		log.Error(err, "unable to use provided region set", "name", req.NamespacedName, "parent name", parentSubnetNamespacedName)
@@ -237,6 +321,10 @@ func (r *SubnetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, err
 	}
 
+	if err := checkGateway(subnet, cidrToReserve); err != nil {
+		return r.failGateway(ctx, log, req.NamespacedName, subnet, err)
+	}
+
 	// If it is not possible to reserve subnet's CIDR in parent subnet,
 	// then CIDR (or its part) is already reserved, and CIDR allocation has failed.
 	if err := parentSubnet.Reserve(cidrToReserve); err != nil {
@@ -252,15 +340,18 @@ func (r *SubnetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	}
 
 	if err := r.Status().Update(ctx, parentSubnet); err != nil {
+		countParentConflict(parentSubnet, err)
 		log.Error(err, "unable to update parent subnet status after cidr reservation", "name", req.NamespacedName, "parent name", parentSubnetNamespacedName)
 		return ctrl.Result{}, err
 	}
 
 	subnet.FillStatusFromCidr(cidrToReserve)
+	fillNetworkConfig(subnet, parentSubnet.Status.NetworkConfig)
 	if err := r.Status().Update(ctx, subnet); err != nil {
 		log.Error(err, "unable to update parent subnet status after cidr reservation", "name", req.NamespacedName, "parent name", parentSubnetNamespacedName)
 		return ctrl.Result{}, err
 	}
+	observeAllocation(subnet)
 	r.EventRecorder.Eventf(subnet, nil, v1.EventTypeNormal, CChildSubnetReservationSuccessReason, "ChildSubnetReservation", "CIDR %s in subnet %s reserved successfully", subnet.Status.Reserved.String(), parentSubnet.Name)
 
 	return ctrl.Result{}, nil
@@ -274,14 +365,13 @@ func (r *SubnetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			return nil
 		}
 		state := subnet.Status.State
-		parentSubnet := subnet.Spec.ParentSubnet.Name
-		if parentSubnet == "" {
+		if subnet.Spec.ParentSubnet.Name == "" {
 			return nil
 		}
 		if state != v1alpha1.FailedSubnetState {
 			return nil
 		}
-		return []string{parentSubnet}
+		return []string{subnetIndexValue(subnet.Namespace, subnet.Spec.ParentSubnet)}
 	}
 
 	createFailedIPIndexValue := func(object client.Object) []string {
@@ -290,14 +380,13 @@ func (r *SubnetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			return nil
 		}
 		state := ip.Status.State
-		parentSubnet := ip.Spec.Subnet.Name
-		if parentSubnet == "" {
+		if ip.Spec.Subnet.Name == "" {
 			return nil
 		}
 		if state != v1alpha1.FailedIPState {
 			return nil
 		}
-		return []string{parentSubnet}
+		return []string{subnetIndexValue(ip.Namespace, ip.Spec.Subnet)}
 	}
 
 	if err := mgr.GetFieldIndexer().IndexField(
@@ -310,12 +399,112 @@ func (r *SubnetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
-	r.EventRecorder = mgr.GetEventRecorder("subnet-controller")
+	createFinishedChildSubnetIndexValue := func(object client.Object) []string {
+		subnet, ok := object.(*v1alpha1.Subnet)
+		if !ok || subnet.Spec.ParentSubnet.Name == "" || subnet.Status.State != v1alpha1.FinishedSubnetState {
+			return nil
+		}
+		return []string{subnetIndexValue(subnet.Namespace, subnet.Spec.ParentSubnet)}
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(), &v1alpha1.Subnet{}, CFinishedChildSubnetIndexKey, createFinishedChildSubnetIndexValue); err != nil {
+		return err
+	}
+
+	// Child subnets are requeued once parent network configuration changes, so they inherit it.
+	childSubnets := func(ctx context.Context, object client.Object) []reconcile.Request {
+		subnets := &v1alpha1.SubnetList{}
+		if err := r.List(ctx, subnets, client.MatchingFields{CFinishedChildSubnetIndexKey: client.ObjectKeyFromObject(object).String()}); err != nil {
+			r.Log.Error(err, "unable to list child subnets", "subnet", client.ObjectKeyFromObject(object))
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(subnets.Items))
+		for i := range subnets.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&subnets.Items[i])})
+		}
+		return requests
+	}
+
+	r.EventRecorder = newMetricsEventRecorder(mgr.GetEventRecorder("subnet-controller"))
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.Subnet{}).
+		Watches(&v1alpha1.Subnet{}, handler.EnqueueRequestsFromMapFunc(childSubnets),
+			builder.WithPredicates(networkConfigChanged)).
 		Complete(r)
 }
 
+// networkConfigChanged passes updates of the resolved subnet network configuration.
+var networkConfigChanged = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldSubnet, ok := e.ObjectOld.(*v1alpha1.Subnet)
+		if !ok {
+			return false
+		}
+		newSubnet, ok := e.ObjectNew.(*v1alpha1.Subnet)
+		return ok && !equality.Semantic.DeepEqual(oldSubnet.Status.NetworkConfig, newSubnet.Status.NetworkConfig)
+	},
+}
+
+// checkGateway checks the gateway set in spec belongs to the CIDR to reserve.
+func checkGateway(subnet *v1alpha1.Subnet, cidr *v1alpha1.CIDR) error {
+	if subnet.Spec.Gateway == nil || cidr == nil || cidr.Net.Contains(subnet.Spec.Gateway.Net) {
+		return nil
+	}
+	return errors.Errorf("gateway %s does not belong to cidr %s", subnet.Spec.Gateway.String(), cidr.String())
+}
+
+// failGateway sets the failed state for a subnet with the gateway out of its CIDR.
+func (r *SubnetReconciler) failGateway(ctx context.Context, log logr.Logger, namespacedName types.NamespacedName,
+	subnet *v1alpha1.Subnet, err error) (ctrl.Result, error) {
+	log.Error(err, "unable to use provided gateway", "name", namespacedName)
+	subnet.Status.State = v1alpha1.FailedSubnetState
+	subnet.Status.Message = err.Error()
+	if err := r.Status().Update(ctx, subnet); err != nil {
+		log.Error(err, "unable to update subnet status", "name", namespacedName)
+		return ctrl.Result{}, err
+	}
+	r.EventRecorder.Eventf(subnet, nil, v1.EventTypeWarning, CSubnetGatewayFailureReason, "SubnetGateway", subnet.Status.Message)
+	return ctrl.Result{}, err
+}
+
+// fillNetworkConfig reserves the gateway of the subnet which has just reserved its CIDR,
+// so the gateway is never allocated to IPs, and resolves the network configuration.
+func fillNetworkConfig(subnet *v1alpha1.Subnet, parent *v1alpha1.NetworkConfig) {
+	if subnet.Spec.Gateway != nil {
+		// Gateway belongs to the CIDR, and the vacant range is the whole CIDR yet.
+		_ = subnet.Reserve(subnet.Spec.Gateway.AsCidr())
+	}
+	subnet.Status.NetworkConfig = subnet.ResolveNetworkConfig(parent)
+}
+
+// updateNetworkConfig resolves network configuration of the finished subnet again,
+// since the spec or the parent configuration may have been changed.
+func (r *SubnetReconciler) updateNetworkConfig(ctx context.Context, subnet *v1alpha1.Subnet) error {
+	var parent *v1alpha1.NetworkConfig
+	if subnet.Spec.ParentSubnet.Name != "" {
+		parentSubnet := &v1alpha1.Subnet{}
+		err := r.Get(ctx, subnet.Spec.ParentSubnet.NamespacedName(subnet.Namespace), parentSubnet)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		parent = parentSubnet.Status.NetworkConfig
+	}
+
+	config := subnet.ResolveNetworkConfig(parent)
+	if equality.Semantic.DeepEqual(config, subnet.Status.NetworkConfig) {
+		return nil
+	}
+	subnet.Status.NetworkConfig = config
+	return r.Status().Update(ctx, subnet)
+}
+
 // finalizeSubnet releases subnet CIDR from parent subnet of network.
 func (r *SubnetReconciler) finalizeSubnet(ctx context.Context, log logr.Logger, namespacedName types.NamespacedName, subnet *v1alpha1.Subnet) error {
 	// If subnet has failed to reserve the CIDR
@@ -357,15 +546,13 @@ func (r *SubnetReconciler) finalizeSubnet(ctx context.Context, log logr.Logger,
 		}
 
 		if err := r.Status().Update(ctx, network); err != nil {
+			countParentConflict(network, err)
 			log.Error(err, "unable to update network", "name", namespacedName, "network name", networkNamespacedName)
 			return err
 		}
 		r.EventRecorder.Eventf(subnet, nil, v1.EventTypeNormal, CTopSubnetReleaseSuccessReason, "TopSubnetRelease", "CIDR %s in network %s released successfully", subnet.Status.Reserved.String(), network.Name)
 	} else {
-		parentSubnetNamespacedName := types.NamespacedName{
-			Namespace: subnet.Namespace,
-			Name:      subnet.Spec.ParentSubnet.Name,
-		}
+		parentSubnetNamespacedName := subnet.Spec.ParentSubnet.NamespacedName(subnet.Namespace)
 
 		parentSubnet := &v1alpha1.Subnet{}
 
@@ -388,6 +575,7 @@ func (r *SubnetReconciler) finalizeSubnet(ctx context.Context, log logr.Logger,
 		}
 
 		if err := r.Status().Update(ctx, parentSubnet); err != nil {
+			countParentConflict(parentSubnet, err)
 			log.Error(err, "unable to update parent subnet status after cidr reservation", "name", namespacedName, "parent name", parentSubnetNamespacedName)
 			return err
 		}
@@ -399,11 +587,11 @@ func (r *SubnetReconciler) finalizeSubnet(ctx context.Context, log logr.Logger,
 
 func (r *SubnetReconciler) requeueFailedSubnets(ctx context.Context, log logr.Logger, subnet *v1alpha1.Subnet) error {
 	matchingFields := client.MatchingFields{
-		CFailedChildSubnetIndexKey: subnet.Name,
+		CFailedChildSubnetIndexKey: client.ObjectKeyFromObject(subnet).String(),
 	}
 
 	subnets := &v1alpha1.SubnetList{}
-	if err := r.List(context.Background(), subnets, client.InNamespace(subnet.Namespace), matchingFields); err != nil {
+	if err := r.List(context.Background(), subnets, matchingFields); err != nil {
 		log.Error(err, "unable to get connected child subnets", "name", types.NamespacedName{Namespace: subnet.Namespace, Name: subnet.Name})
 		return err
 	}
@@ -415,6 +603,7 @@ func (r *SubnetReconciler) requeueFailedSubnets(ctx context.Context, log logr.Lo
 			log.Error(err, "unable to update child subnet", "name", types.NamespacedName{Namespace: subnet.Namespace, Name: subnet.Name}, "subnet", subnet.Name)
 			return err
 		}
+		failedChildRequeuesTotal.WithLabelValues("Subnet").Inc()
 	}
 
 	return nil
@@ -422,11 +611,11 @@ func (r *SubnetReconciler) requeueFailedSubnets(ctx context.Context, log logr.Lo
 
 func (r *SubnetReconciler) requeueFailedIPs(ctx context.Context, log logr.Logger, subnet *v1alpha1.Subnet) error {
 	matchingFields := client.MatchingFields{
-		CFailedIPIndexKey: subnet.Name,
+		CFailedIPIndexKey: client.ObjectKeyFromObject(subnet).String(),
 	}
 
 	ips := &v1alpha1.IPList{}
-	if err := r.List(context.Background(), ips, client.InNamespace(subnet.Namespace), matchingFields); err != nil {
+	if err := r.List(context.Background(), ips, matchingFields); err != nil {
 		log.Error(err, "unable to get connected ips", "name", types.NamespacedName{Namespace: subnet.Namespace, Name: subnet.Name})
 		return err
 	}
@@ -438,11 +627,30 @@ func (r *SubnetReconciler) requeueFailedIPs(ctx context.Context, log logr.Logger
 			log.Error(err, "unable to update child ips", "name", types.NamespacedName{Namespace: subnet.Namespace, Name: subnet.Name}, "subnet", subnet.Name)
 			return err
 		}
+		failedChildRequeuesTotal.WithLabelValues("IP").Inc()
 	}
 
 	return nil
 }
 
+// updateCapacityLowCondition evaluates subnet utilization against its thresholds,
+// and emits a warning event if the utilization level has been raised.
+func (r *SubnetReconciler) updateCapacityLowCondition(ctx context.Context, subnet *v1alpha1.Subnet) error {
+	utilization := v1alpha1.UtilizationPercentage(subnet.Status.Capacity, subnet.Status.CapacityLeft)
+	condition := v1alpha1.UtilizationCondition(utilization, subnet.Spec.UtilizationWarning, subnet.Spec.UtilizationCritical, subnet.Generation)
+	changed, raised := setCapacityLowCondition(&subnet.Status.Conditions, condition)
+	if !changed {
+		return nil
+	}
+	if err := r.Status().Update(ctx, subnet); err != nil {
+		return err
+	}
+	if raised {
+		r.EventRecorder.Eventf(subnet, nil, v1.EventTypeWarning, CCapacityLowReason, "CapacityCheck", condition.Message)
+	}
+	return nil
+}
+
 func regionSubset(set []v1alpha1.Region, subset []v1alpha1.Region) error {
 	nameSet := make([]string, len(set))
 	for i := range set {
@@ -316,7 +316,7 @@ var _ = Describe("Subnet controller", func() {
 				Network: corev1.LocalObjectReference{
 					Name: NetworkName,
 				},
-				ParentSubnet: corev1.LocalObjectReference{
+				ParentSubnet: v1alpha1.SubnetReference{
 					Name: ParentSubnetName,
 				},
 				Regions: []v1alpha1.Region{
@@ -510,7 +510,7 @@ var _ = Describe("Subnet controller", func() {
 				Network: corev1.LocalObjectReference{
 					Name: NetworkName,
 				},
-				ParentSubnet: corev1.LocalObjectReference{
+				ParentSubnet: v1alpha1.SubnetReference{
 					Name: ParentSubnetName,
 				},
 				Regions: []v1alpha1.Region{
@@ -631,7 +631,7 @@ var _ = Describe("Subnet controller", func() {
 				Network: corev1.LocalObjectReference{
 					Name: NetworkName,
 				},
-				ParentSubnet: corev1.LocalObjectReference{
+				ParentSubnet: v1alpha1.SubnetReference{
 					Name: ParentSubnetName,
 				},
 				Regions: []v1alpha1.Region{
@@ -793,7 +793,7 @@ var _ = Describe("Subnet controller", func() {
 				Network: corev1.LocalObjectReference{
 					Name: NetworkName,
 				},
-				ParentSubnet: corev1.LocalObjectReference{
+				ParentSubnet: v1alpha1.SubnetReference{
 					Name: ParentSubnetName,
 				},
 				Regions: []v1alpha1.Region{
@@ -832,7 +832,7 @@ var _ = Describe("Subnet controller", func() {
 				Network: corev1.LocalObjectReference{
 					Name: NetworkName,
 				},
-				ParentSubnet: corev1.LocalObjectReference{
+				ParentSubnet: v1alpha1.SubnetReference{
 					Name: ParentSubnetName,
 				},
 				Regions: []v1alpha1.Region{
@@ -927,7 +927,7 @@ var _ = Describe("Subnet controller", func() {
 				Network: corev1.LocalObjectReference{
 					Name: NetworkName,
 				},
-				ParentSubnet: corev1.LocalObjectReference{
+				ParentSubnet: v1alpha1.SubnetReference{
 					Name: ParentSubnetName,
 				},
 				Regions: []v1alpha1.Region{
@@ -965,7 +965,7 @@ var _ = Describe("Subnet controller", func() {
 				Network: corev1.LocalObjectReference{
 					Name: NetworkName,
 				},
-				ParentSubnet: corev1.LocalObjectReference{
+				ParentSubnet: v1alpha1.SubnetReference{
 					Name: ParentSubnetName,
 				},
 				Regions: []v1alpha1.Region{
@@ -991,4 +991,77 @@ var _ = Describe("Subnet controller", func() {
 			return true
 		}).Should(BeTrue())
 	})
+
+	It("Should resolve network configuration of subnets and IPs", func(ctx SpecContext) {
+		By("Network is installed")
+		network := &v1alpha1.Network{
+			ObjectMeta: v1.ObjectMeta{Name: NetworkName, Namespace: ns.Name},
+		}
+		Expect(k8sClient.Create(ctx, network)).To(Succeed())
+		Eventually(Object(network)).Should(HaveField("Status.State", v1alpha1.CFinishedNetworkState))
+
+		By("Parent Subnet with gateway and DNS servers is installed")
+		mtu := int32(9000)
+		parentSubnet := &v1alpha1.Subnet{
+			ObjectMeta: v1.ObjectMeta{Name: ParentSubnetName, Namespace: ns.Name},
+			Spec: v1alpha1.SubnetSpec{
+				CIDR:       v1alpha1.CidrMustParse("10.0.0.0/16"),
+				Network:    corev1.LocalObjectReference{Name: NetworkName},
+				Gateway:    v1alpha1.IPMustParse("10.0.0.1"),
+				DNSServers: []v1alpha1.IPAddr{*v1alpha1.IPMustParse("10.0.0.53")},
+				MTU:        &mtu,
+			},
+		}
+		Expect(k8sClient.Create(ctx, parentSubnet)).To(Succeed())
+		Eventually(Object(parentSubnet)).Should(SatisfyAll(
+			HaveField("Status.State", v1alpha1.FinishedSubnetState),
+			HaveField("Status.CapacityLeft.Value()", int64(65535)),
+			HaveField("Status.NetworkConfig.PrefixLength", byte(16)),
+			HaveField("Status.NetworkConfig.Gateway", v1alpha1.IPMustParse("10.0.0.1")),
+		))
+
+		By("IP in the parent Subnet does not get the gateway address")
+		ip := &v1alpha1.IP{
+			ObjectMeta: v1.ObjectMeta{Name: "test-ip", Namespace: ns.Name},
+			Spec: v1alpha1.IPSpec{
+				Subnet: v1alpha1.SubnetReference{Name: ParentSubnetName},
+			},
+		}
+		Expect(k8sClient.Create(ctx, ip)).To(Succeed())
+		Eventually(Object(ip)).Should(SatisfyAll(
+			HaveField("Status.State", v1alpha1.FinishedIPState),
+			HaveField("Status.Reserved", Not(Equal(v1alpha1.IPMustParse("10.0.0.1")))),
+			HaveField("Status.NetworkConfig.PrefixLength", byte(16)),
+			HaveField("Status.NetworkConfig.Gateway", v1alpha1.IPMustParse("10.0.0.1")),
+		))
+
+		By("Child Subnet inherits the configuration of the parent")
+		childSubnet := &v1alpha1.Subnet{
+			ObjectMeta: v1.ObjectMeta{Name: SubnetName, Namespace: ns.Name},
+			Spec: v1alpha1.SubnetSpec{
+				CIDR:          v1alpha1.CidrMustParse("10.0.5.0/24"),
+				Network:       corev1.LocalObjectReference{Name: NetworkName},
+				ParentSubnet:  v1alpha1.SubnetReference{Name: ParentSubnetName},
+				SearchDomains: []string{"example.com"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, childSubnet)).To(Succeed())
+		Eventually(Object(childSubnet)).Should(SatisfyAll(
+			HaveField("Status.State", v1alpha1.FinishedSubnetState),
+			HaveField("Status.NetworkConfig.PrefixLength", byte(16)),
+			HaveField("Status.NetworkConfig.Gateway", v1alpha1.IPMustParse("10.0.0.1")),
+			HaveField("Status.NetworkConfig.DNSServers", ConsistOf(*v1alpha1.IPMustParse("10.0.0.53"))),
+			HaveField("Status.NetworkConfig.SearchDomains", ConsistOf("example.com")),
+			HaveField("Status.NetworkConfig.MTU", Equal(&mtu)),
+		))
+
+		By("Change of the parent configuration is propagated to children and IPs")
+		Eventually(Update(parentSubnet, func() {
+			parentSubnet.Spec.DNSServers = []v1alpha1.IPAddr{*v1alpha1.IPMustParse("10.0.0.54")}
+		})).Should(Succeed())
+		Eventually(Object(childSubnet)).Should(
+			HaveField("Status.NetworkConfig.DNSServers", ConsistOf(*v1alpha1.IPMustParse("10.0.0.54"))))
+		Eventually(Object(ip)).Should(
+			HaveField("Status.NetworkConfig.DNSServers", ConsistOf(*v1alpha1.IPMustParse("10.0.0.54"))))
+	})
 })
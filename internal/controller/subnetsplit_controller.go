@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+const (
+	CSubnetSplitFailureReason       = "SubnetSplitFailure"
+	CSubnetSplitChildCreatedReason  = "SubnetSplitChildCreated"
+	CSubnetSplitChildReleasedReason = "SubnetSplitChildReleased"
+)
+
+// SubnetSplitReconciler carves Subnets with spec.split into equally sized child Subnets.
+// Children are labeled with v1alpha1.SplitParentLabel and v1alpha1.SplitIndexLabel and owned by the split Subnet.
+// They are created one by one, each once the previous ones are reserved, so the Subnet controller proposes
+// their CIDRs in address order of the vacant space of the split Subnet.
+type SubnetSplitReconciler struct {
+	client.Client
+	Log           logr.Logger
+	Scheme        *runtime.Scheme
+	EventRecorder events.EventRecorder
+}
+
+// Reconcile creates missing children of the Subnet split, and deletes children not matching the split.
+func (r *SubnetSplitReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("subnet", req.NamespacedName)
+
+	subnet := &v1alpha1.Subnet{}
+	err := r.Get(ctx, req.NamespacedName, subnet)
+	if apierrors.IsNotFound(err) {
+		log.Info("Subnet not found, it might have been deleted.")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if err != nil {
+		log.Error(err, "unable to get subnet resource", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	if subnet.GetDeletionTimestamp() != nil || v1alpha1.IsReconcilePaused(subnet.Annotations) {
+		return ctrl.Result{}, nil
+	}
+
+	split := subnet.Spec.Split
+	var prefixBits byte
+	var count int
+	if split != nil {
+		if subnet.Status.State != v1alpha1.FinishedSubnetState || subnet.Status.Reserved == nil {
+			return ctrl.Result{}, nil
+		}
+		prefixBits, count, err = split.Children(subnet.Status.Reserved)
+		if err != nil {
+			r.EventRecorder.Eventf(subnet, nil, v1.EventTypeWarning, CSubnetSplitFailureReason, "SubnetSplit", err.Error())
+			return ctrl.Result{}, nil
+		}
+	}
+
+	childList := &v1alpha1.SubnetList{}
+	if err := r.List(ctx, childList, client.InNamespace(subnet.Namespace),
+		client.MatchingLabels{v1alpha1.SplitParentLabel: subnet.Name}); err != nil {
+		log.Error(err, "unable to list split children", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	// Children not matching the split are released first, so the space they occupy is vacant for new children.
+	children := make([]*v1alpha1.Subnet, count)
+	releasing := false
+	for i := range childList.Items {
+		child := &childList.Items[i]
+		if !metav1.IsControlledBy(child, subnet) {
+			continue
+		}
+		index, err := strconv.Atoi(child.Labels[v1alpha1.SplitIndexLabel])
+		if err == nil && index >= 0 && index < count && children[index] == nil &&
+			child.Name == split.ChildName(subnet.Name, index) &&
+			child.Spec.PrefixBits != nil && *child.Spec.PrefixBits == prefixBits {
+			children[index] = child
+			continue
+		}
+
+		releasing = true
+		if child.GetDeletionTimestamp() != nil {
+			continue
+		}
+		if err := r.Delete(ctx, child); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "unable to delete split child", "name", req.NamespacedName, "child", child.Name)
+			r.EventRecorder.Eventf(subnet, nil, v1.EventTypeWarning, CSubnetSplitFailureReason, "SubnetSplit",
+				"unable to release child subnet %s: %s", child.Name, err.Error())
+			return ctrl.Result{}, err
+		}
+		r.EventRecorder.Eventf(subnet, nil, v1.EventTypeNormal, CSubnetSplitChildReleasedReason, "SubnetSplit",
+			"Child subnet %s released", child.Name)
+	}
+	if releasing {
+		return ctrl.Result{}, nil
+	}
+
+	for index, child := range children {
+		if child == nil {
+			child = newSplitChild(subnet, index, prefixBits)
+			if err := controllerutil.SetControllerReference(subnet, child, r.Scheme); err != nil {
+				log.Error(err, "unable to set owner reference", "name", req.NamespacedName)
+				return ctrl.Result{}, err
+			}
+			if err := r.Create(ctx, child); err != nil {
+				log.Error(err, "unable to create split child", "name", req.NamespacedName, "child", child.Name)
+				r.EventRecorder.Eventf(subnet, nil, v1.EventTypeWarning, CSubnetSplitFailureReason, "SubnetSplit",
+					"unable to create child subnet %s: %s", child.Name, err.Error())
+				return ctrl.Result{}, err
+			}
+			r.EventRecorder.Eventf(subnet, nil, v1.EventTypeNormal, CSubnetSplitChildCreatedReason, "SubnetSplit",
+				"Child subnet %s created", child.Name)
+			return ctrl.Result{}, nil
+		}
+
+		switch child.Status.State {
+		case v1alpha1.FinishedSubnetState:
+			continue
+		case v1alpha1.FailedSubnetState:
+			r.EventRecorder.Eventf(subnet, nil, v1.EventTypeWarning, CSubnetSplitFailureReason, "SubnetSplit",
+				"Child subnet %s failed: %s", child.Name, child.Status.Message)
+		}
+		// The next child is created once the previous one is reserved, so children are allocated in address order.
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// newSplitChild builds the child Subnet of the split with the index.
+func newSplitChild(subnet *v1alpha1.Subnet, index int, prefixBits byte) *v1alpha1.Subnet {
+	return &v1alpha1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      subnet.Spec.Split.ChildName(subnet.Name, index),
+			Namespace: subnet.Namespace,
+			Labels: map[string]string{
+				v1alpha1.SplitParentLabel: subnet.Name,
+				v1alpha1.SplitIndexLabel:  strconv.Itoa(index),
+			},
+		},
+		Spec: v1alpha1.SubnetSpec{
+			PrefixBits:   &prefixBits,
+			ParentSubnet: v1alpha1.SubnetReference{Name: subnet.Name},
+			Network:      subnet.Spec.Network,
+			Regions:      subnet.Spec.Regions,
+		},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SubnetSplitReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.EventRecorder = newMetricsEventRecorder(mgr.GetEventRecorder("subnet-split-controller"))
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("subnet-split").
+		For(&v1alpha1.Subnet{}).
+		Owns(&v1alpha1.Subnet{}).
+		Complete(r)
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Subnet split controller", func() {
+	ns := SetupTest()
+
+	childSubnet := func(name string) *v1alpha1.Subnet {
+		return &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns.Name},
+		}
+	}
+
+	It("Should carve the subnet into children in address order and replace them once the split changes", func(ctx SpecContext) {
+		network := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: ns.Name},
+		}
+		Expect(k8sClient.Create(ctx, network)).To(Succeed())
+		Eventually(Object(network)).Should(HaveField("Status.State", v1alpha1.CFinishedNetworkState))
+
+		subnet := &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: ns.Name},
+			Spec: v1alpha1.SubnetSpec{
+				CIDR:    v1alpha1.CidrMustParse("10.0.0.0/22"),
+				Network: corev1.LocalObjectReference{Name: network.Name},
+				Split:   &v1alpha1.SubnetSplit{PrefixBits: ptr.To[byte](24)},
+			},
+		}
+		Expect(k8sClient.Create(ctx, subnet)).To(Succeed())
+
+		for index, cidr := range []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24", "10.0.3.0/24"} {
+			child := childSubnet("pod-" + strconv.Itoa(index))
+			Eventually(Object(child)).Should(SatisfyAll(
+				HaveField("Status.State", v1alpha1.FinishedSubnetState),
+				HaveField("Status.Reserved.String()", cidr),
+				HaveField("Labels", HaveKeyWithValue(v1alpha1.SplitIndexLabel, strconv.Itoa(index))),
+				HaveField("OwnerReferences", ContainElement(HaveField("Name", subnet.Name))),
+			))
+		}
+
+		By("Changing the split")
+		Eventually(Update(subnet, func() {
+			subnet.Spec.Split = &v1alpha1.SubnetSplit{Count: ptr.To[int32](2), NameTemplate: "{subnet.name}-half-{index}"}
+		})).Should(Succeed())
+
+		for _, name := range []string{"pod-0", "pod-1", "pod-2", "pod-3"} {
+			Eventually(Get(childSubnet(name))).Should(Satisfy(apierrors.IsNotFound))
+		}
+		Eventually(Object(childSubnet("pod-half-0"))).Should(HaveField("Status.Reserved.String()", "10.0.0.0/23"))
+		Eventually(Object(childSubnet("pod-half-1"))).Should(HaveField("Status.Reserved.String()", "10.0.2.0/23"))
+
+		By("Removing the split")
+		Eventually(Update(subnet, func() {
+			subnet.Spec.Split = nil
+		})).Should(Succeed())
+
+		for _, name := range []string{"pod-half-0", "pod-half-1"} {
+			Eventually(Get(childSubnet(name))).Should(Satisfy(apierrors.IsNotFound))
+		}
+	})
+})
@@ -131,6 +131,18 @@ func SetupTest() *corev1.Namespace {
 			Log:    ctrl.Log.WithName("controllers").WithName("Subnet"),
 		}).SetupWithManager(k8sManager)).To(Succeed())
 
+		Expect((&SubnetSplitReconciler{
+			Scheme: k8sManager.GetScheme(),
+			Client: k8sManager.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("SubnetSplit"),
+		}).SetupWithManager(k8sManager)).To(Succeed())
+
+		Expect((&AddressPlanReconciler{
+			Scheme: k8sManager.GetScheme(),
+			Client: k8sManager.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("AddressPlan"),
+		}).SetupWithManager(k8sManager)).To(Succeed())
+
 		Expect((&NetworkReconciler{
 			Scheme: k8sManager.GetScheme(),
 			Client: k8sManager.GetClient(),
@@ -143,6 +155,48 @@ func SetupTest() *corev1.Namespace {
 			Log:    ctrl.Log.WithName("controllers").WithName("IP"),
 		}).SetupWithManager(k8sManager)).To(Succeed())
 
+		Expect((&IPAMQuotaReconciler{
+			Scheme: k8sManager.GetScheme(),
+			Client: k8sManager.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("IPAMQuota"),
+		}).SetupWithManager(k8sManager)).To(Succeed())
+
+		Expect((&ConsumerIPReconciler{
+			Scheme: k8sManager.GetScheme(),
+			Client: k8sManager.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("ConsumerIP"),
+			GVK:    corev1.SchemeGroupVersion.WithKind("Pod"),
+		}).SetupWithManager(k8sManager)).To(Succeed())
+
+		Expect((&LoadBalancerReconciler{
+			Scheme:            k8sManager.GetScheme(),
+			Client:            k8sManager.GetClient(),
+			Log:               ctrl.Log.WithName("controllers").WithName("LoadBalancer"),
+			LoadBalancerClass: testLoadBalancerClass,
+		}).SetupWithManager(k8sManager)).To(Succeed())
+
+		Expect((&DHCPExportReconciler{
+			Scheme: k8sManager.GetScheme(),
+			Client: k8sManager.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("DHCPExport"),
+		}).SetupWithManager(k8sManager)).To(Succeed())
+
+		Expect((&DNSRecordReconciler{
+			Scheme: k8sManager.GetScheme(),
+			Client: k8sManager.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("DNSRecord"),
+			Output: CZoneFileOutput,
+		}).SetupWithManager(k8sManager)).To(Succeed())
+
+		Expect((&NodeIPAMReconciler{
+			Scheme:         k8sManager.GetScheme(),
+			Client:         k8sManager.GetClient(),
+			Log:            ctrl.Log.WithName("controllers").WithName("NodeIPAM"),
+			Pool:           testNodeCIDRPool,
+			IPv4PrefixBits: 24,
+			IPv6PrefixBits: 64,
+		}).SetupWithManager(k8sManager)).To(Succeed())
+
 		go func() {
 			defer GinkgoRecover()
 			Expect(k8sManager.Start(mgrCtx)).To(Succeed(), "failed to start manager")
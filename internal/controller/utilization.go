@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+const (
+	CCapacityLowReason = "CapacityLow"
+)
+
+// utilizationSeverity orders CapacityLow condition reasons, so only raised levels are reported with events.
+var utilizationSeverity = map[string]int{
+	v1alpha1.UtilizationNormalReason:   0,
+	v1alpha1.UtilizationWarningReason:  1,
+	v1alpha1.UtilizationCriticalReason: 2,
+}
+
+// setCapacityLowCondition sets or removes the CapacityLow condition.
+// It returns whether conditions have been changed, and whether the utilization level has been raised.
+func setCapacityLowCondition(conditions *[]metav1.Condition, condition *metav1.Condition) (changed bool, raised bool) {
+	current := meta.FindStatusCondition(*conditions, v1alpha1.CapacityLowCondition)
+	if condition == nil {
+		return meta.RemoveStatusCondition(conditions, v1alpha1.CapacityLowCondition), false
+	}
+
+	severity := 0
+	if current != nil {
+		severity = utilizationSeverity[current.Reason]
+	}
+	raised = utilizationSeverity[condition.Reason] > severity
+	return meta.SetStatusCondition(conditions, *condition), raised
+}
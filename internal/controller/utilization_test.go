@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Utilization thresholds", func() {
+	ns := SetupTest()
+
+	capacityLowEvents := func(ctx context.Context, g Gomega, name string) []string {
+		events := &eventsv1.EventList{}
+		g.Expect(k8sClient.List(ctx, events, client.InNamespace(ns.Name))).To(Succeed())
+		notes := make([]string, 0)
+		for _, event := range events.Items {
+			if event.Reason == CCapacityLowReason && event.Regarding.Name == name {
+				notes = append(notes, event.Note)
+			}
+		}
+		return notes
+	}
+
+	It("Should evaluate utilization against thresholds", func() {
+		Expect(v1alpha1.UtilizationCondition(90, nil, nil, 1)).To(BeNil())
+
+		condition := v1alpha1.UtilizationCondition(79, ptr.To[int32](80), ptr.To[int32](95), 1)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(v1alpha1.UtilizationNormalReason))
+
+		condition = v1alpha1.UtilizationCondition(80, ptr.To[int32](80), ptr.To[int32](95), 1)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(v1alpha1.UtilizationWarningReason))
+
+		condition = v1alpha1.UtilizationCondition(100, ptr.To[int32](80), ptr.To[int32](95), 1)
+		Expect(condition.Reason).To(Equal(v1alpha1.UtilizationCriticalReason))
+		Expect(condition.Message).To(Equal("100% of capacity is reserved, critical threshold is 95%"))
+
+		conditions := make([]metav1.Condition, 0)
+		changed, raised := setCapacityLowCondition(&conditions, v1alpha1.UtilizationCondition(85, ptr.To[int32](80), nil, 1))
+		Expect(changed).To(BeTrue())
+		Expect(raised).To(BeTrue())
+		changed, raised = setCapacityLowCondition(&conditions, v1alpha1.UtilizationCondition(85, ptr.To[int32](80), nil, 1))
+		Expect(changed).To(BeFalse())
+		Expect(raised).To(BeFalse())
+		changed, raised = setCapacityLowCondition(&conditions, nil)
+		Expect(changed).To(BeTrue())
+		Expect(raised).To(BeFalse())
+		Expect(conditions).To(BeEmpty())
+	})
+
+	It("Should set CapacityLow condition on Subnet and Network", func(ctx SpecContext) {
+		network := &v1alpha1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: ns.Name},
+			Spec: v1alpha1.NetworkSpec{
+				UtilizationWarning: ptr.To[int32](50),
+			},
+		}
+		Expect(k8sClient.Create(ctx, network)).To(Succeed())
+		Eventually(Object(network)).Should(HaveField("Status.State", v1alpha1.CFinishedNetworkState))
+
+		subnet := &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: "subnet", Namespace: ns.Name},
+			Spec: v1alpha1.SubnetSpec{
+				CIDR:                v1alpha1.CidrMustParse("10.0.0.0/24"),
+				Network:             corev1.LocalObjectReference{Name: network.Name},
+				UtilizationWarning:  ptr.To[int32](50),
+				UtilizationCritical: ptr.To[int32](75),
+			},
+		}
+		Expect(k8sClient.Create(ctx, subnet)).To(Succeed())
+		Eventually(Object(subnet)).Should(HaveField("Status.Conditions", ContainElement(SatisfyAll(
+			HaveField("Type", v1alpha1.CapacityLowCondition),
+			HaveField("Status", metav1.ConditionFalse),
+		))))
+
+		child := &v1alpha1.Subnet{
+			ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: ns.Name},
+			Spec: v1alpha1.SubnetSpec{
+				CIDR:         v1alpha1.CidrMustParse("10.0.0.0/25"),
+				ParentSubnet: v1alpha1.SubnetReference{Name: subnet.Name},
+				Network:      corev1.LocalObjectReference{Name: network.Name},
+			},
+		}
+		Expect(k8sClient.Create(ctx, child)).To(Succeed())
+
+		Eventually(Object(subnet)).Should(HaveField("Status.Conditions", ContainElement(SatisfyAll(
+			HaveField("Type", v1alpha1.CapacityLowCondition),
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", v1alpha1.UtilizationWarningReason),
+		))))
+		Eventually(Object(network)).Should(HaveField("Status.Conditions", ContainElement(SatisfyAll(
+			HaveField("Type", v1alpha1.CapacityLowCondition),
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Message", "IPv4: 50% of capacity is reserved, warning threshold is 50%"),
+		))))
+		Eventually(func(g Gomega) []string {
+			return capacityLowEvents(ctx, g, subnet.Name)
+		}).Should(ConsistOf("50% of capacity is reserved, warning threshold is 50%"))
+
+		Expect(k8sClient.Delete(ctx, child)).To(Succeed())
+		Eventually(Object(subnet)).Should(HaveField("Status.Conditions", ContainElement(SatisfyAll(
+			HaveField("Type", v1alpha1.CapacityLowCondition),
+			HaveField("Status", metav1.ConditionFalse),
+		))))
+	})
+})
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// checkSubnetAccess validates allocation from the parent subnet against its access policy.
+// Explicit path is set if a particular address is requested.
+// Unknown parent subnets are not validated, since the allocation will fail anyway.
+func checkSubnetAccess(ctx context.Context, c client.Client, parentName types.NamespacedName,
+	consumer *v1alpha1.ResourceReference, explicitPath *field.Path) (field.ErrorList, error) {
+	policy, err := getSubnetAccessPolicy(ctx, c, parentName)
+	if err != nil || policy == nil {
+		return nil, err
+	}
+
+	var allErrs field.ErrorList
+	if !policy.AllowsConsumer(consumer) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec.consumer"),
+			fmt.Sprintf("access policy of subnet %s does not allow allocations for this consumer", parentName)))
+	}
+	if explicitPath != nil && !policy.AllowsExplicitAddresses() {
+		allErrs = append(allErrs, field.Forbidden(explicitPath,
+			fmt.Sprintf("access policy of subnet %s does not allow requests of particular addresses", parentName)))
+	}
+	if req, err := admission.RequestFromContext(ctx); err == nil &&
+		!policy.AllowsUser(req.UserInfo.Username, req.UserInfo.Groups) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("metadata"),
+			fmt.Sprintf("access policy of subnet %s does not allow allocations by user %s", parentName, req.UserInfo.Username)))
+	}
+	return allErrs, nil
+}
+
+// checkSubnetConsumer validates the consumer set on update of an allocation against the access policy of the parent subnet.
+func checkSubnetConsumer(ctx context.Context, c client.Client, parentName types.NamespacedName,
+	consumer *v1alpha1.ResourceReference) (*field.Error, error) {
+	policy, err := getSubnetAccessPolicy(ctx, c, parentName)
+	if err != nil || policy == nil || policy.AllowsConsumer(consumer) {
+		return nil, err
+	}
+	return field.Forbidden(field.NewPath("spec.consumer"),
+		fmt.Sprintf("access policy of subnet %s does not allow allocations for this consumer", parentName)), nil
+}
+
+// getSubnetAccessPolicy returns the access policy of the parent subnet, or nil if the subnet is unknown.
+func getSubnetAccessPolicy(ctx context.Context, c client.Client, parentName types.NamespacedName) (*v1alpha1.SubnetAccessPolicy, error) {
+	parent := &v1alpha1.Subnet{}
+	if err := c.Get(ctx, parentName, parent); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, apierrors.NewInternalError(errors.Wrap(err, "unable to get parent subnet"))
+	}
+	return parent.Spec.AccessPolicy, nil
+}
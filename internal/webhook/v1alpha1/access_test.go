@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+
+	v1alpha2 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Subnet access policy", func() {
+	Context("When parent Subnet has an access policy", func() {
+		It("Should reject allocations not allowed by the policy", func() {
+			testNamespaceName := createTestNamespace()
+			ctx := context.Background()
+
+			newSubnet := func(name string, policy *v1alpha2.SubnetAccessPolicy) *v1alpha2.Subnet {
+				return &v1alpha2.Subnet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: testNamespaceName,
+					},
+					Spec: v1alpha2.SubnetSpec{
+						CIDR:         v1alpha2.CidrMustParse("10.0.0.0/24"),
+						Network:      corev1.LocalObjectReference{Name: "network"},
+						AccessPolicy: policy,
+					},
+				}
+			}
+			newIP := func(subnet string, ip string, consumerKind string) *v1alpha2.IP {
+				cr := &v1alpha2.IP{
+					ObjectMeta: metav1.ObjectMeta{
+						GenerateName: "ip-",
+						Namespace:    testNamespaceName,
+					},
+					Spec: v1alpha2.IPSpec{
+						Subnet:   v1alpha2.SubnetReference{Name: subnet},
+						Consumer: &v1alpha2.ResourceReference{Kind: consumerKind, Name: "consumer"},
+					},
+				}
+				if ip != "" {
+					cr.Spec.IP = v1alpha2.IPMustParse(ip)
+				}
+				return cr
+			}
+			forbidden := func(cr client.Object) func() bool {
+				return func() bool {
+					err := k8sClient.Create(ctx, cr.DeepCopyObject().(client.Object), client.DryRunAll)
+					return apierrors.IsInvalid(err)
+				}
+			}
+
+			By("Creating Subnets with access policies")
+			// envtest client authenticates as a member of system:masters group
+			Expect(k8sClient.Create(ctx, newSubnet("restricted", &v1alpha2.SubnetAccessPolicy{
+				Consumers:              []v1alpha2.ConsumerKind{{Kind: "Machine"}},
+				Groups:                 []string{"system:masters"},
+				AllowExplicitAddresses: ptr.To(false),
+			}))).To(Succeed())
+			Expect(k8sClient.Create(ctx, newSubnet("private", &v1alpha2.SubnetAccessPolicy{
+				Users: []string{"system:serviceaccount:default:allocator"},
+			}))).To(Succeed())
+
+			By("Attempting to allocate IPs not allowed by the policy")
+			Eventually(forbidden(newIP("restricted", "", "Pod")), Timeout, Interval).Should(BeTrue())
+			Eventually(forbidden(newIP("restricted", "10.0.0.10", "Machine")), Timeout, Interval).Should(BeTrue())
+			Eventually(forbidden(newIP("private", "", "Machine")), Timeout, Interval).Should(BeTrue())
+
+			By("Attempting to allocate a child Subnet with explicit CIDR")
+			child := newSubnet("child", nil)
+			child.Spec.CIDR = v1alpha2.CidrMustParse("10.0.0.0/28")
+			child.Spec.ParentSubnet = v1alpha2.SubnetReference{Name: "restricted"}
+			child.Spec.Consumer = &v1alpha2.ResourceReference{Kind: "Machine", Name: "consumer"}
+			Eventually(forbidden(child), Timeout, Interval).Should(BeTrue())
+
+			By("Allocating an IP allowed by the policy")
+			ip := newIP("restricted", "", "Machine")
+			Expect(k8sClient.Create(ctx, ip)).To(Succeed())
+
+			By("Attempting to change the consumer of the IP")
+			changed := ip.DeepCopy()
+			changed.Spec.Consumer = &v1alpha2.ResourceReference{Kind: "Pod", Name: "consumer"}
+			Expect(apierrors.IsInvalid(k8sClient.Update(ctx, changed))).To(BeTrue())
+
+			By("Removing the consumer of the IP")
+			ip.Spec.Consumer = nil
+			Expect(k8sClient.Update(ctx, ip)).To(Succeed())
+
+			By("Attempting to set the consumer of the IP not allowed by the policy")
+			ip.Spec.Consumer = &v1alpha2.ResourceReference{Kind: "Pod", Name: "consumer"}
+			Expect(apierrors.IsInvalid(k8sClient.Update(ctx, ip))).To(BeTrue())
+
+			By("Setting the consumer of the IP allowed by the policy")
+			ip.Spec.Consumer = &v1alpha2.ResourceReference{Kind: "Machine", Name: "another"}
+			Expect(k8sClient.Update(ctx, ip)).To(Succeed())
+		})
+	})
+})
@@ -6,6 +6,7 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
 
@@ -64,10 +65,46 @@ func (v *IPCustomValidator) ValidateCreate(ctx context.Context, obj *v1alpha1.IP
 			field.NewPath("spec.subnet.name"), obj.Spec.IP, "Parent subnet should be defined"))
 	}
 
+	if obj.Spec.Subnet.Name != "" {
+		grantErr, err := checkReferenceGrant(ctx, v.Client, "IP", obj.Namespace, obj.Spec.Subnet, field.NewPath("spec.subnet.namespace"))
+		if err != nil {
+			return warnings, err
+		}
+		if grantErr != nil {
+			allErrs = append(allErrs, grantErr)
+		}
+
+		var explicitPath *field.Path
+		if obj.Spec.IP != nil {
+			explicitPath = field.NewPath("spec.ip")
+		}
+		accessErrs, err := checkSubnetAccess(ctx, v.Client, obj.Spec.Subnet.NamespacedName(obj.Namespace), obj.Spec.Consumer, explicitPath)
+		if err != nil {
+			return warnings, err
+		}
+		allErrs = append(allErrs, accessErrs...)
+
+		if obj.Spec.IP != nil {
+			network, err := getSubnetNetwork(ctx, v.Client, obj.Spec.Subnet.NamespacedName(obj.Namespace))
+			if err != nil {
+				return warnings, err
+			}
+			specialPurposeWarnings, specialPurposeErr := checkSpecialPurpose(network, obj.Spec.IP.AsCidr(), field.NewPath("spec.ip"))
+			warnings = append(warnings, specialPurposeWarnings...)
+			if specialPurposeErr != nil {
+				allErrs = append(allErrs, specialPurposeErr)
+			}
+		}
+	}
+
 	if len(allErrs) > 0 {
 		return warnings, apierrors.NewInvalid(obj.GroupVersionKind().GroupKind(), obj.Name, allErrs)
 	}
 
+	if err := checkQuotas(ctx, v.Client, nil, obj, "ips", requestedIP); err != nil {
+		return warnings, err
+	}
+
 	return warnings, nil
 }
 
@@ -87,15 +124,33 @@ func (v *IPCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj *
 		}
 	}
 
-	if oldObj.Spec.Subnet.Name != newObj.Spec.Subnet.Name {
+	if oldObj.Spec.Subnet != newObj.Spec.Subnet {
 		allErrs = append(allErrs, field.Invalid(
-			field.NewPath("spec.subnet.name"), newObj.Spec.Subnet.Name, "Subnet change is disallowed"))
+			field.NewPath("spec.subnet"), newObj.Spec.Subnet, "Subnet change is disallowed"))
+	}
+
+	// New consumer should be allowed by the subnet access policy as well.
+	if newObj.Spec.Consumer != nil && !reflect.DeepEqual(oldObj.Spec.Consumer, newObj.Spec.Consumer) {
+		consumerErr, err := checkSubnetConsumer(ctx, v.Client, newObj.Spec.Subnet.NamespacedName(newObj.Namespace), newObj.Spec.Consumer)
+		if err != nil {
+			return warnings, err
+		}
+		if consumerErr != nil {
+			allErrs = append(allErrs, consumerErr)
+		}
 	}
 
 	if len(allErrs) > 0 {
 		return warnings, apierrors.NewInvalid(newObj.GroupVersionKind().GroupKind(), newObj.Name, allErrs)
 	}
 
+	// Label change may move the IP into quotas not accounting it yet.
+	if !reflect.DeepEqual(oldObj.Labels, newObj.Labels) {
+		if err := checkQuotas(ctx, v.Client, oldObj, newObj, "ips", requestedIP); err != nil {
+			return warnings, err
+		}
+	}
+
 	return warnings, nil
 }
 
@@ -11,7 +11,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -35,7 +34,7 @@ var _ = Describe("IP webhook", func() {
 						Namespace: testNamespaceName,
 					},
 					Spec: v1alpha2.IPSpec{
-						Subnet: corev1.LocalObjectReference{
+						Subnet: v1alpha2.SubnetReference{
 							Name: "sample-subnet",
 						},
 						Consumer: &v1alpha2.ResourceReference{
@@ -64,7 +63,7 @@ var _ = Describe("IP webhook", func() {
 						Namespace: testNamespaceName,
 					},
 					Spec: v1alpha2.IPSpec{
-						Subnet: corev1.LocalObjectReference{
+						Subnet: v1alpha2.SubnetReference{
 							Name: "sample-subnet",
 						},
 					},
@@ -75,7 +74,7 @@ var _ = Describe("IP webhook", func() {
 						Namespace: testNamespaceName,
 					},
 					Spec: v1alpha2.IPSpec{
-						Subnet: corev1.LocalObjectReference{
+						Subnet: v1alpha2.SubnetReference{
 							Name: "sample-subnet",
 						},
 						Consumer: &v1alpha2.ResourceReference{
@@ -90,7 +89,7 @@ var _ = Describe("IP webhook", func() {
 						Namespace: testNamespaceName,
 					},
 					Spec: v1alpha2.IPSpec{
-						Subnet: corev1.LocalObjectReference{
+						Subnet: v1alpha2.SubnetReference{
 							Name: "sample-subnet",
 						},
 						IP: v1alpha2.IPMustParse("192.168.1.1"),
@@ -102,7 +101,7 @@ var _ = Describe("IP webhook", func() {
 						Namespace: testNamespaceName,
 					},
 					Spec: v1alpha2.IPSpec{
-						Subnet: corev1.LocalObjectReference{
+						Subnet: v1alpha2.SubnetReference{
 							Name: "sample-subnet",
 						},
 						Consumer: &v1alpha2.ResourceReference{
@@ -135,7 +134,7 @@ var _ = Describe("IP webhook", func() {
 						Namespace: testNamespaceName,
 					},
 					Spec: v1alpha2.IPSpec{
-						Subnet: corev1.LocalObjectReference{
+						Subnet: v1alpha2.SubnetReference{
 							Name: "sample-subnet",
 						},
 					},
@@ -146,7 +145,7 @@ var _ = Describe("IP webhook", func() {
 						Namespace: testNamespaceName,
 					},
 					Spec: v1alpha2.IPSpec{
-						Subnet: corev1.LocalObjectReference{
+						Subnet: v1alpha2.SubnetReference{
 							Name: "sample-subnet",
 						},
 						Consumer: &v1alpha2.ResourceReference{
@@ -161,7 +160,7 @@ var _ = Describe("IP webhook", func() {
 						Namespace: testNamespaceName,
 					},
 					Spec: v1alpha2.IPSpec{
-						Subnet: corev1.LocalObjectReference{
+						Subnet: v1alpha2.SubnetReference{
 							Name: "sample-subnet",
 						},
 						IP: v1alpha2.IPMustParse("192.168.1.1"),
@@ -173,7 +172,7 @@ var _ = Describe("IP webhook", func() {
 						Namespace: testNamespaceName,
 					},
 					Spec: v1alpha2.IPSpec{
-						Subnet: corev1.LocalObjectReference{
+						Subnet: v1alpha2.SubnetReference{
 							Name: "sample-subnet",
 						},
 						Consumer: &v1alpha2.ResourceReference{
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"reflect"
 
 	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
 
@@ -17,6 +18,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -25,7 +27,7 @@ var networklog = logf.Log.WithName("network-resource")
 
 func SetupNetworkWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr, &v1alpha1.Network{}).
-		WithValidator(&NetworkCustomValidator{}).
+		WithValidator(&NetworkCustomValidator{Client: mgr.GetClient()}).
 		Complete()
 }
 
@@ -39,6 +41,7 @@ func SetupNetworkWebhookWithManager(mgr ctrl.Manager) error {
 // NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
 // as this struct is used only for temporary operations and does not need to be deeply copied.
 type NetworkCustomValidator struct {
+	client.Client
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
@@ -57,6 +60,13 @@ func (v *NetworkCustomValidator) ValidateCreate(ctx context.Context, obj *v1alph
 		allErrs = append(allErrs, err)
 	}
 
+	if err := validateUtilizationThresholds(obj.Spec.UtilizationWarning, obj.Spec.UtilizationCritical); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	allErrs = append(allErrs, validateReservedRanges(obj)...)
+	allErrs = append(allErrs, validateAllowedRanges(obj)...)
+
 	if len(allErrs) > 0 {
 		gvk := obj.GroupVersionKind()
 		gk := schema.GroupKind{
@@ -66,6 +76,10 @@ func (v *NetworkCustomValidator) ValidateCreate(ctx context.Context, obj *v1alph
 		return warnings, apierrors.NewInvalid(gk, obj.Name, allErrs)
 	}
 
+	if err := checkQuotas(ctx, v.Client, nil, obj, "networks", requestedNetwork); err != nil {
+		return warnings, err
+	}
+
 	return warnings, nil
 }
 
@@ -93,6 +107,13 @@ func (v *NetworkCustomValidator) ValidateUpdate(ctx context.Context, oldObj, new
 		allErrs = append(allErrs, err)
 	}
 
+	if err := validateUtilizationThresholds(newObj.Spec.UtilizationWarning, newObj.Spec.UtilizationCritical); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	allErrs = append(allErrs, validateReservedRanges(newObj)...)
+	allErrs = append(allErrs, validateAllowedRanges(newObj)...)
+
 	if len(allErrs) > 0 {
 		gvk := newObj.GroupVersionKind()
 		gk := schema.GroupKind{
@@ -102,6 +123,13 @@ func (v *NetworkCustomValidator) ValidateUpdate(ctx context.Context, oldObj, new
 		return warnings, apierrors.NewInvalid(gk, newObj.Name, allErrs)
 	}
 
+	// Label change may move the Network into quotas not accounting it yet.
+	if !reflect.DeepEqual(oldObj.Labels, newObj.Labels) {
+		if err := checkQuotas(ctx, v.Client, oldObj, newObj, "networks", requestedNetwork); err != nil {
+			return warnings, err
+		}
+	}
+
 	return warnings, nil
 }
 
@@ -112,12 +140,22 @@ func (v *NetworkCustomValidator) ValidateDelete(ctx context.Context, obj *v1alph
 
 	networklog.Info("validate delete", "name", obj.Name)
 
-	if len(obj.Status.IPv4Ranges) > 0 {
+	// Ranges booked by reserved ranges don't prevent the deletion
+	ipv4Reserved, ipv6Reserved := 0, 0
+	for _, reservedRange := range obj.Status.ReservedRanges {
+		if reservedRange.CIDR.IsIPv4() {
+			ipv4Reserved++
+		} else {
+			ipv6Reserved++
+		}
+	}
+
+	if len(obj.Status.IPv4Ranges) > ipv4Reserved {
 		allErrs = append(allErrs, field.InternalError(
 			field.NewPath("metadata.name"), errors.New("Network has active IPv4 subnets")))
 	}
 
-	if len(obj.Status.IPv6Ranges) > 0 {
+	if len(obj.Status.IPv6Ranges) > ipv6Reserved {
 		allErrs = append(allErrs, field.InternalError(
 			field.NewPath("metadata.name"), errors.New("Network has active IPv6 subnets")))
 	}
@@ -133,6 +171,40 @@ func (v *NetworkCustomValidator) ValidateDelete(ctx context.Context, obj *v1alph
 	return warnings, nil
 }
 
+// validateReservedRanges checks reserved ranges of the network don't overlap each other.
+func validateReservedRanges(in *v1alpha1.Network) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, reservedRange := range in.Spec.ReservedRanges {
+		if !reservedRange.CIDR.Net.IsValid() {
+			allErrs = append(allErrs, field.Required(field.NewPath("spec.reservedRanges").Index(i).Child("cidr"), "reserved range cidr should be set"))
+			continue
+		}
+		for j := range i {
+			if in.Spec.ReservedRanges[j].CIDR.Net.Overlaps(reservedRange.CIDR.Net) {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec.reservedRanges").Index(i).Child("cidr"), reservedRange.CIDR.String(),
+					fmt.Sprintf("reserved range overlaps reserved range %s", in.Spec.ReservedRanges[j].CIDR.String())))
+				break
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateAllowedRanges checks allowed ranges of the network don't overlap each other.
+func validateAllowedRanges(in *v1alpha1.Network) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, allowedRange := range in.Spec.AllowedRanges {
+		for j := range i {
+			if in.Spec.AllowedRanges[j].Net.Overlaps(allowedRange.Net) {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec.allowedRanges").Index(i), allowedRange.String(),
+					fmt.Sprintf("allowed range overlaps allowed range %s", in.Spec.AllowedRanges[j].String())))
+				break
+			}
+		}
+	}
+	return allErrs
+}
+
 func validateID(in *v1alpha1.Network) *field.Error {
 	if in.Spec.ID == nil {
 		return nil
@@ -12,6 +12,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 )
 
@@ -109,6 +110,40 @@ var _ = Describe("Network webhook", func() {
 						Type: v1alpha2.MPLSNetworkType,
 					},
 				},
+				{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      "warning-above-critical",
+						Namespace: testNamespaceName,
+					},
+					Spec: v1alpha2.NetworkSpec{
+						UtilizationWarning:  ptr.To[int32](90),
+						UtilizationCritical: ptr.To[int32](80),
+					},
+				},
+				{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      "overlapping-reserved-ranges",
+						Namespace: testNamespaceName,
+					},
+					Spec: v1alpha2.NetworkSpec{
+						ReservedRanges: []v1alpha2.NetworkReservedRange{
+							{CIDR: *v1alpha2.CidrMustParse("10.0.0.0/8")},
+							{CIDR: *v1alpha2.CidrMustParse("10.1.0.0/16")},
+						},
+					},
+				},
+				{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      "overlapping-allowed-ranges",
+						Namespace: testNamespaceName,
+					},
+					Spec: v1alpha2.NetworkSpec{
+						AllowedRanges: []v1alpha2.CIDR{
+							*v1alpha2.CidrMustParse("10.0.0.0/8"),
+							*v1alpha2.CidrMustParse("10.1.0.0/16"),
+						},
+					},
+				},
 			}
 
 			ctx := context.Background()
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// checkQuotas rejects creation of the resource, if it exceeds any IPAMQuota of the namespace selecting the resource.
+// On update oldObj is set, and only quotas selecting the resource, but not oldObj, are checked, since the resource
+// is already accounted by quotas selecting oldObj.
+// Usage is calculated from existing resources instead of quota status, since the status is updated asynchronously.
+func checkQuotas(ctx context.Context, c client.Client, oldObj, obj client.Object, resourceName string,
+	requested func(subnets []v1alpha1.Subnet) v1alpha1.IPAMQuotaResources) error {
+	quotas := &v1alpha1.IPAMQuotaList{}
+	if err := c.List(ctx, quotas, client.InNamespace(obj.GetNamespace())); err != nil {
+		return apierrors.NewInternalError(errors.Wrap(err, "unable to list ipam quotas"))
+	}
+	if len(quotas.Items) == 0 {
+		return nil
+	}
+
+	networks := &v1alpha1.NetworkList{}
+	if err := c.List(ctx, networks, client.InNamespace(obj.GetNamespace())); err != nil {
+		return apierrors.NewInternalError(errors.Wrap(err, "unable to list networks"))
+	}
+	subnets := &v1alpha1.SubnetList{}
+	if err := c.List(ctx, subnets, client.InNamespace(obj.GetNamespace())); err != nil {
+		return apierrors.NewInternalError(errors.Wrap(err, "unable to list subnets"))
+	}
+	ips := &v1alpha1.IPList{}
+	if err := c.List(ctx, ips, client.InNamespace(obj.GetNamespace())); err != nil {
+		return apierrors.NewInternalError(errors.Wrap(err, "unable to list ips"))
+	}
+
+	resources := requested(subnets.Items)
+	for _, quota := range quotas.Items {
+		selected, err := quota.Selects(obj)
+		if err != nil {
+			return apierrors.NewInternalError(errors.Wrapf(err, "unable to apply selector of ipam quota %s", quota.Name))
+		}
+		if !selected {
+			continue
+		}
+		if oldObj != nil {
+			accounted, err := quota.Selects(oldObj)
+			if err != nil {
+				return apierrors.NewInternalError(errors.Wrapf(err, "unable to apply selector of ipam quota %s", quota.Name))
+			}
+			if accounted {
+				continue
+			}
+		}
+		used, err := quota.Usage(networks.Items, subnets.Items, ips.Items)
+		if err != nil {
+			return apierrors.NewInternalError(errors.Wrapf(err, "unable to calculate usage of ipam quota %s", quota.Name))
+		}
+		if exceeded := quota.Exceeds(used, resources); len(exceeded) > 0 {
+			return apierrors.NewForbidden(v1alpha1.SchemeGroupVersion.WithResource(resourceName).GroupResource(),
+				obj.GetName(), errors.New(quota.ExceededMessage(exceeded)))
+		}
+	}
+
+	return nil
+}
+
+func requestedNetwork(_ []v1alpha1.Subnet) v1alpha1.IPAMQuotaResources {
+	return v1alpha1.IPAMQuotaResources{Networks: ptr.To[int64](1)}
+}
+
+func requestedIP(_ []v1alpha1.Subnet) v1alpha1.IPAMQuotaResources {
+	return v1alpha1.IPAMQuotaResources{IPs: ptr.To[int64](1)}
+}
+
+// requestedSubnet returns capacity requested by a child subnet; top level subnets are not limited.
+// Parent from another namespace is fetched with the client, and is considered unknown if it can not be retrieved.
+func requestedSubnet(ctx context.Context, c client.Client, subnet *v1alpha1.Subnet) func(subnets []v1alpha1.Subnet) v1alpha1.IPAMQuotaResources {
+	return func(subnets []v1alpha1.Subnet) v1alpha1.IPAMQuotaResources {
+		if subnet.Spec.ParentSubnet.Name == "" {
+			return v1alpha1.IPAMQuotaResources{}
+		}
+		var parent *v1alpha1.Subnet
+		if subnet.Spec.ParentSubnet.IsCrossNamespace(subnet.Namespace) {
+			parent = &v1alpha1.Subnet{}
+			if err := c.Get(ctx, subnet.Spec.ParentSubnet.NamespacedName(subnet.Namespace), parent); err != nil {
+				parent = nil
+			}
+		} else {
+			for i := range subnets {
+				if subnets[i].Name == subnet.Spec.ParentSubnet.Name {
+					parent = &subnets[i]
+					break
+				}
+			}
+		}
+		capacity := subnet.RequestedCapacity(parent)
+		return v1alpha1.IPAMQuotaResources{SubnetCapacity: ptr.To(capacity)}
+	}
+}
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+
+	v1alpha2 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("IPAM quota", func() {
+	Context("When IPAMQuota limits IPs of a team", func() {
+		It("Should reject IPs exceeding the quota", func() {
+			testNamespaceName := createTestNamespace()
+			ctx := context.Background()
+
+			quota := &v1alpha2.IPAMQuota{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "team-a",
+					Namespace: testNamespaceName,
+				},
+				Spec: v1alpha2.IPAMQuotaSpec{
+					Hard: v1alpha2.IPAMQuotaResources{
+						IPs: ptr.To[int64](1),
+					},
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, quota)).To(Succeed())
+
+			newIP := func(name, team string) *v1alpha2.IP {
+				return &v1alpha2.IP{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: testNamespaceName,
+						Labels:    map[string]string{"team": team},
+					},
+					Spec: v1alpha2.IPSpec{
+						Subnet: v1alpha2.SubnetReference{Name: "sample-subnet"},
+					},
+				}
+			}
+
+			By("Creating an IP within the quota")
+			Expect(k8sClient.Create(ctx, newIP("first", "a"))).To(Succeed())
+
+			By("Attempting to create an IP exceeding the quota")
+			Eventually(func() bool {
+				err := k8sClient.Create(ctx, newIP("second", "a"), client.DryRunAll)
+				return apierrors.IsForbidden(err)
+			}, Timeout, Interval).Should(BeTrue())
+
+			By("Creating an IP not selected by the quota")
+			other := newIP("other", "b")
+			Expect(k8sClient.Create(ctx, other)).To(Succeed())
+
+			By("Attempting to move the IP into the quota by labels")
+			other.Labels["team"] = "a"
+			Expect(apierrors.IsForbidden(k8sClient.Update(ctx, other))).To(BeTrue())
+
+			By("Updating labels of an IP accounted by the quota")
+			first := &v1alpha2.IP{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: testNamespaceName, Name: "first"}, first)).To(Succeed())
+			first.Labels["role"] = "gateway"
+			Expect(k8sClient.Update(ctx, first)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// checkReferenceGrant validates that a cross namespace subnet reference
+// is permitted by an IPAMReferenceGrant of the subnet namespace.
+func checkReferenceGrant(ctx context.Context, c client.Client, kind, namespace string,
+	ref v1alpha1.SubnetReference, path *field.Path) (*field.Error, error) {
+	if !ref.IsCrossNamespace(namespace) {
+		return nil, nil
+	}
+	grants := &v1alpha1.IPAMReferenceGrantList{}
+	if err := c.List(ctx, grants, client.InNamespace(ref.Namespace)); err != nil {
+		return nil, apierrors.NewInternalError(errors.Wrap(err, "unable to list ipam reference grants"))
+	}
+	if !v1alpha1.ReferencePermitted(grants.Items, kind, namespace, ref) {
+		return field.Forbidden(path,
+			fmt.Sprintf("reference to subnet %s is not permitted by any IPAMReferenceGrant", ref.NamespacedName(namespace))), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+
+	v1alpha2 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("IPAM reference grants", func() {
+	Context("When IPs and Subnets refer a Subnet of another namespace", func() {
+		It("Should allow only references permitted by a grant of the Subnet namespace", func() {
+			platformNamespaceName := createTestNamespace()
+			tenantNamespaceName := createTestNamespace()
+			ctx := context.Background()
+
+			By("Creating a Subnet in the platform namespace")
+			Expect(k8sClient.Create(ctx, &v1alpha2.Subnet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pool",
+					Namespace: platformNamespaceName,
+				},
+				Spec: v1alpha2.SubnetSpec{
+					CIDR:    v1alpha2.CidrMustParse("10.0.0.0/16"),
+					Network: corev1.LocalObjectReference{Name: "network"},
+				},
+			})).To(Succeed())
+
+			ref := v1alpha2.SubnetReference{Name: "pool", Namespace: platformNamespaceName}
+			ip := &v1alpha2.IP{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ip",
+					Namespace: tenantNamespaceName,
+				},
+				Spec: v1alpha2.IPSpec{
+					Subnet: ref,
+				},
+			}
+			child := &v1alpha2.Subnet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "child",
+					Namespace: tenantNamespaceName,
+				},
+				Spec: v1alpha2.SubnetSpec{
+					CIDR:         v1alpha2.CidrMustParse("10.0.0.0/24"),
+					Network:      corev1.LocalObjectReference{Name: "network"},
+					ParentSubnet: ref,
+				},
+			}
+			create := func(cr client.Object) func() error {
+				return func() error {
+					return k8sClient.Create(ctx, cr.DeepCopyObject().(client.Object), client.DryRunAll)
+				}
+			}
+
+			By("Attempting to refer the Subnet without a grant")
+			Expect(apierrors.IsInvalid(create(ip)())).To(BeTrue())
+			Expect(apierrors.IsInvalid(create(child)())).To(BeTrue())
+
+			By("Granting references from IPs of the tenant namespace")
+			Expect(k8sClient.Create(ctx, &v1alpha2.IPAMReferenceGrant{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tenant",
+					Namespace: platformNamespaceName,
+				},
+				Spec: v1alpha2.IPAMReferenceGrantSpec{
+					From: []v1alpha2.ReferenceGrantFrom{{Kind: "IP", Namespace: tenantNamespaceName}},
+					To:   []v1alpha2.ReferenceGrantTo{{Name: "pool"}},
+				},
+			})).To(Succeed())
+
+			Eventually(create(ip), Timeout, Interval).Should(Succeed())
+			Expect(apierrors.IsInvalid(create(child)())).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+// getNetwork returns the network or nil, if the one doesn't exist yet.
+func getNetwork(ctx context.Context, c client.Client, name types.NamespacedName) (*v1alpha1.Network, error) {
+	network := &v1alpha1.Network{}
+	err := c.Get(ctx, name, network)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apierrors.NewInternalError(errors.Wrap(err, "unable to get network"))
+	}
+	return network, nil
+}
+
+// getSubnetNetwork returns the network of the subnet or nil, if either of them doesn't exist yet.
+func getSubnetNetwork(ctx context.Context, c client.Client, subnetName types.NamespacedName) (*v1alpha1.Network, error) {
+	subnet := &v1alpha1.Subnet{}
+	err := c.Get(ctx, subnetName, subnet)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apierrors.NewInternalError(errors.Wrap(err, "unable to get subnet"))
+	}
+	return getNetwork(ctx, c, types.NamespacedName{Namespace: subnet.Namespace, Name: subnet.Spec.Network.Name})
+}
+
+// checkSpecialPurpose applies the special-purpose policy of the network to the CIDR, overlaps with IANA special-purpose
+// blocks are either warned about or rejected. The default policy is applied if the network doesn't exist yet.
+func checkSpecialPurpose(network *v1alpha1.Network, cidr *v1alpha1.CIDR, path *field.Path) (admission.Warnings, *field.Error) {
+	var policy *v1alpha1.SpecialPurposePolicy
+	networkName := ""
+	if network != nil {
+		policy = network.Spec.SpecialPurposePolicy
+		networkName = network.Name
+	}
+
+	action, blocks := policy.Check(cidr)
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		names = append(names, block.String())
+	}
+	msg := fmt.Sprintf("%s overlaps special-purpose blocks %s", cidr.String(), strings.Join(names, ", "))
+
+	if action == v1alpha1.RejectSpecialPurposeAction {
+		return nil, field.Invalid(path, cidr.String(), fmt.Sprintf("%s, which network %s rejects", msg, networkName))
+	}
+	return admission.Warnings{msg}, nil
+}
@@ -19,12 +19,15 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// Finished child resources are indexed by namespaced name of the parent subnet,
+// since they may refer subnets of another namespace.
 const (
 	FinishedChildSubnetToSubnetIndexKey = "finishedChildSubnetToSubnet"
 	FinishedChildIPToSubnetIndexKey     = "finishedChildIPToSubnet"
@@ -40,14 +43,13 @@ func SetupSubnetWebhookWithManager(mgr ctrl.Manager) error {
 			return nil
 		}
 		state := subnet.Status.State
-		parentSubnet := subnet.Spec.ParentSubnet.Name
-		if parentSubnet == "" {
+		if subnet.Spec.ParentSubnet.Name == "" {
 			return nil
 		}
 		if state != v1alpha1.FinishedSubnetState {
 			return nil
 		}
-		return []string{parentSubnet}
+		return []string{subnet.Spec.ParentSubnet.NamespacedName(subnet.Namespace).String()}
 	}
 
 	createChildIPIndexValue := func(object client.Object) []string {
@@ -56,11 +58,10 @@ func SetupSubnetWebhookWithManager(mgr ctrl.Manager) error {
 			return nil
 		}
 		state := ip.Status.State
-		parentSubnet := ip.Spec.Subnet.Name
 		if state != v1alpha1.FinishedIPState {
 			return nil
 		}
-		return []string{parentSubnet}
+		return []string{ip.Spec.Subnet.NamespacedName(ip.Namespace).String()}
 	}
 
 	if err := mgr.GetFieldIndexer().IndexField(
@@ -139,6 +140,47 @@ func (v *SubnetCustomValidator) ValidateCreate(ctx context.Context, obj *v1alpha
 		}
 	}
 
+	if err := validateUtilizationThresholds(obj.Spec.UtilizationWarning, obj.Spec.UtilizationCritical); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	allErrs = append(allErrs, validateNetworkConfig(obj)...)
+	allErrs = append(allErrs, validateDNS(obj)...)
+	allErrs = append(allErrs, validateSplit(obj)...)
+
+	if obj.Spec.ParentSubnet.Name != "" {
+		grantErr, err := checkReferenceGrant(ctx, v.Client, "Subnet", obj.Namespace, obj.Spec.ParentSubnet, field.NewPath("spec.parentSubnet.namespace"))
+		if err != nil {
+			return warnings, err
+		}
+		if grantErr != nil {
+			allErrs = append(allErrs, grantErr)
+		}
+
+		var explicitPath *field.Path
+		if obj.Spec.CIDR != nil {
+			explicitPath = field.NewPath("spec.cidr")
+		}
+		accessErrs, err := checkSubnetAccess(ctx, v.Client, obj.Spec.ParentSubnet.NamespacedName(obj.Namespace), obj.Spec.Consumer, explicitPath)
+		if err != nil {
+			return warnings, err
+		}
+		allErrs = append(allErrs, accessErrs...)
+	} else if obj.Spec.CIDR != nil {
+		network, err := getNetwork(ctx, v.Client, types.NamespacedName{Namespace: obj.Namespace, Name: obj.Spec.Network.Name})
+		if err != nil {
+			return warnings, err
+		}
+		if rangeErr := checkNetworkRanges(network, obj); rangeErr != nil {
+			allErrs = append(allErrs, rangeErr)
+		}
+		specialPurposeWarnings, specialPurposeErr := checkSpecialPurpose(network, obj.Spec.CIDR, field.NewPath("spec.cidr"))
+		warnings = append(warnings, specialPurposeWarnings...)
+		if specialPurposeErr != nil {
+			allErrs = append(allErrs, specialPurposeErr)
+		}
+	}
+
 	if len(allErrs) > 0 {
 		gvk := obj.GroupVersionKind()
 		gk := schema.GroupKind{
@@ -148,6 +190,10 @@ func (v *SubnetCustomValidator) ValidateCreate(ctx context.Context, obj *v1alpha
 		return warnings, apierrors.NewInvalid(gk, obj.Name, allErrs)
 	}
 
+	if err := checkQuotas(ctx, v.Client, nil, obj, "subnets", requestedSubnet(ctx, v.Client, obj)); err != nil {
+		return warnings, err
+	}
+
 	return warnings, nil
 }
 
@@ -181,8 +227,19 @@ func (v *SubnetCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newO
 		}
 	}
 
-	if oldObj.Spec.ParentSubnet.Name != newObj.Spec.ParentSubnet.Name {
-		allErrs = append(allErrs, field.Invalid(field.NewPath("spec.parentSubnet.name"), newObj.Spec.CIDR, "Parent Subnet change is disallowed"))
+	if oldObj.Spec.ParentSubnet != newObj.Spec.ParentSubnet {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec.parentSubnet"), newObj.Spec.ParentSubnet, "Parent Subnet change is disallowed"))
+	}
+
+	// New consumer should be allowed by the parent access policy as well.
+	if newObj.Spec.ParentSubnet.Name != "" && newObj.Spec.Consumer != nil && !reflect.DeepEqual(oldObj.Spec.Consumer, newObj.Spec.Consumer) {
+		consumerErr, err := checkSubnetConsumer(ctx, v.Client, newObj.Spec.ParentSubnet.NamespacedName(newObj.Namespace), newObj.Spec.Consumer)
+		if err != nil {
+			return warnings, err
+		}
+		if consumerErr != nil {
+			allErrs = append(allErrs, consumerErr)
+		}
 	}
 
 	if oldObj.Spec.Network.Name != newObj.Spec.Network.Name {
@@ -193,6 +250,34 @@ func (v *SubnetCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newO
 		allErrs = append(allErrs, field.Invalid(field.NewPath("spec.regions"), newObj.Spec.CIDR, "Regions change is disallowed"))
 	}
 
+	if err := validateUtilizationThresholds(newObj.Spec.UtilizationWarning, newObj.Spec.UtilizationCritical); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	// Gateway is reserved along with the CIDR, so it may not be changed afterwards.
+	if oldObj.Spec.Gateway != nil || newObj.Spec.Gateway != nil {
+		if oldObj.Spec.Gateway == nil || newObj.Spec.Gateway == nil ||
+			!oldObj.Spec.Gateway.Equal(newObj.Spec.Gateway) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.gateway"), newObj.Spec.Gateway, "Gateway change is disallowed"))
+		}
+	}
+
+	allErrs = append(allErrs, validateNetworkConfig(newObj)...)
+	allErrs = append(allErrs, validateDNS(newObj)...)
+	allErrs = append(allErrs, validateSplit(newObj)...)
+
+	// Children of the split are released once the split changes, so they should not be in use.
+	if !reflect.DeepEqual(oldObj.Spec.Split, newObj.Spec.Split) {
+		child, err := splitChildInUse(ctx, v.Client, oldObj)
+		if err != nil {
+			return warnings, err
+		}
+		if child != "" {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec.split"),
+				fmt.Sprintf("Split change is disallowed while child subnet %s is in use", child)))
+		}
+	}
+
 	if len(allErrs) > 0 {
 		return warnings, apierrors.NewInvalid(
 			schema.GroupKind{
@@ -201,6 +286,13 @@ func (v *SubnetCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newO
 			}, newObj.Name, allErrs)
 	}
 
+	// Label change may move the Subnet into quotas not accounting it yet.
+	if !reflect.DeepEqual(oldObj.Labels, newObj.Labels) {
+		if err := checkQuotas(ctx, v.Client, oldObj, newObj, "subnets", requestedSubnet(ctx, v.Client, newObj)); err != nil {
+			return warnings, err
+		}
+	}
+
 	return warnings, nil
 }
 
@@ -245,11 +337,11 @@ func (v *SubnetCustomValidator) ValidateDelete(ctx context.Context, obj *v1alpha
 	}
 
 	childSubnetsMatchingFields := client.MatchingFields{
-		FinishedChildSubnetToSubnetIndexKey: obj.Name,
+		FinishedChildSubnetToSubnetIndexKey: client.ObjectKeyFromObject(obj).String(),
 	}
 
 	subnets := &v1alpha1.SubnetList{}
-	if err := v.List(context.Background(), subnets, client.InNamespace(obj.Namespace), childSubnetsMatchingFields, client.Limit(1)); err != nil {
+	if err := v.List(context.Background(), subnets, childSubnetsMatchingFields, client.Limit(1)); err != nil {
 		wrappedErr := errors.Wrap(err, "unable to get connected child subnets")
 		subnetlog.Error(wrappedErr,
 			"", "name", types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name})
@@ -263,11 +355,11 @@ func (v *SubnetCustomValidator) ValidateDelete(ctx context.Context, obj *v1alpha
 	}
 
 	childIPsMatchingFields := client.MatchingFields{
-		FinishedChildIPToSubnetIndexKey: obj.Name,
+		FinishedChildIPToSubnetIndexKey: client.ObjectKeyFromObject(obj).String(),
 	}
 
 	ips := &v1alpha1.IPList{}
-	if err := v.List(context.Background(), ips, client.InNamespace(obj.Namespace), childIPsMatchingFields, client.Limit(1)); err != nil {
+	if err := v.List(context.Background(), ips, childIPsMatchingFields, client.Limit(1)); err != nil {
 		wrappedErr := errors.Wrap(err, "unable to get connected child ips")
 		subnetlog.Error(wrappedErr, "", "name", types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name})
 		return append(warnings, wrappedErr.Error()), wrappedErr
@@ -323,6 +415,164 @@ func uniqueAZSet(azs []string) bool {
 	return true
 }
 
+// validateUtilizationThresholds checks that the warning is raised before utilization becomes critical.
+func validateUtilizationThresholds(warning, critical *int32) *field.Error {
+	if warning != nil && critical != nil && *warning > *critical {
+		return field.Invalid(field.NewPath("spec.utilizationWarning"), *warning, "utilization warning threshold should not exceed critical threshold")
+	}
+	return nil
+}
+
+// validateNetworkConfig checks the gateway belongs to the CIDR, if the one is set explicitly,
+// route gateways are of the same family as route destinations, and search domains are valid DNS names.
+func validateNetworkConfig(subnet *v1alpha1.Subnet) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if subnet.Spec.Gateway != nil && subnet.Spec.CIDR != nil && !subnet.Spec.CIDR.Net.Contains(subnet.Spec.Gateway.Net) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec.gateway"), subnet.Spec.Gateway, "gateway should belong to the subnet cidr"))
+	}
+
+	for i, route := range subnet.Spec.Routes {
+		path := field.NewPath("spec.routes").Index(i)
+		if route.Destination == nil {
+			allErrs = append(allErrs, field.Required(path.Child("destination"), "route destination should be set"))
+			continue
+		}
+		if route.Gateway != nil && route.Gateway.Net.Is4() != route.Destination.IsIPv4() {
+			allErrs = append(allErrs, field.Invalid(path.Child("gateway"), route.Gateway, "route gateway should be of the same family as the destination"))
+		}
+	}
+
+	for i, domain := range subnet.Spec.SearchDomains {
+		for _, msg := range validation.IsDNS1123Subdomain(domain) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.searchDomains").Index(i), domain, msg))
+		}
+	}
+
+	return allErrs
+}
+
+// checkNetworkRanges checks the CIDR of a top level subnet belongs to ranges allowed in the network,
+// and doesn't overlap ranges reserved in the network.
+// Subnets may be created before their network, so a missing network is not an error.
+func checkNetworkRanges(network *v1alpha1.Network, subnet *v1alpha1.Subnet) *field.Error {
+	if network == nil {
+		return nil
+	}
+
+	if !network.Allows(subnet.Spec.CIDR) {
+		return field.Invalid(field.NewPath("spec.cidr"), subnet.Spec.CIDR,
+			fmt.Sprintf("cidr is out of ranges allowed in network %s", network.Name))
+	}
+
+	for _, reservedRange := range network.Spec.ReservedRanges {
+		if reservedRange.CIDR.Net.Overlaps(subnet.Spec.CIDR.Net) {
+			return field.Invalid(field.NewPath("spec.cidr"), subnet.Spec.CIDR,
+				fmt.Sprintf("cidr overlaps range %s reserved in network %s: %s", reservedRange.CIDR.String(), network.Name, reservedRange.Reason))
+		}
+	}
+	return nil
+}
+
+// validateDNS checks the zone and nameservers are valid DNS names,
+// and the hostname template refers known placeholders only.
+func validateDNS(subnet *v1alpha1.Subnet) field.ErrorList {
+	var allErrs field.ErrorList
+	if subnet.Spec.DNS == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec.dns")
+
+	for _, msg := range validation.IsDNS1123Subdomain(strings.TrimSuffix(subnet.Spec.DNS.Zone, ".")) {
+		allErrs = append(allErrs, field.Invalid(path.Child("zone"), subnet.Spec.DNS.Zone, msg))
+	}
+
+	template := subnet.Spec.DNS.HostnameTemplate
+	for _, placeholder := range v1alpha1.DNSHostnamePlaceholders {
+		template = strings.ReplaceAll(template, placeholder, "")
+	}
+	if strings.ContainsAny(template, "{}") {
+		allErrs = append(allErrs, field.Invalid(path.Child("hostnameTemplate"), subnet.Spec.DNS.HostnameTemplate,
+			"hostname template should refer only "+strings.Join(v1alpha1.DNSHostnamePlaceholders, ", ")))
+	}
+
+	for i, nameserver := range subnet.Spec.DNS.Nameservers {
+		for _, msg := range validation.IsDNS1123Subdomain(strings.TrimSuffix(nameserver, ".")) {
+			allErrs = append(allErrs, field.Invalid(path.Child("nameservers").Index(i), nameserver, msg))
+		}
+	}
+
+	return allErrs
+}
+
+// validateSplit checks the split sets either prefix bits or count fitting into the CIDR, if known,
+// and the name template refers known placeholders only and makes valid names.
+func validateSplit(subnet *v1alpha1.Subnet) field.ErrorList {
+	var allErrs field.ErrorList
+	split := subnet.Spec.Split
+	if split == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec.split")
+
+	switch {
+	case split.PrefixBits == nil && split.Count == nil:
+		allErrs = append(allErrs, field.Required(path, "either prefixBits or count should be set"))
+	case split.PrefixBits != nil && split.Count != nil:
+		allErrs = append(allErrs, field.Invalid(path.Child("count"), *split.Count, "either prefixBits or count should be set, not both"))
+	case subnet.Spec.Gateway != nil:
+		// Gateway is reserved along with the CIDR, so the child covering it could never be reserved.
+		allErrs = append(allErrs, field.Forbidden(path, "split may not be set along with the gateway"))
+	case subnet.Spec.CIDR != nil:
+		if _, _, err := split.Children(subnet.Spec.CIDR); err != nil {
+			allErrs = append(allErrs, field.Invalid(path, split, err.Error()))
+		}
+	}
+
+	if split.NameTemplate != "" {
+		template := split.NameTemplate
+		for _, placeholder := range v1alpha1.SplitNamePlaceholders {
+			template = strings.ReplaceAll(template, placeholder, "")
+		}
+		if strings.ContainsAny(template, "{}") || !strings.Contains(split.NameTemplate, "{index}") {
+			allErrs = append(allErrs, field.Invalid(path.Child("nameTemplate"), split.NameTemplate,
+				"name template should refer {index} and only "+strings.Join(v1alpha1.SplitNamePlaceholders, ", ")))
+			return allErrs
+		}
+	}
+	for _, msg := range validation.IsDNS1123Subdomain(split.ChildName(subnet.Name, v1alpha1.MaxSplitChildren-1)) {
+		allErrs = append(allErrs, field.Invalid(path.Child("nameTemplate"), split.NameTemplate, msg))
+	}
+
+	return allErrs
+}
+
+// splitChildInUse returns the name of a child Subnet of the split having finished IPs or child Subnets, if any.
+func splitChildInUse(ctx context.Context, c client.Client, subnet *v1alpha1.Subnet) (string, error) {
+	children := &v1alpha1.SubnetList{}
+	if err := c.List(ctx, children, client.InNamespace(subnet.Namespace),
+		client.MatchingLabels{v1alpha1.SplitParentLabel: subnet.Name}); err != nil {
+		return "", apierrors.NewInternalError(errors.Wrap(err, "unable to list split children"))
+	}
+
+	for i := range children.Items {
+		key := client.ObjectKeyFromObject(&children.Items[i]).String()
+
+		subnets := &v1alpha1.SubnetList{}
+		if err := c.List(ctx, subnets, client.MatchingFields{FinishedChildSubnetToSubnetIndexKey: key}, client.Limit(1)); err != nil {
+			return "", apierrors.NewInternalError(errors.Wrap(err, "unable to get connected child subnets"))
+		}
+		ips := &v1alpha1.IPList{}
+		if err := c.List(ctx, ips, client.MatchingFields{FinishedChildIPToSubnetIndexKey: key}, client.Limit(1)); err != nil {
+			return "", apierrors.NewInternalError(errors.Wrap(err, "unable to get connected child ips"))
+		}
+		if len(subnets.Items) > 0 || len(ips.Items) > 0 {
+			return children.Items[i].Name, nil
+		}
+	}
+	return "", nil
+}
+
 type StringSet map[string]struct{}
 
 func (s StringSet) Put(item string) error {
@@ -16,6 +16,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -32,7 +33,7 @@ var _ = Describe("Subnet webhook", func() {
 						Namespace: testNamespaceName,
 					},
 					Spec: v1alpha1.SubnetSpec{
-						ParentSubnet: corev1.LocalObjectReference{
+						ParentSubnet: v1alpha1.SubnetReference{
 							Name: "parent-subnet",
 						},
 						Network: corev1.LocalObjectReference{
@@ -54,7 +55,7 @@ var _ = Describe("Subnet webhook", func() {
 					Spec: v1alpha1.SubnetSpec{
 						CIDR:     v1alpha1.CidrMustParse("127.0.0.0/24"),
 						Capacity: resource.NewScaledQuantity(60, 0),
-						ParentSubnet: corev1.LocalObjectReference{
+						ParentSubnet: v1alpha1.SubnetReference{
 							Name: "parent-subnet",
 						},
 						Network: corev1.LocalObjectReference{
@@ -129,7 +130,7 @@ var _ = Describe("Subnet webhook", func() {
 					},
 					Spec: v1alpha1.SubnetSpec{
 						CIDR: v1alpha1.CidrMustParse("127.0.0.0/24"),
-						ParentSubnet: corev1.LocalObjectReference{
+						ParentSubnet: v1alpha1.SubnetReference{
 							Name: "parent-subnet",
 						},
 						Network: corev1.LocalObjectReference{
@@ -154,7 +155,7 @@ var _ = Describe("Subnet webhook", func() {
 					},
 					Spec: v1alpha1.SubnetSpec{
 						CIDR: v1alpha1.CidrMustParse("127.0.0.0/24"),
-						ParentSubnet: corev1.LocalObjectReference{
+						ParentSubnet: v1alpha1.SubnetReference{
 							Name: "parent-subnet",
 						},
 						Network: corev1.LocalObjectReference{
@@ -179,7 +180,7 @@ var _ = Describe("Subnet webhook", func() {
 					},
 					Spec: v1alpha1.SubnetSpec{
 						CIDR: v1alpha1.CidrMustParse("127.0.0.0/24"),
-						ParentSubnet: corev1.LocalObjectReference{
+						ParentSubnet: v1alpha1.SubnetReference{
 							Name: "parent-subnet",
 						},
 						Network: corev1.LocalObjectReference{
@@ -197,6 +198,142 @@ var _ = Describe("Subnet webhook", func() {
 						},
 					},
 				},
+				{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      "with-gateway-out-of-cidr",
+						Namespace: testNamespaceName,
+					},
+					Spec: v1alpha1.SubnetSpec{
+						CIDR:    v1alpha1.CidrMustParse("127.0.0.0/24"),
+						Gateway: v1alpha1.IPMustParse("127.0.1.1"),
+						Network: corev1.LocalObjectReference{
+							Name: "parent-net",
+						},
+					},
+				},
+				{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      "with-route-of-mixed-families",
+						Namespace: testNamespaceName,
+					},
+					Spec: v1alpha1.SubnetSpec{
+						CIDR: v1alpha1.CidrMustParse("127.0.0.0/24"),
+						Network: corev1.LocalObjectReference{
+							Name: "parent-net",
+						},
+						Routes: []v1alpha1.SubnetRoute{
+							{
+								Destination: v1alpha1.CidrMustParse("10.0.0.0/8"),
+								Gateway:     v1alpha1.IPMustParse("fd00::1"),
+							},
+						},
+					},
+				},
+				{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      "with-invalid-search-domain",
+						Namespace: testNamespaceName,
+					},
+					Spec: v1alpha1.SubnetSpec{
+						CIDR: v1alpha1.CidrMustParse("127.0.0.0/24"),
+						Network: corev1.LocalObjectReference{
+							Name: "parent-net",
+						},
+						SearchDomains: []string{"Not A Domain"},
+					},
+				},
+				{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      "with-invalid-dns-zone",
+						Namespace: testNamespaceName,
+					},
+					Spec: v1alpha1.SubnetSpec{
+						CIDR: v1alpha1.CidrMustParse("127.0.0.0/24"),
+						Network: corev1.LocalObjectReference{
+							Name: "parent-net",
+						},
+						DNS: &v1alpha1.SubnetDNS{Zone: "Not A Zone"},
+					},
+				},
+				{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      "with-unknown-dns-placeholder",
+						Namespace: testNamespaceName,
+					},
+					Spec: v1alpha1.SubnetSpec{
+						CIDR: v1alpha1.CidrMustParse("127.0.0.0/24"),
+						Network: corev1.LocalObjectReference{
+							Name: "parent-net",
+						},
+						DNS: &v1alpha1.SubnetDNS{Zone: "example.net", HostnameTemplate: "{ip.address}.{zone}"},
+					},
+				},
+				{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      "with-invalid-dns-nameserver",
+						Namespace: testNamespaceName,
+					},
+					Spec: v1alpha1.SubnetSpec{
+						CIDR: v1alpha1.CidrMustParse("127.0.0.0/24"),
+						Network: corev1.LocalObjectReference{
+							Name: "parent-net",
+						},
+						DNS: &v1alpha1.SubnetDNS{Zone: "example.net", Nameservers: []string{"ns_1"}},
+					},
+				},
+				{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      "with-split-prefix-bits-and-count",
+						Namespace: testNamespaceName,
+					},
+					Spec: v1alpha1.SubnetSpec{
+						CIDR: v1alpha1.CidrMustParse("127.0.0.0/24"),
+						Network: corev1.LocalObjectReference{
+							Name: "parent-net",
+						},
+						Split: &v1alpha1.SubnetSplit{PrefixBits: ptr.To[byte](26), Count: ptr.To[int32](4)},
+					},
+				},
+				{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      "with-split-not-fitting-cidr",
+						Namespace: testNamespaceName,
+					},
+					Spec: v1alpha1.SubnetSpec{
+						CIDR: v1alpha1.CidrMustParse("127.0.0.0/24"),
+						Network: corev1.LocalObjectReference{
+							Name: "parent-net",
+						},
+						Split: &v1alpha1.SubnetSplit{PrefixBits: ptr.To[byte](20)},
+					},
+				},
+				{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      "with-split-name-template-without-index",
+						Namespace: testNamespaceName,
+					},
+					Spec: v1alpha1.SubnetSpec{
+						CIDR: v1alpha1.CidrMustParse("127.0.0.0/24"),
+						Network: corev1.LocalObjectReference{
+							Name: "parent-net",
+						},
+						Split: &v1alpha1.SubnetSplit{Count: ptr.To[int32](4), NameTemplate: "{subnet.name}-rack"},
+					},
+				},
+				{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      "with-split-and-gateway",
+						Namespace: testNamespaceName,
+					},
+					Spec: v1alpha1.SubnetSpec{
+						CIDR: v1alpha1.CidrMustParse("127.0.0.0/24"),
+						Network: corev1.LocalObjectReference{
+							Name: "parent-net",
+						},
+						Gateway: v1alpha1.IPMustParse("127.0.0.1"),
+						Split:   &v1alpha1.SubnetSplit{PrefixBits: ptr.To[byte](26)},
+					},
+				},
 			}
 
 			ctx := context.Background()
@@ -220,7 +357,7 @@ var _ = Describe("Subnet webhook", func() {
 					},
 					Spec: v1alpha1.SubnetSpec{
 						CIDR: v1alpha1.CidrMustParse("127.0.0.0/24"),
-						ParentSubnet: corev1.LocalObjectReference{
+						ParentSubnet: v1alpha1.SubnetReference{
 							Name: "parent-subnet",
 						},
 						Network: corev1.LocalObjectReference{
@@ -235,7 +372,7 @@ var _ = Describe("Subnet webhook", func() {
 					},
 					Spec: v1alpha1.SubnetSpec{
 						CIDR: v1alpha1.CidrMustParse("127.0.0.0/24"),
-						ParentSubnet: corev1.LocalObjectReference{
+						ParentSubnet: v1alpha1.SubnetReference{
 							Name: "parent-subnet",
 						},
 						Network: corev1.LocalObjectReference{
@@ -260,7 +397,7 @@ var _ = Describe("Subnet webhook", func() {
 					},
 					Spec: v1alpha1.SubnetSpec{
 						Capacity: resource.NewScaledQuantity(60, 0),
-						ParentSubnet: corev1.LocalObjectReference{
+						ParentSubnet: v1alpha1.SubnetReference{
 							Name: "parent-subnet",
 						},
 						Network: corev1.LocalObjectReference{
@@ -281,7 +418,7 @@ var _ = Describe("Subnet webhook", func() {
 					},
 					Spec: v1alpha1.SubnetSpec{
 						PrefixBits: bytePtr(20),
-						ParentSubnet: corev1.LocalObjectReference{
+						ParentSubnet: v1alpha1.SubnetReference{
 							Name: "parent-subnet",
 						},
 						Network: corev1.LocalObjectReference{
@@ -320,7 +457,7 @@ var _ = Describe("Subnet webhook", func() {
 					},
 					Spec: v1alpha1.SubnetSpec{
 						CIDR: v1alpha1.CidrMustParse("127.0.0.0/24"),
-						ParentSubnet: corev1.LocalObjectReference{
+						ParentSubnet: v1alpha1.SubnetReference{
 							Name: "parent-subnet",
 						},
 						Network: corev1.LocalObjectReference{
@@ -368,7 +505,7 @@ var _ = Describe("Subnet webhook", func() {
 				},
 				Spec: v1alpha1.SubnetSpec{
 					CIDR: testCidr,
-					ParentSubnet: corev1.LocalObjectReference{
+					ParentSubnet: v1alpha1.SubnetReference{
 						Name: "ps",
 					},
 					Network: corev1.LocalObjectReference{
@@ -396,6 +533,222 @@ var _ = Describe("Subnet webhook", func() {
 			By("Try to update Subnet CR")
 			cr.Spec.ParentSubnet.Name = "new"
 			Expect(k8sClient.Update(ctx, &cr)).ShouldNot(Succeed())
+
+			By("Try to update Subnet gateway")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}, &cr)).To(Succeed())
+			cr.Spec.Gateway = v1alpha1.IPMustParse("10.0.0.1")
+			Expect(k8sClient.Update(ctx, &cr)).ShouldNot(Succeed())
+		})
+	})
+
+	Context("When Network has reserved ranges", func() {
+		It("Should reject overlapping top level subnets", func() {
+			testNamespaceName := createTestNamespace()
+			ctx := context.Background()
+
+			network := v1alpha1.Network{
+				ObjectMeta: controllerruntime.ObjectMeta{
+					Name:      "reserved",
+					Namespace: testNamespaceName,
+				},
+				Spec: v1alpha1.NetworkSpec{
+					ReservedRanges: []v1alpha1.NetworkReservedRange{
+						{CIDR: *v1alpha1.CidrMustParse("10.0.0.0/16"), Reason: "upstream provider"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, &network)).Should(Succeed())
+
+			newSubnet := func(name, cidr string) *v1alpha1.Subnet {
+				return &v1alpha1.Subnet{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      name,
+						Namespace: testNamespaceName,
+					},
+					Spec: v1alpha1.SubnetSpec{
+						CIDR:    v1alpha1.CidrMustParse(cidr),
+						Network: corev1.LocalObjectReference{Name: network.Name},
+					},
+				}
+			}
+
+			By("Attempting to create Subnet within the reserved range")
+			Expect(k8sClient.Create(ctx, newSubnet("within", "10.0.1.0/24"))).ShouldNot(Succeed())
+			By("Attempting to create Subnet enclosing the reserved range")
+			Expect(k8sClient.Create(ctx, newSubnet("enclosing", "10.0.0.0/8"))).ShouldNot(Succeed())
+			By("Creating Subnet outside of the reserved range")
+			Expect(k8sClient.Create(ctx, newSubnet("outside", "10.1.0.0/16"))).Should(Succeed())
+		})
+	})
+
+	Context("When Network has allowed ranges", func() {
+		It("Should reject top level subnets out of allowed ranges", func() {
+			testNamespaceName := createTestNamespace()
+			ctx := context.Background()
+
+			network := v1alpha1.Network{
+				ObjectMeta: controllerruntime.ObjectMeta{
+					Name:      "allowed",
+					Namespace: testNamespaceName,
+				},
+				Spec: v1alpha1.NetworkSpec{
+					AllowedRanges: []v1alpha1.CIDR{*v1alpha1.CidrMustParse("10.0.0.0/8")},
+				},
+			}
+			Expect(k8sClient.Create(ctx, &network)).Should(Succeed())
+
+			newSubnet := func(name, cidr string) *v1alpha1.Subnet {
+				return &v1alpha1.Subnet{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      name,
+						Namespace: testNamespaceName,
+					},
+					Spec: v1alpha1.SubnetSpec{
+						CIDR:    v1alpha1.CidrMustParse(cidr),
+						Network: corev1.LocalObjectReference{Name: network.Name},
+					},
+				}
+			}
+
+			By("Attempting to create Subnet out of allowed ranges")
+			Expect(k8sClient.Create(ctx, newSubnet("public", "203.0.113.0/24"))).ShouldNot(Succeed())
+			By("Attempting to create Subnet enclosing the allowed range")
+			Expect(k8sClient.Create(ctx, newSubnet("enclosing", "0.0.0.0/0"))).ShouldNot(Succeed())
+			By("Creating Subnet within allowed ranges")
+			Expect(k8sClient.Create(ctx, newSubnet("private", "10.1.0.0/16"))).Should(Succeed())
+			By("Creating Subnet of the family without allowed ranges")
+			Expect(k8sClient.Create(ctx, newSubnet("ipv6", "fd00::/64"))).Should(Succeed())
+		})
+	})
+
+	Context("When Network rejects special-purpose blocks", func() {
+		It("Should reject top level subnets and IPs overlapping them", func() {
+			testNamespaceName := createTestNamespace()
+			ctx := context.Background()
+
+			newSubnet := func(name, cidr string) *v1alpha1.Subnet {
+				return &v1alpha1.Subnet{
+					ObjectMeta: controllerruntime.ObjectMeta{
+						Name:      name,
+						Namespace: testNamespaceName,
+					},
+					Spec: v1alpha1.SubnetSpec{
+						CIDR:    v1alpha1.CidrMustParse(cidr),
+						Network: corev1.LocalObjectReference{Name: "special"},
+					},
+				}
+			}
+
+			By("Creating Subnet before the Network, so the default policy warns only")
+			Expect(k8sClient.Create(ctx, newSubnet("benchmarking", "198.18.0.0/24"))).Should(Succeed())
+
+			network := v1alpha1.Network{
+				ObjectMeta: controllerruntime.ObjectMeta{
+					Name:      "special",
+					Namespace: testNamespaceName,
+				},
+				Spec: v1alpha1.NetworkSpec{
+					SpecialPurposePolicy: &v1alpha1.SpecialPurposePolicy{
+						Action:     v1alpha1.RejectSpecialPurposeAction,
+						Exceptions: []v1alpha1.CIDR{*v1alpha1.CidrMustParse("100.64.0.0/10")},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, &network)).Should(Succeed())
+
+			By("Attempting to create Subnet of documentation range")
+			Expect(k8sClient.Create(ctx, newSubnet("documentation", "192.0.2.0/24"))).ShouldNot(Succeed())
+			By("Creating Subnet within the exception")
+			Expect(k8sClient.Create(ctx, newSubnet("cgnat", "100.64.0.0/16"))).Should(Succeed())
+
+			By("Attempting to create IP of the benchmarking range")
+			ip := &v1alpha1.IP{
+				ObjectMeta: controllerruntime.ObjectMeta{
+					Name:      "benchmarking",
+					Namespace: testNamespaceName,
+				},
+				Spec: v1alpha1.IPSpec{
+					Subnet: v1alpha1.SubnetReference{Name: "benchmarking"},
+					IP:     v1alpha1.IPMustParse("198.18.0.10"),
+				},
+			}
+			Expect(k8sClient.Create(ctx, ip)).ShouldNot(Succeed())
+		})
+	})
+
+	Context("When Subnet is split", func() {
+		It("Should not allow to change the split while children are in use", func(ctx SpecContext) {
+			testNamespaceName := createTestNamespace()
+
+			parentSubnet := v1alpha1.Subnet{
+				ObjectMeta: controllerruntime.ObjectMeta{
+					Name:      "split-subnet",
+					Namespace: testNamespaceName,
+				},
+				Spec: v1alpha1.SubnetSpec{
+					CIDR: v1alpha1.CidrMustParse("10.0.0.0/22"),
+					Network: corev1.LocalObjectReference{
+						Name: "ng",
+					},
+					Split: &v1alpha1.SubnetSplit{PrefixBits: ptr.To[byte](24)},
+				},
+			}
+			Expect(k8sClient.Create(ctx, &parentSubnet)).To(Succeed())
+
+			By("Child Subnet of the split is created")
+			childSubnet := v1alpha1.Subnet{
+				ObjectMeta: controllerruntime.ObjectMeta{
+					Name:      "split-subnet-0",
+					Namespace: testNamespaceName,
+					Labels: map[string]string{
+						v1alpha1.SplitParentLabel: parentSubnet.Name,
+						v1alpha1.SplitIndexLabel:  "0",
+					},
+				},
+				Spec: v1alpha1.SubnetSpec{
+					PrefixBits: ptr.To[byte](24),
+					ParentSubnet: v1alpha1.SubnetReference{
+						Name: parentSubnet.Name,
+					},
+					Network: corev1.LocalObjectReference{
+						Name: "ng",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, &childSubnet)).To(Succeed())
+
+			By("Split is changed while children are not in use")
+			parentSubnet.Spec.Split = &v1alpha1.SubnetSplit{Count: ptr.To[int32](4)}
+			Expect(k8sClient.Update(ctx, &parentSubnet)).To(Succeed())
+
+			By("IP is reserved in the child Subnet")
+			childIP := v1alpha1.IP{
+				ObjectMeta: controllerruntime.ObjectMeta{
+					Name:      "split-ip",
+					Namespace: testNamespaceName,
+				},
+				Spec: v1alpha1.IPSpec{
+					Subnet: v1alpha1.SubnetReference{
+						Name: childSubnet.Name,
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, &childIP)).To(Succeed())
+			childIP.Status.State = v1alpha1.FinishedIPState
+			Expect(k8sClient.Status().Update(ctx, &childIP)).To(Succeed())
+			Eventually(func() ([]v1alpha1.IP, error) {
+				ips := &v1alpha1.IPList{}
+				err := k8sClient.List(ctx, ips, client.MatchingFields{
+					FinishedChildIPToSubnetIndexKey: client.ObjectKeyFromObject(&childSubnet).String(),
+				})
+				return ips.Items, err
+			}, Timeout, Interval).Should(HaveLen(1))
+
+			By("Split change is refused")
+			parentSubnet.Spec.Split = &v1alpha1.SubnetSplit{Count: ptr.To[int32](2)}
+			Expect(k8sClient.Update(ctx, &parentSubnet)).NotTo(Succeed())
+			parentSubnet.Spec.Split = nil
+			Expect(k8sClient.Update(ctx, &parentSubnet)).NotTo(Succeed())
 		})
 	})
 
@@ -450,7 +803,7 @@ var _ = Describe("Subnet webhook", func() {
 				},
 				Spec: v1alpha1.SubnetSpec{
 					CIDR: childSubnetCidr,
-					ParentSubnet: corev1.LocalObjectReference{
+					ParentSubnet: v1alpha1.SubnetReference{
 						Name: parentSubnet.Name,
 					},
 					Network: corev1.LocalObjectReference{
@@ -479,7 +832,7 @@ var _ = Describe("Subnet webhook", func() {
 			Expect(k8sClient.Status().Update(ctx, &childSubnet)).Should(Succeed())
 			Eventually(func() bool {
 				childSubnetsMatchingFields := client.MatchingFields{
-					FinishedChildSubnetToSubnetIndexKey: parentSubnet.Name,
+					FinishedChildSubnetToSubnetIndexKey: client.ObjectKeyFromObject(&parentSubnet).String(),
 				}
 				subnets := &v1alpha1.SubnetList{}
 				err := k8sClient.List(context.Background(), subnets, client.InNamespace(testNamespaceName), childSubnetsMatchingFields, client.Limit(1))
@@ -528,7 +881,7 @@ var _ = Describe("Subnet webhook", func() {
 				},
 				Spec: v1alpha1.SubnetSpec{
 					CIDR: parentSubnetCidr,
-					ParentSubnet: corev1.LocalObjectReference{
+					ParentSubnet: v1alpha1.SubnetReference{
 						Name: "ps",
 					},
 					Network: corev1.LocalObjectReference{
@@ -564,7 +917,7 @@ var _ = Describe("Subnet webhook", func() {
 					Namespace: testNamespaceName,
 				},
 				Spec: v1alpha1.IPSpec{
-					Subnet: corev1.LocalObjectReference{
+					Subnet: v1alpha1.SubnetReference{
 						Name: parentSubnet.Name,
 					},
 					IP: childIPAddr,
@@ -585,7 +938,7 @@ var _ = Describe("Subnet webhook", func() {
 			Expect(k8sClient.Status().Update(ctx, &childIP)).Should(Succeed())
 			Eventually(func() bool {
 				childIPsMatchingFields := client.MatchingFields{
-					FinishedChildIPToSubnetIndexKey: parentSubnet.Name,
+					FinishedChildIPToSubnetIndexKey: client.ObjectKeyFromObject(&parentSubnet).String(),
 				}
 				ips := &v1alpha1.IPList{}
 				err := k8sClient.List(context.Background(), ips, client.InNamespace(testNamespaceName), childIPsMatchingFields, client.Limit(1))
@@ -0,0 +1,240 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package netconfig renders network configuration of a consumer from its reserved IPs,
+// in cloud-init network-config version 2 or netplan format.
+package netconfig
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	ipamv1alpha1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+const (
+	// CloudInitFormat is cloud-init network-config version 2
+	CloudInitFormat = "cloud-init"
+	// NetplanFormat is netplan configuration, which wraps version 2 configuration into the network key
+	NetplanFormat = "netplan"
+
+	// DefaultInterface is an interface the IPs without InterfaceAnnotation are configured on.
+	DefaultInterface = "eth0"
+)
+
+// Formats are supported output formats.
+var Formats = []string{CloudInitFormat, NetplanFormat}
+
+// Config is a network configuration version 2.
+type Config struct {
+	Version   int                 `json:"version"`
+	Ethernets map[string]Ethernet `json:"ethernets,omitempty"`
+}
+
+// Ethernet is a configuration of an ethernet interface.
+type Ethernet struct {
+	Addresses   []string     `json:"addresses,omitempty"`
+	Routes      []Route      `json:"routes,omitempty"`
+	Nameservers *Nameservers `json:"nameservers,omitempty"`
+	MTU         *int32       `json:"mtu,omitempty"`
+}
+
+// Route is a static route of an interface.
+type Route struct {
+	To     string `json:"to"`
+	Via    string `json:"via,omitempty"`
+	Scope  string `json:"scope,omitempty"`
+	Metric *int32 `json:"metric,omitempty"`
+}
+
+// Nameservers are DNS resolvers and search domains of an interface.
+type Nameservers struct {
+	Addresses []string `json:"addresses,omitempty"`
+	Search    []string `json:"search,omitempty"`
+}
+
+// Options are options of building the configuration.
+type Options struct {
+	// DefaultInterface is an interface the IPs without InterfaceAnnotation are configured on, DefaultInterface if empty
+	DefaultInterface string
+}
+
+// ConsumerIPs lists reserved IPs with the consumer, along with their Subnets.
+// Consumer API version is matched only if it is set. IPs are looked up in all namespaces if the namespace is empty.
+func ConsumerIPs(ctx context.Context, cl client.Client, namespace string, consumer *ipamv1alpha1.ResourceReference) (
+	[]ipamv1alpha1.IP, []ipamv1alpha1.Subnet, error) {
+	ipList := &ipamv1alpha1.IPList{}
+	if err := cl.List(ctx, ipList, client.InNamespace(namespace)); err != nil {
+		return nil, nil, fmt.Errorf("couldn't list ips: %w", err)
+	}
+
+	var ips []ipamv1alpha1.IP
+	var subnets []ipamv1alpha1.Subnet
+	seen := map[types.NamespacedName]struct{}{}
+	for _, ip := range ipList.Items {
+		if !consumerMatches(ip.Spec.Consumer, consumer) {
+			continue
+		}
+		ips = append(ips, ip)
+
+		name := ip.Spec.Subnet.NamespacedName(ip.Namespace)
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		subnet := ipamv1alpha1.Subnet{}
+		if err := cl.Get(ctx, name, &subnet); err != nil {
+			return nil, nil, fmt.Errorf("couldn't get subnet %s of ip %s/%s: %w", name, ip.Namespace, ip.Name, err)
+		}
+		subnets = append(subnets, subnet)
+	}
+	return ips, subnets, nil
+}
+
+func consumerMatches(ref, consumer *ipamv1alpha1.ResourceReference) bool {
+	if ref == nil || consumer == nil {
+		return false
+	}
+	return ref.Kind == consumer.Kind && ref.Name == consumer.Name &&
+		(consumer.APIVersion == "" || ref.APIVersion == consumer.APIVersion)
+}
+
+// Build builds the configuration from the IPs, grouped by their interfaces.
+// Addresses get the prefix length of the network configuration of the IP, or of its Subnet, if the IP has none.
+// Subnet gateways are configured as default routes.
+func Build(ips []ipamv1alpha1.IP, subnets []ipamv1alpha1.Subnet, opts Options) (*Config, error) {
+	defaultInterface := opts.DefaultInterface
+	if defaultInterface == "" {
+		defaultInterface = DefaultInterface
+	}
+
+	ips = slices.Clone(ips)
+	for _, ip := range ips {
+		if ip.Status.State != ipamv1alpha1.FinishedIPState || ip.Status.Reserved == nil {
+			return nil, fmt.Errorf("ip %s/%s is not reserved", ip.Namespace, ip.Name)
+		}
+	}
+	// IPv4 addresses go first, so the configuration does not depend on the order IPs are listed in
+	slices.SortFunc(ips, func(a, b ipamv1alpha1.IP) int {
+		return a.Status.Reserved.Net.Compare(b.Status.Reserved.Net)
+	})
+
+	config := &Config{Version: 2, Ethernets: map[string]Ethernet{}}
+	for _, ip := range ips {
+		networkConfig, err := ipNetworkConfig(&ip, subnets)
+		if err != nil {
+			return nil, err
+		}
+
+		name := defaultInterface
+		if value := ip.Annotations[ipamv1alpha1.InterfaceAnnotation]; value != "" {
+			name = value
+		}
+		ethernet := config.Ethernets[name]
+		addEthernetConfig(&ethernet, ip.Status.Reserved.Net, networkConfig)
+		config.Ethernets[name] = ethernet
+	}
+	return config, nil
+}
+
+func ipNetworkConfig(ip *ipamv1alpha1.IP, subnets []ipamv1alpha1.Subnet) (*ipamv1alpha1.NetworkConfig, error) {
+	if ip.Status.NetworkConfig != nil {
+		return ip.Status.NetworkConfig, nil
+	}
+	name := ip.Spec.Subnet.NamespacedName(ip.Namespace)
+	for _, subnet := range subnets {
+		if subnet.Namespace == name.Namespace && subnet.Name == name.Name && subnet.Status.Reserved != nil {
+			return &ipamv1alpha1.NetworkConfig{PrefixLength: subnet.Status.Reserved.MaskOnes()}, nil
+		}
+	}
+	return nil, fmt.Errorf("subnet %s of ip %s/%s is not reserved", name, ip.Namespace, ip.Name)
+}
+
+func addEthernetConfig(ethernet *Ethernet, address netip.Addr, config *ipamv1alpha1.NetworkConfig) {
+	ethernet.Addresses = append(ethernet.Addresses, netip.PrefixFrom(address, int(config.PrefixLength)).String())
+
+	if config.Gateway != nil {
+		defaultRoute := netip.PrefixFrom(netip.IPv4Unspecified(), 0)
+		if config.Gateway.Net.Is6() {
+			defaultRoute = netip.PrefixFrom(netip.IPv6Unspecified(), 0)
+		}
+		addRoute(ethernet, Route{To: defaultRoute.String(), Via: config.Gateway.String()})
+	}
+	for _, subnetRoute := range config.Routes {
+		if subnetRoute.Destination == nil {
+			continue
+		}
+		route := Route{To: subnetRoute.Destination.String(), Metric: subnetRoute.Metric}
+		switch {
+		case subnetRoute.Gateway != nil:
+			route.Via = subnetRoute.Gateway.String()
+		case config.Gateway != nil:
+			route.Via = config.Gateway.String()
+		default:
+			route.Scope = "link"
+		}
+		addRoute(ethernet, route)
+	}
+
+	if len(config.DNSServers) > 0 || len(config.SearchDomains) > 0 {
+		if ethernet.Nameservers == nil {
+			ethernet.Nameservers = &Nameservers{}
+		}
+		for _, server := range config.DNSServers {
+			ethernet.Nameservers.Addresses = appendUnique(ethernet.Nameservers.Addresses, server.String())
+		}
+		for _, domain := range config.SearchDomains {
+			ethernet.Nameservers.Search = appendUnique(ethernet.Nameservers.Search, domain)
+		}
+	}
+
+	if config.MTU != nil && ethernet.MTU == nil {
+		mtu := *config.MTU
+		ethernet.MTU = &mtu
+	}
+}
+
+// addRoute adds the route unless a route to the same destination is already there.
+func addRoute(ethernet *Ethernet, route Route) {
+	if slices.ContainsFunc(ethernet.Routes, func(r Route) bool { return r.To == route.To }) {
+		return
+	}
+	ethernet.Routes = append(ethernet.Routes, route)
+}
+
+func appendUnique(list []string, item string) []string {
+	if slices.Contains(list, item) {
+		return list
+	}
+	return append(list, item)
+}
+
+// Render writes the configuration in the format.
+func Render(w io.Writer, config *Config, format string) error {
+	var out any
+	switch format {
+	case CloudInitFormat, "":
+		out = config
+	case NetplanFormat:
+		out = struct {
+			Network *Config `json:"network"`
+		}{Network: config}
+	default:
+		return fmt.Errorf("unknown format %s, expected any of %s", format, strings.Join(Formats, ", "))
+	}
+
+	data, err := yaml.Marshal(out)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
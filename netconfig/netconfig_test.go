@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package netconfig
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ipamv1alpha1 "github.com/ironcore-dev/ipam/api/ipam/v1alpha1"
+)
+
+var _ = Describe("Network config rendering", func() {
+	var (
+		ips     []ipamv1alpha1.IP
+		subnets []ipamv1alpha1.Subnet
+	)
+
+	newIP := func(name, subnet, address string, config *ipamv1alpha1.NetworkConfig) ipamv1alpha1.IP {
+		return ipamv1alpha1.IP{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: ipamv1alpha1.IPSpec{
+				Subnet:   ipamv1alpha1.SubnetReference{Name: subnet},
+				Consumer: &ipamv1alpha1.ResourceReference{Kind: "Machine", Name: "m1"},
+			},
+			Status: ipamv1alpha1.IPStatus{
+				State:         ipamv1alpha1.FinishedIPState,
+				Reserved:      ipamv1alpha1.IPMustParse(address),
+				NetworkConfig: config,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		mtu := int32(9000)
+		metric := int32(100)
+		ips = []ipamv1alpha1.IP{
+			newIP("v6", "v6", "fd00::10", &ipamv1alpha1.NetworkConfig{
+				PrefixLength: 64,
+				Gateway:      ipamv1alpha1.IPMustParse("fd00::1"),
+				DNSServers:   []ipamv1alpha1.IPAddr{*ipamv1alpha1.IPMustParse("fd00::53")},
+			}),
+			newIP("v4", "v4", "10.0.0.10", &ipamv1alpha1.NetworkConfig{
+				PrefixLength:  16,
+				Gateway:       ipamv1alpha1.IPMustParse("10.0.0.1"),
+				DNSServers:    []ipamv1alpha1.IPAddr{*ipamv1alpha1.IPMustParse("10.0.0.53")},
+				SearchDomains: []string{"example.com"},
+				Routes: []ipamv1alpha1.SubnetRoute{{
+					Destination: ipamv1alpha1.CidrMustParse("192.168.0.0/16"),
+					Gateway:     ipamv1alpha1.IPMustParse("10.0.0.254"),
+					Metric:      &metric,
+				}},
+				MTU: &mtu,
+			}),
+			newIP("storage", "storage", "10.1.0.10", nil),
+		}
+		ips[2].Annotations = map[string]string{ipamv1alpha1.InterfaceAnnotation: "eth1"}
+
+		storage := ipamv1alpha1.Subnet{ObjectMeta: metav1.ObjectMeta{Name: "storage", Namespace: "default"}}
+		storage.FillStatusFromCidr(ipamv1alpha1.CidrMustParse("10.1.0.0/24"))
+		subnets = []ipamv1alpha1.Subnet{storage}
+	})
+
+	DescribeTable("Should render the configuration of all IPs in the format",
+		func(format, golden string) {
+			config, err := Build(ips, subnets, Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			out := &bytes.Buffer{}
+			Expect(Render(out, config, format)).To(Succeed())
+
+			path := filepath.Join("testdata", golden)
+			if *update {
+				Expect(os.WriteFile(path, out.Bytes(), 0644)).To(Succeed())
+			}
+			expected, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out.String()).To(Equal(string(expected)))
+		},
+		Entry("cloud-init", CloudInitFormat, "cloud-init.yaml"),
+		Entry("netplan", NetplanFormat, "netplan.yaml"),
+	)
+
+	It("Should fail if an IP is not reserved", func() {
+		ips[0].Status = ipamv1alpha1.IPStatus{State: ipamv1alpha1.ProcessingIPState}
+		_, err := Build(ips, subnets, Options{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should fail on unknown format", func() {
+		Expect(Render(&bytes.Buffer{}, &Config{Version: 2}, "ifupdown")).NotTo(Succeed())
+	})
+
+	It("Should match consumers with and without API version", func() {
+		ref := &ipamv1alpha1.ResourceReference{APIVersion: "metal.ironcore.dev/v1alpha1", Kind: "Machine", Name: "m1"}
+		Expect(consumerMatches(ref, &ipamv1alpha1.ResourceReference{Kind: "Machine", Name: "m1"})).To(BeTrue())
+		Expect(consumerMatches(ref, ref)).To(BeTrue())
+		Expect(consumerMatches(ref, &ipamv1alpha1.ResourceReference{APIVersion: "v1", Kind: "Machine", Name: "m1"})).To(BeFalse())
+		Expect(consumerMatches(nil, ref)).To(BeFalse())
+	})
+})